@@ -39,7 +39,7 @@ func (c *Conn) PrepareContext(_ context.Context, query string) (driver.Stmt, err
 
 // Close implements driver.Conn/Close.
 func (c *Conn) Close() error {
-	return nil
+	return c.restClient.Close()
 }
 
 // Begin implements driver.Conn/Begin.
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"net"
-	"strconv"
 	"strings"
 	"time"
 
@@ -46,10 +45,12 @@ func _myMacAddr(ip string) (net.HardwareAddr, error) {
 }
 
 // _valuesToNamedValues transforms a []driver.Value to []driver.NamedValue.
+//
+// Since v1.2.0, the values are positional (i.e. Name is empty) and Ordinal is 1-based, as database/sql does.
 func _valuesToNamedValues(values []driver.Value) []driver.NamedValue {
 	result := make([]driver.NamedValue, len(values))
 	for i, v := range values {
-		result[i] = driver.NamedValue{Name: "$" + strconv.Itoa(i+1), Ordinal: i, Value: v}
+		result[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
 	}
 	return result
 }
@@ -0,0 +1,370 @@
+package gocosmos_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// _fakeCosmos is an in-memory fake of the Cosmos DB REST API serving collections partitioned by /pk.
+// Queries are not evaluated: all documents of the collection are returned, paged by x-ms-max-item-count, except that
+// the parameter @__id (used by the driver to re-check a single document) restricts the result to that document.
+type _fakeCosmos struct {
+	server      *httptest.Server
+	mutex       sync.Mutex
+	colls       map[string]map[string]map[string]interface{} // collection path -> id -> document
+	dropped     map[string]bool                              // paths of the collections that no longer exist
+	etagSeq     int
+	queries     []string                              // texts of the queries received
+	queryPages  int                                   // number of query result pages served
+	collGets    int                                   // number of "get collection" requests received
+	queryPlans  int                                   // number of query plan requests received
+	pkrangeGets int                                   // number of "get partition key ranges" requests received
+	planInfo    map[string]interface{}                // if not nil, the "queryInfo" of the query plans served
+	numPkranges int                                   // number of partition key ranges of the collections, 1 if not set
+	rangeQuery  func(pkRangeId string) []interface{}  // if not nil, serves the (single page) result of queries against a partition key range
+	requests    []string                              // "<method> <collection path>[/<doc-id>]" of the document requests received
+	beforeWrite func(id string)                       // if not nil, called before a document is replaced/patched/deleted, e.g. to simulate a concurrent writer
+	recheck     func(doc map[string]interface{}) bool // if not nil, evaluates the predicate of re-check queries (see query)
+	// if not empty, PATCH requests fail with "400 Bad Request" and this message, regardless of the operations
+	patchBadRequest string
+	// if not empty, the partition key path of the collections (instead of /pk), point reads must then supply its value
+	pkPath string
+	// if not nil, restricts the result of queries to the documents it accepts, given the query parameters by name
+	queryFilter func(doc map[string]interface{}, params map[string]interface{}) bool
+}
+
+func _newFakeCosmos() *_fakeCosmos {
+	fake := &_fakeCosmos{colls: make(map[string]map[string]map[string]interface{}), dropped: make(map[string]bool)}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	return fake
+}
+
+// openDb opens a database/sql connection to the fake server, using "mydb" as the default database.
+func (fake *_fakeCosmos) openDb(t *testing.T, testName string) *sql.DB {
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+fake.server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/sql.Open", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// put stores a document into the collection (e.g. "/dbs/mydb/colls/mytable") and assigns it a new _etag.
+func (fake *_fakeCosmos) put(collPath string, doc map[string]interface{}) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.putLocked(collPath, doc)
+}
+
+func (fake *_fakeCosmos) putLocked(collPath string, doc map[string]interface{}) map[string]interface{} {
+	if fake.colls[collPath] == nil {
+		fake.colls[collPath] = make(map[string]map[string]interface{})
+	}
+	fake.etagSeq++
+	doc["_etag"] = fmt.Sprintf(`"%d"`, fake.etagSeq)
+	fake.colls[collPath][doc["id"].(string)] = doc
+	return doc
+}
+
+// get returns a copy of the document, nil if not found.
+func (fake *_fakeCosmos) get(collPath, id string) map[string]interface{} {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	doc, ok := fake.colls[collPath][id]
+	if !ok {
+		return nil
+	}
+	js, _ := json.Marshal(doc)
+	var result map[string]interface{}
+	_ = json.Unmarshal(js, &result)
+	return result
+}
+
+// drop removes the collection: subsequent requests to the collection fail with 404.
+func (fake *_fakeCosmos) drop(collPath string) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	delete(fake.colls, collPath)
+	fake.dropped[collPath] = true
+}
+
+func (fake *_fakeCosmos) reset() {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.queries, fake.requests, fake.queryPages, fake.collGets, fake.queryPlans, fake.pkrangeGets = nil, nil, 0, 0, 0, 0
+}
+
+func _writeFakeResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.WriteHeader(status)
+	js, _ := json.Marshal(body)
+	_, _ = w.Write(js)
+}
+
+func _writeFakeError(w http.ResponseWriter, status int, code, msg string) {
+	_writeFakeResponse(w, status, map[string]interface{}{"code": code, "message": msg})
+}
+
+func (fake *_fakeCosmos) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/" {
+		_, _ = w.Write([]byte(`{"id":"myaccount"}`))
+		return
+	}
+	data, _ := io.ReadAll(r.Body)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "dbs" || parts[2] != "colls" {
+		_writeFakeError(w, 404, "NotFound", "ResourceType: Collection")
+		return
+	}
+	collPath := "/" + strings.Join(parts[:4], "/")
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	if fake.dropped[collPath] {
+		_writeFakeError(w, 404, "NotFound", "Resource Not Found. ResourceType: Collection")
+		return
+	}
+	switch {
+	case len(parts) == 4 && r.Method == "GET":
+		fake.collGets++
+		pkPath := fake.pkPath
+		if pkPath == "" {
+			pkPath = "/pk"
+		}
+		_writeFakeResponse(w, 200, map[string]interface{}{"id": parts[3], "_rid": "rid",
+			"partitionKey": map[string]interface{}{"paths": []string{pkPath}, "kind": "Hash"}})
+	case len(parts) == 5 && parts[4] == "pkranges":
+		fake.pkrangeGets++
+		pkranges := []map[string]interface{}{{"id": "0", "minInclusive": "", "maxExclusive": "FF"}}
+		for i := 1; i < fake.numPkranges; i++ {
+			pkranges[i-1]["maxExclusive"] = fmt.Sprintf("%02X", i*0xFF/fake.numPkranges)
+			pkranges = append(pkranges, map[string]interface{}{"id": strconv.Itoa(i), "minInclusive": pkranges[i-1]["maxExclusive"], "maxExclusive": "FF"})
+		}
+		_writeFakeResponse(w, 200, map[string]interface{}{"_rid": "rid", "_count": len(pkranges), "PartitionKeyRanges": pkranges})
+	case len(parts) == 5 && parts[4] == "docs" && r.Header.Get("x-ms-cosmos-is-query-plan-request") != "":
+		fake.queryPlans++
+		queryInfo := fake.planInfo
+		if queryInfo == nil {
+			queryInfo = map[string]interface{}{"distinctType": "None"}
+		}
+		_writeFakeResponse(w, 200, map[string]interface{}{"partitionedQueryExecutionInfoVersion": 2,
+			"queryInfo": queryInfo, "queryRanges": []interface{}{}})
+	case len(parts) == 5 && parts[4] == "docs" && r.Header.Get("x-ms-documentdb-isquery") != "":
+		fake.query(w, r, collPath, data)
+	case len(parts) == 5 && parts[4] == "docs" && r.Method == "POST":
+		fake.requests = append(fake.requests, "POST "+collPath)
+		var doc map[string]interface{}
+		_ = json.Unmarshal(data, &doc)
+		if _, ok := fake.colls[collPath][fmt.Sprintf("%s", doc["id"])]; ok && r.Header.Get("x-ms-documentdb-is-upsert") == "" {
+			_writeFakeError(w, 409, "Conflict", "Entity with the specified id already exists in the system.")
+			return
+		}
+		_writeFakeResponse(w, 201, fake.putLocked(collPath, doc))
+	case len(parts) == 6 && parts[4] == "docs":
+		fake.requests = append(fake.requests, r.Method+" "+collPath+"/"+parts[5])
+		fake.serveDoc(w, r, collPath, parts[5], data)
+	default:
+		_writeFakeError(w, 400, "BadRequest", "unsupported request")
+	}
+}
+
+func (fake *_fakeCosmos) query(w http.ResponseWriter, r *http.Request, collPath string, data []byte) {
+	var body map[string]interface{}
+	_ = json.Unmarshal(data, &body)
+	query, _ := body["query"].(string)
+	if r.Header.Get("x-ms-continuation") == "" {
+		fake.queries = append(fake.queries, query)
+	}
+	fake.queryPages++
+	if pkRangeId := r.Header.Get("x-ms-documentdb-partitionkeyrangeid"); fake.rangeQuery != nil && pkRangeId != "" {
+		docs := fake.rangeQuery(pkRangeId)
+		_writeFakeResponse(w, 200, map[string]interface{}{"_rid": "rid", "_count": len(docs), "Documents": docs})
+		return
+	}
+	onlyId, filtered := "", false
+	params, _ := body["parameters"].([]interface{})
+	paramsByName := make(map[string]interface{}, len(params))
+	for _, param := range params {
+		p, _ := param.(map[string]interface{})
+		paramsByName[fmt.Sprintf("%s", p["name"])] = p["value"]
+		if p["name"] == "@__id" {
+			onlyId, filtered = fmt.Sprintf("%s", p["value"]), true
+		}
+	}
+	ids := make([]string, 0)
+	for id, doc := range fake.colls[collPath] {
+		if fake.queryFilter != nil && !fake.queryFilter(doc, paramsByName) {
+			continue
+		}
+		if !filtered || (id == onlyId && (fake.recheck == nil || fake.recheck(doc))) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	offset, _ := strconv.Atoi(r.Header.Get("x-ms-continuation"))
+	end := len(ids)
+	if pageSize, _ := strconv.Atoi(r.Header.Get("x-ms-max-item-count")); pageSize > 0 && offset+pageSize < end {
+		end = offset + pageSize
+		w.Header().Set("x-ms-continuation", strconv.Itoa(end))
+	}
+	docs := make([]map[string]interface{}, 0)
+	for _, id := range ids[offset:end] {
+		doc := fake.colls[collPath][id]
+		if strings.HasPrefix(query, "SELECT c.id, c._etag") {
+			// projection built by the driver to fetch only id, _etag and partition key values
+			doc = map[string]interface{}{"id": doc["id"], "_etag": doc["_etag"], "_pk0": doc["pk"]}
+		}
+		docs = append(docs, doc)
+	}
+	_writeFakeResponse(w, 200, map[string]interface{}{"_rid": "rid", "_count": len(docs), "Documents": docs})
+}
+
+func (fake *_fakeCosmos) serveDoc(w http.ResponseWriter, r *http.Request, collPath, id string, data []byte) {
+	doc, ok := fake.colls[collPath][id]
+	if !ok {
+		_writeFakeError(w, 404, "NotFound", "Entity with the specified id does not exist in the system. ResourceType: Document")
+		return
+	}
+	if r.Method == "GET" {
+		if fake.pkPath != "" {
+			if pk, _ := json.Marshal([]interface{}{doc[strings.TrimPrefix(fake.pkPath, "/")]}); r.Header.Get("x-ms-documentdb-partitionkey") != string(pk) {
+				_writeFakeError(w, 404, "NotFound", "Entity with the specified id does not exist in the system. ResourceType: Document")
+				return
+			}
+		}
+		_writeFakeResponse(w, 200, doc)
+		return
+	}
+	if fake.beforeWrite != nil {
+		fake.mutex.Unlock()
+		fake.beforeWrite(id)
+		fake.mutex.Lock()
+		doc = fake.colls[collPath][id]
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != doc["_etag"] {
+		_writeFakeError(w, 412, "PreconditionFailed", "Operation cannot be performed because one of the specified precondition is not met.")
+		return
+	}
+	switch r.Method {
+	case "DELETE":
+		delete(fake.colls[collPath], id)
+		w.WriteHeader(204)
+	case "PUT":
+		var newDoc map[string]interface{}
+		_ = json.Unmarshal(data, &newDoc)
+		_writeFakeResponse(w, 200, fake.putLocked(collPath, newDoc))
+	case "PATCH":
+		var body struct {
+			Operations []struct {
+				Op, Path string
+				Value    interface{}
+			} `json:"operations"`
+		}
+		_ = json.Unmarshal(data, &body)
+		js, _ := json.Marshal(doc)
+		var newDoc map[string]interface{}
+		_ = json.Unmarshal(js, &newDoc)
+		if fake.patchBadRequest != "" {
+			_writeFakeError(w, 400, "BadRequest", fake.patchBadRequest)
+			return
+		}
+		for i, op := range body.Operations {
+			if err := _applyFakePatch(newDoc, op.Op, op.Path, op.Value); err != nil {
+				_writeFakeError(w, 400, "BadRequest", fmt.Sprintf("For Operation(%d): %s", i+1, err))
+				return
+			}
+		}
+		_writeFakeResponse(w, 200, fake.putLocked(collPath, newDoc))
+	default:
+		_writeFakeError(w, 400, "BadRequest", "unsupported request")
+	}
+}
+
+// _applyFakePatch applies a patch operation the way Cosmos DB does: the parent of the target path must exist,
+// "replace" and "remove" require the target to exist and "add" with "/-" appends to an existing array.
+func _applyFakePatch(doc map[string]interface{}, op, path string, value interface{}) error {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	var parent interface{} = doc
+	for _, seg := range segments[:len(segments)-1] {
+		var ok bool
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			parent, ok = p[seg]
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			ok = err == nil && i >= 0 && i < len(p)
+			if ok {
+				parent = p[i]
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s Operation can only create a child object of an existing node(array or object).", op)
+		}
+	}
+	last := segments[len(segments)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		current, exists := p[last]
+		switch op {
+		case "set":
+			p[last] = value
+		case "replace":
+			if !exists {
+				return fmt.Errorf("%s Operation can only be applied on an existing path.", op)
+			}
+			p[last] = value
+		case "remove":
+			if !exists {
+				return fmt.Errorf("%s Operation can only be applied on an existing path.", op)
+			}
+			delete(p, last)
+		case "incr":
+			c, _ := current.(float64)
+			p[last] = c + value.(float64)
+		default:
+			return fmt.Errorf("path %s: unsupported operation %s", path, op)
+		}
+	case []interface{}:
+		if last == "-" && op == "add" {
+			// the array is appended in place of its parent
+			return _appendFakeArray(doc, segments[:len(segments)-1], value)
+		}
+		i, err := strconv.Atoi(last)
+		if err != nil || (op != "set" && op != "replace") {
+			return fmt.Errorf("path %s: invalid array operation %s", path, op)
+		}
+		if i < 0 || i >= len(p) {
+			return fmt.Errorf("Index %d to operation %s at path %s is out of bounds.", i, op, path)
+		}
+		p[i] = value
+	default:
+		return fmt.Errorf("path %s: parent is not an object or array", path)
+	}
+	return nil
+}
+
+func _appendFakeArray(doc map[string]interface{}, segments []string, value interface{}) error {
+	var container interface{} = doc
+	for _, seg := range segments[:len(segments)-1] {
+		m, ok := container.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unsupported path")
+		}
+		container = m[seg]
+	}
+	m, ok := container.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unsupported path")
+	}
+	arr, _ := m[segments[len(segments)-1]].([]interface{})
+	m[segments[len(segments)-1]] = append(arr, value)
+	return nil
+}
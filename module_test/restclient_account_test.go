@@ -0,0 +1,118 @@
+package gocosmos_test
+
+import (
+	"github.com/btnguyen2k/gocosmos"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRestClient_GetDatabaseAccount(t *testing.T) {
+	name := "TestRestClient_GetDatabaseAccount"
+	client := _newRestClient(t, name)
+
+	result := client.GetDatabaseAccount()
+	if result.Error() != nil {
+		t.Fatalf("%s failed: %s", name, result.Error())
+	}
+	if result.Id == "" || len(result.WritableLocations) == 0 || len(result.ReadableLocations) == 0 {
+		t.Fatalf("%s failed: unexpected account info %#v", name, result.DatabaseAccountInfo)
+	}
+	if result.ConsistencyPolicy.DefaultConsistencyLevel == "" {
+		t.Fatalf("%s failed: default consistency level is empty", name)
+	}
+	if _, err := result.QueryEngineConfig(); err != nil {
+		t.Fatalf("%s failed: %s", name+"/QueryEngineConfig", err)
+	}
+}
+
+func TestRestClient_ConsistencyLevelValidation(t *testing.T) {
+	name := "TestRestClient_ConsistencyLevelValidation"
+	numDocRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"id":"myaccount","enableMultipleWriteLocations":false,` +
+				`"userConsistencyPolicy":{"defaultConsistencyLevel":"Session"},` +
+				`"queryEngineConfiguration":"{\"maxSqlQueryInputLength\":262144,\"maxJoinsPerSqlQuery\":5}"}`))
+			return
+		}
+		numDocRequests++
+		_, _ = w.Write([]byte(`{"id":"doc1"}`))
+	}))
+	defer server.Close()
+
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+
+	account := client.GetDatabaseAccount()
+	if account.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetDatabaseAccount", account.Error())
+	}
+	if account.ConsistencyPolicy.DefaultConsistencyLevel != "Session" || account.EnableMultipleWriteLocations {
+		t.Fatalf("%s failed: unexpected account info %#v", name+"/GetDatabaseAccount", account.DatabaseAccountInfo)
+	}
+	if cfg, err := account.QueryEngineConfig(); err != nil || cfg["maxJoinsPerSqlQuery"] != 5.0 {
+		t.Fatalf("%s failed: unexpected query engine config %#v / %s", name+"/QueryEngineConfig", cfg, err)
+	}
+	// modifying the returned account info must not affect the cached one
+	account.ConsistencyPolicy.DefaultConsistencyLevel = "Strong"
+
+	docReq := gocosmos.DocReq{DbName: "db", CollName: "coll", DocId: "doc1", PartitionKeyValues: []interface{}{"doc1"}}
+	for _, level := range []string{"", "Session", "ConsistentPrefix", "Eventual", "Unknown"} {
+		docReq.ConsistencyLevel = level
+		if result := client.GetDocument(docReq); result.Error() != nil {
+			t.Fatalf("%s failed: consistency level %#v must be accepted, but received %s", name, level, result.Error())
+		}
+	}
+	for _, level := range []string{"Strong", "BoundedStaleness"} {
+		docReq.ConsistencyLevel = level
+		if result := client.GetDocument(docReq); result.Error() == nil {
+			t.Fatalf("%s failed: consistency level %#v must be rejected", name, level)
+		}
+	}
+	if numDocRequests != 5 {
+		t.Fatalf("%s failed: expected 5 requests to be sent but received %d", name, numDocRequests)
+	}
+}
+
+func TestRestClient_ConsistencyLevelValidationNotBlocking(t *testing.T) {
+	name := "TestRestClient_ConsistencyLevelValidationNotBlocking"
+	var numDocRequests int32
+	accountFetched := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			// the account info is only served once the first document request has completed
+			<-accountFetched
+			_, _ = w.Write([]byte(`{"id":"myaccount","userConsistencyPolicy":{"defaultConsistencyLevel":"Session"}}`))
+			return
+		}
+		atomic.AddInt32(&numDocRequests, 1)
+		_, _ = w.Write([]byte(`{"id":"doc1"}`))
+	}))
+	defer server.Close()
+
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	docReq := gocosmos.DocReq{DbName: "db", CollName: "coll", DocId: "doc1", PartitionKeyValues: []interface{}{"doc1"}, ConsistencyLevel: "Strong"}
+	if result := client.GetDocument(docReq); result.Error() != nil {
+		t.Fatalf("%s failed: the first request must be sent without waiting for the account info, but received %s", name, result.Error())
+	}
+	close(accountFetched)
+
+	// once the account info is available, a stronger consistency level is rejected
+	deadline := time.Now().Add(5 * time.Second)
+	for client.GetDocument(docReq).Error() == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s failed: consistency level %#v must be rejected once the account info is available", name, docReq.ConsistencyLevel)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,8 +1,16 @@
 package gocosmos_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/btnguyen2k/gocosmos"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 /*----------------------------------------------------------------------*/
@@ -381,6 +389,129 @@ func TestRestClient_GetCollection(t *testing.T) {
 	}
 }
 
+// _newFakeIndexTransformation starts a fake server which reports the index transformation progress of a collection:
+// the n-th GetCollection call reports progress[n] (the last one is repeated), a negative value means the header is omitted.
+func _newFakeIndexTransformation(progress ...int) (*httptest.Server, *int32) {
+	hits := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(hits, 1)) - 1
+		if n >= len(progress) {
+			n = len(progress) - 1
+		}
+		if r.Header.Get("x-ms-documentdb-populatequotainfo") != "true" {
+			w.WriteHeader(400)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if progress[n] >= 0 {
+			w.Header().Set("x-ms-documentdb-collection-index-transformation-progress", fmt.Sprintf("%d", progress[n]))
+		}
+		_, _ = w.Write([]byte(`{"id":"mytable","_rid":"rid","indexingPolicy":{"indexingMode":"consistent"}}`))
+	}))
+	return server, hits
+}
+
+func TestRestClient_GetCollection_IndexTransformationProgress(t *testing.T) {
+	name := "TestRestClient_GetCollection_IndexTransformationProgress"
+	testData := []struct {
+		name     string
+		progress int
+		expected int
+	}{
+		{name: "in_progress", progress: 42, expected: 42},
+		{name: "completed", progress: 100, expected: 100},
+		{name: "not_reported", progress: -1, expected: -1},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			server, _ := _newFakeIndexTransformation(testCase.progress)
+			defer server.Close()
+			client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+			if err != nil {
+				t.Fatalf("%s failed: %s", name+"/"+testCase.name, err)
+			}
+			result := client.GetCollection("mydb", "mytable")
+			if result.Error() != nil {
+				t.Fatalf("%s failed: %s", name+"/"+testCase.name, result.Error())
+			}
+			if result.Id != "mytable" || result.IndexTransformationProgress != testCase.expected {
+				t.Fatalf("%s failed: <progress> expected %#v but received %#v", name+"/"+testCase.name, testCase.expected, result.IndexTransformationProgress)
+			}
+		})
+	}
+}
+
+func TestRestClient_WaitForIndexTransformation(t *testing.T) {
+	name := "TestRestClient_WaitForIndexTransformation"
+	server, hits := _newFakeIndexTransformation(50, 100)
+	defer server.Close()
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	result := client.WaitForIndexTransformation(context.Background(), "mydb", "mytable")
+	if result.Error() != nil {
+		t.Fatalf("%s failed: %s", name, result.Error())
+	}
+	if result.IndexTransformationProgress != 100 || atomic.LoadInt32(hits) != 2 {
+		t.Fatalf("%s failed: expected progress 100 after 2 polls but received %#v after %#v polls", name, result.IndexTransformationProgress, atomic.LoadInt32(hits))
+	}
+}
+
+func TestRestClient_WaitForIndexTransformation_ContextDone(t *testing.T) {
+	name := "TestRestClient_WaitForIndexTransformation_ContextDone"
+	server, _ := _newFakeIndexTransformation(10)
+	defer server.Close()
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result := client.WaitForIndexTransformation(ctx, "mydb", "mytable")
+	if !errors.Is(result.Error(), context.DeadlineExceeded) {
+		t.Fatalf("%s failed: expected context.DeadlineExceeded but received %#v", name, result.Error())
+	}
+	if result.IndexTransformationProgress != 10 {
+		t.Fatalf("%s failed: <progress> expected %#v but received %#v", name, 10, result.IndexTransformationProgress)
+	}
+}
+
+func TestRestClient_CollectionDefaultTtl(t *testing.T) {
+	name := "TestRestClient_CollectionDefaultTtl"
+	var lastBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastBody = nil
+		_ = json.NewDecoder(r.Body).Decode(&lastBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"mytable","_rid":"rid","defaultTtl":60}`))
+	}))
+	defer server.Close()
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+
+	ttl := 60
+	spec := gocosmos.CollectionSpec{DbName: "mydb", CollName: "mytable", PartitionKeyInfo: map[string]interface{}{"paths": []string{"/id"}, "kind": "Hash"}, DefaultTtl: &ttl}
+	if result := client.CreateCollection(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/CreateCollection", result.Error())
+	} else if lastBody["defaultTtl"] != 60.0 || result.DefaultTtl == nil || *result.DefaultTtl != 60 {
+		t.Fatalf("%s failed: <defaultTtl> sent %#v, received %#v", name+"/CreateCollection", lastBody["defaultTtl"], result.DefaultTtl)
+	}
+	if result := client.ReplaceCollection(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ReplaceCollection", result.Error())
+	} else if lastBody["defaultTtl"] != 60.0 {
+		t.Fatalf("%s failed: <defaultTtl> sent %#v", name+"/ReplaceCollection", lastBody["defaultTtl"])
+	}
+	spec.DefaultTtl = nil
+	if result := client.ReplaceCollection(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ReplaceCollection", result.Error())
+	} else if _, ok := lastBody["defaultTtl"]; ok {
+		t.Fatalf("%s failed: <defaultTtl> must not be sent, but received %#v", name+"/ReplaceCollection", lastBody["defaultTtl"])
+	}
+}
+
 func TestRestClient_ListCollection(t *testing.T) {
 	name := "TestRestClient_ListCollection"
 	client := _newRestClient(t, name)
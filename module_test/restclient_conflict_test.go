@@ -0,0 +1,37 @@
+package gocosmos_test
+
+import (
+	"github.com/btnguyen2k/gocosmos"
+	"testing"
+)
+
+func TestRestClient_Conflicts(t *testing.T) {
+	name := "TestRestClient_Conflicts"
+	client := _newRestClient(t, name)
+
+	dbname := testDb
+	collname := testTable
+	_ensureDatabase(client, gocosmos.DatabaseSpec{Id: dbname, Ru: 400})
+	defer client.DeleteDatabase(dbname)
+	_ensureCollection(client, gocosmos.CollectionSpec{DbName: dbname, CollName: collname,
+		PartitionKeyInfo:         map[string]interface{}{"paths": []string{"/id"}, "kind": "Hash"},
+		ConflictResolutionPolicy: map[string]interface{}{"mode": "LastWriterWins", "conflictResolutionPath": "/version"},
+	})
+
+	if result := client.GetCollection(dbname, collname); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetCollection", result.Error())
+	} else if result.ConflictResolutionPolicy["conflictResolutionPath"] != "/version" {
+		t.Fatalf("%s failed: unexpected conflict resolution policy %#v", name+"/GetCollection", result.ConflictResolutionPolicy)
+	}
+	if result := client.ListConflicts(dbname, collname); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ListConflicts", result.Error())
+	} else if result.Count != 0 || len(result.Conflicts) != 0 {
+		t.Fatalf("%s failed: expected no conflicts but received %d", name+"/ListConflicts", result.Count)
+	}
+	if result := client.GetConflict(gocosmos.ConflictReq{DbName: dbname, CollName: collname, ConflictId: "not_exists"}); result.StatusCode != 404 {
+		t.Fatalf("%s failed: expected status code 404 but received %d", name+"/GetConflict", result.StatusCode)
+	}
+	if result := client.DeleteConflict(gocosmos.ConflictReq{DbName: dbname, CollName: collname, ConflictId: "not_exists"}); result.StatusCode != 404 {
+		t.Fatalf("%s failed: expected status code 404 but received %d", name+"/DeleteConflict", result.StatusCode)
+	}
+}
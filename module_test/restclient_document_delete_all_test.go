@@ -0,0 +1,57 @@
+package gocosmos_test
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/btnguyen2k/gocosmos"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRestClient_DeleteAllItemsByPartitionKey(t *testing.T) {
+	name := "TestRestClient_DeleteAllItemsByPartitionKey"
+	var method, path, pkHeader, authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"id":"myaccount"}`))
+			return
+		}
+		method, path = r.Method, r.URL.Path
+		pkHeader, authHeader = r.Header.Get("x-ms-documentdb-partitionkey"), r.Header.Get("Authorization")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	if result := client.DeleteAllItemsByPartitionKey("mydb", "mytable", []interface{}{"tenant1"}); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name, result.Error())
+	}
+	if method != "POST" || path != "/dbs/mydb/colls/mytable/operations/partitionkeydelete" {
+		t.Fatalf("%s failed: unexpected request %s %s", name, method, path)
+	}
+	if pkHeader != `["tenant1"]` || authHeader == "" {
+		t.Fatalf("%s failed: unexpected headers {pk: %s / auth: %s}", name, pkHeader, authHeader)
+	}
+
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+	execResult, err := db.Exec(`DELETE ALL FROM mytable2 WHERE app=@1 AND username="\"user1\""`, "app1")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/Exec", err)
+	}
+	if _, err := execResult.RowsAffected(); !errors.Is(err, gocosmos.ErrOperationNotSupported) {
+		t.Fatalf("%s failed: expected ErrOperationNotSupported but received %#v", name+"/Exec", err)
+	}
+	if !strings.HasSuffix(path, "/dbs/mydb/colls/mytable2/operations/partitionkeydelete") || pkHeader != `["app1","user1"]` {
+		t.Fatalf("%s failed: unexpected request {path: %s / pk: %s}", name+"/Exec", path, pkHeader)
+	}
+}
@@ -0,0 +1,165 @@
+package gocosmos_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/btnguyen2k/gocosmos"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type _fakeDocServer struct {
+	server                    *httptest.Server
+	method, path, contentType string
+	pkHeader, ifMatchHeader   string
+	preTriggers, postTriggers string
+	body                      map[string]interface{}
+	status                    int // status code to respond with, 0 means 200
+}
+
+func _newFakeDocServer() *_fakeDocServer {
+	fake := &_fakeDocServer{}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"id":"myaccount"}`))
+			return
+		}
+		fake.method, fake.path, fake.contentType = r.Method, r.URL.Path, r.Header.Get("Content-Type")
+		fake.pkHeader, fake.ifMatchHeader = r.Header.Get("x-ms-documentdb-partitionkey"), r.Header.Get("If-Match")
+		fake.preTriggers, fake.postTriggers = r.Header.Get("x-ms-documentdb-pre-trigger-include"), r.Header.Get("x-ms-documentdb-post-trigger-include")
+		data, _ := io.ReadAll(r.Body)
+		fake.body = nil
+		_ = json.Unmarshal(data, &fake.body)
+		if fake.status != 0 {
+			w.WriteHeader(fake.status)
+			_, _ = w.Write([]byte(`{"code":"PreconditionFailed","message":"Operation cannot be performed because one of the specified precondition is not met."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","address":{"city":"Hanoi"},"_etag":"\"etag\""}`))
+	}))
+	return fake
+}
+
+func TestRestClient_PatchDocument(t *testing.T) {
+	name := "TestRestClient_PatchDocument"
+	fake := _newFakeDocServer()
+	defer fake.server.Close()
+
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+fake.server.URL+";AccountKey="+_testAccountKey)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	req := gocosmos.PatchDocReq{DbName: "mydb", CollName: "mytable", DocId: "1", PartitionKeyValues: []interface{}{"1"}, MatchEtag: `"etag"`,
+		PreTriggerInclude: []string{"pre1", "pre2"}, PostTriggerInclude: []string{"post1"},
+		Operations: []gocosmos.PatchOperation{
+			{Op: "set", Path: "/address/city", Value: "Hanoi"},
+			{Op: "replace", Path: "/tags/0", Value: nil},
+			{Op: "remove", Path: "/obsolete"},
+		}}
+	result := client.PatchDocument(req)
+	if result.Error() != nil {
+		t.Fatalf("%s failed: %s", name, result.Error())
+	}
+	if result.DocInfo.Id() != "1" {
+		t.Fatalf("%s failed: unexpected result %#v", name, result.DocInfo)
+	}
+	if fake.method != "PATCH" || fake.path != "/dbs/mydb/colls/mytable/docs/1" || fake.contentType != "application/json_patch+json" {
+		t.Fatalf("%s failed: unexpected request {method: %s / path: %s / content-type: %s}", name, fake.method, fake.path, fake.contentType)
+	}
+	if fake.pkHeader != `["1"]` || fake.ifMatchHeader != `"etag"` {
+		t.Fatalf("%s failed: unexpected headers {pk: %s / if-match: %s}", name, fake.pkHeader, fake.ifMatchHeader)
+	}
+	if fake.preTriggers != "pre1,pre2" || fake.postTriggers != "post1" {
+		t.Fatalf("%s failed: unexpected trigger headers {pre: %s / post: %s}", name, fake.preTriggers, fake.postTriggers)
+	}
+	expectedBody := map[string]interface{}{"operations": []interface{}{
+		map[string]interface{}{"op": "set", "path": "/address/city", "value": "Hanoi"},
+		map[string]interface{}{"op": "replace", "path": "/tags/0", "value": nil},
+		map[string]interface{}{"op": "remove", "path": "/obsolete"},
+	}}
+	if !reflect.DeepEqual(fake.body, expectedBody) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", name, expectedBody, fake.body)
+	}
+
+	req.Operations = make([]gocosmos.PatchOperation, gocosmos.MaxPatchOperations+1)
+	if result := client.PatchDocument(req); result.Error() == nil {
+		t.Fatalf("%s failed: expected error for too many operations", name)
+	}
+}
+
+func TestStmt_NestedFieldPaths(t *testing.T) {
+	name := "TestStmt_NestedFieldPaths"
+	fake := _newFakeDocServer()
+	defer fake.server.Close()
+
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+fake.server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := db.Exec(`INSERT INTO mytable (id, tenant, address.city, tags[0], tags[1]) VALUES (@1, @2, @3, "\"a\"", "\"b\"") WITH PK=/tenant`, "1", "t1", "Hanoi"); err != nil {
+		t.Fatalf("%s failed: %s", name+"/INSERT", err)
+	}
+	expectedDoc := map[string]interface{}{"id": "1", "tenant": "t1", "address": map[string]interface{}{"city": "Hanoi"}, "tags": []interface{}{"a", "b"}}
+	if fake.method != "POST" || !reflect.DeepEqual(fake.body, expectedDoc) || fake.pkHeader != `["t1"]` {
+		t.Fatalf("%s failed: unexpected request {method: %s / body: %#v / pk: %s}", name+"/INSERT", fake.method, fake.body, fake.pkHeader)
+	}
+
+	execResult, err := db.Exec(`UPDATE mytable SET address.zip=@1, tags[0]=null WHERE id=@2 AND tenant="\"t1\""`, "10000", "1")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/UPDATE", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+		t.Fatalf("%s failed: expected 1 affected-row but received %d / %s", name+"/UPDATE", affectedRows, err)
+	}
+	expectedBody := map[string]interface{}{"operations": []interface{}{
+		map[string]interface{}{"op": "set", "path": "/address/zip", "value": "10000"},
+		map[string]interface{}{"op": "set", "path": "/tags/0", "value": nil},
+	}}
+	if fake.method != "PATCH" || fake.path != "/dbs/mydb/colls/mytable/docs/1" || !reflect.DeepEqual(fake.body, expectedBody) {
+		t.Fatalf("%s failed: unexpected request {method: %s / path: %s / body: %#v}", name+"/UPDATE", fake.method, fake.path, fake.body)
+	}
+}
+
+func TestStmtUpdate_PatchOperations(t *testing.T) {
+	name := "TestStmtUpdate_PatchOperations"
+	fake := _newFakeDocServer()
+	defer fake.server.Close()
+
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+fake.server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	query := `UPDATE mytable SET views=views+1, stock=stock-@1, tags=ARRAY_APPEND(tags, @2), note=null REMOVE obsolete WHERE id="\"1\"" AND tenant="\"t1\""`
+	if _, err := db.Exec(query, 3, "new"); err != nil {
+		t.Fatalf("%s failed: %s", name+"/Patch", err)
+	}
+	expectedBody := map[string]interface{}{"operations": []interface{}{
+		map[string]interface{}{"op": "incr", "path": "/views", "value": 1.0},
+		map[string]interface{}{"op": "incr", "path": "/stock", "value": -3.0},
+		map[string]interface{}{"op": "add", "path": "/tags/-", "value": "new"},
+		map[string]interface{}{"op": "set", "path": "/note", "value": nil},
+		map[string]interface{}{"op": "remove", "path": "/obsolete"},
+	}}
+	if fake.method != "PATCH" || !reflect.DeepEqual(fake.body, expectedBody) {
+		t.Fatalf("%s failed: unexpected request {method: %s / body: %#v}", name+"/Patch", fake.method, fake.body)
+	}
+
+	// more than MaxPatchOperations fields: the document is fetched, modified and replaced as a whole
+	query = `UPDATE mytable SET a1=1, a2=2, a3=3, a4=4, a5=5, a6=6, a7=7, a8=8, views=views+2, address.zip="\"10000\"" REMOVE address.city WHERE id="\"1\"" AND tenant="\"t1\""`
+	if _, err := db.Exec(query); err != nil {
+		t.Fatalf("%s failed: %s", name+"/Replace", err)
+	}
+	expectedDoc := map[string]interface{}{"id": "1", "address": map[string]interface{}{"zip": "10000"},
+		"a1": 1.0, "a2": 2.0, "a3": 3.0, "a4": 4.0, "a5": 5.0, "a6": 6.0, "a7": 7.0, "a8": 8.0, "views": 2.0}
+	if fake.method != "PUT" || !reflect.DeepEqual(fake.body, expectedDoc) {
+		t.Fatalf("%s failed: unexpected request {method: %s / body: %#v}", name+"/Replace", fake.method, fake.body)
+	}
+}
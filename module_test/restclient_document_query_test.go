@@ -498,6 +498,42 @@ func _distinctPerPartition(low, high int, dataList []gocosmos.DocInfo, distinctF
 	return result
 }
 
+func TestRestClient_QueryDocuments_Metrics(t *testing.T) {
+	testName := "TestRestClient_QueryDocuments_Metrics"
+	client := _newRestClient(t, testName)
+	dbname := testDb
+	collname := testTable
+	_initDataLargeRU(t, testName, client, dbname, collname, 100)
+
+	query := gocosmos.QueryReq{DbName: dbname, CollName: collname, Query: "SELECT * FROM c WHERE c.grade>=@grade", MaxItemCount: -1,
+		Params: []interface{}{map[string]interface{}{"name": "@grade", "value": 100}}}
+	result := client.QueryDocumentsCrossPartition(query)
+	if result.Error() != nil {
+		t.Fatalf("%s failed: %s", testName, result.Error())
+	}
+	if result.QueryMetrics == nil {
+		t.Fatalf("%s failed: query metrics not populated", testName)
+	}
+	if result.QueryMetrics.OutputDocumentCount != int64(result.Count) {
+		t.Fatalf("%s failed: <output-document-count> expected %#v but received %#v", testName, result.Count, result.QueryMetrics.OutputDocumentCount)
+	}
+	if result.QueryMetrics.RetrievedDocumentCount < int64(result.Count) {
+		t.Fatalf("%s failed: <retrieved-document-count> expected at least %#v but received %#v", testName, result.Count, result.QueryMetrics.RetrievedDocumentCount)
+	}
+	if result.IndexMetrics != nil {
+		t.Fatalf("%s failed: index metrics must not be populated unless requested", testName)
+	}
+
+	query.PopulateIndexMetrics = true
+	result = client.QueryDocumentsCrossPartition(query)
+	if result.Error() != nil {
+		t.Fatalf("%s failed: %s", testName, result.Error())
+	}
+	if result.IndexMetrics == nil {
+		t.Fatalf("%s failed: index metrics not populated", testName)
+	}
+}
+
 /*
 - Simple queries, with or without ORDER BY, (including No-limit/MaxItemCount/OFFSET...LIMIT) should work.
 - SELECT DISTINCT/VALUE, with or without ORDER BY, queries (including No-limit/MaxItemCount/OFFSET...LIMIT) should work.
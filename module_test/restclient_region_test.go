@@ -0,0 +1,138 @@
+package gocosmos_test
+
+import (
+	"fmt"
+	"github.com/btnguyen2k/gocosmos"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const _testAccountKey = "C2y6yDjf5/R+ob0N8A7Cgv30VRDJIWEHLM+4QDU5DE2nQ9nDuVTqobD4b8mGGyPMbIZnqyMsEcaGQy67XIw/Jw=="
+
+type _fakeRegion struct {
+	server *httptest.Server
+	hits   int32
+	status int32 // status code to respond with, 0 means 200
+}
+
+func _newFakeRegion() *_fakeRegion {
+	region := &_fakeRegion{}
+	region.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&region.hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if status := atomic.LoadInt32(&region.status); status != 0 {
+			w.WriteHeader(int(status))
+			_, _ = w.Write([]byte(`{"code":"ServiceUnavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"mydb","_rid":"rid"}`))
+	}))
+	return region
+}
+
+func TestRestClient_PreferredRegions(t *testing.T) {
+	name := "TestRestClient_PreferredRegions"
+	writeRegion, readRegion := _newFakeRegion(), _newFakeRegion()
+	defer writeRegion.server.Close()
+	defer readRegion.server.Close()
+	global := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(500)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"writableLocations":[{"name":"West US","databaseAccountEndpoint":"%s/"}],`+
+			`"readableLocations":[{"name":"West US","databaseAccountEndpoint":"%s/"},{"name":"East US","databaseAccountEndpoint":"%s/"}]}`,
+			writeRegion.server.URL, writeRegion.server.URL, readRegion.server.URL)))
+	}))
+	defer global.Close()
+
+	client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+global.URL+";AccountKey="+_testAccountKey+";PreferredRegions=East US, West US")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+
+	if result := client.GetDatabase("mydb"); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetDatabase", result.Error())
+	}
+	if atomic.LoadInt32(&readRegion.hits) != 1 || atomic.LoadInt32(&writeRegion.hits) != 0 {
+		t.Fatalf("%s failed: read request must be routed to the preferred region", name+"/GetDatabase")
+	}
+
+	if result := client.CreateDatabase(gocosmos.DatabaseSpec{Id: "mydb"}); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/CreateDatabase", result.Error())
+	}
+	if atomic.LoadInt32(&readRegion.hits) != 1 || atomic.LoadInt32(&writeRegion.hits) != 1 {
+		t.Fatalf("%s failed: write request must be routed to the write region", name+"/CreateDatabase")
+	}
+
+	atomic.StoreInt32(&readRegion.status, 503)
+	if result := client.GetDatabase("mydb"); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetDatabase", result.Error())
+	}
+	if atomic.LoadInt32(&readRegion.hits) != 2 || atomic.LoadInt32(&writeRegion.hits) != 2 {
+		t.Fatalf("%s failed: read request must fail over to the next region", name+"/GetDatabase")
+	}
+	if result := client.GetDatabase("mydb"); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetDatabase", result.Error())
+	}
+	if atomic.LoadInt32(&readRegion.hits) != 2 || atomic.LoadInt32(&writeRegion.hits) != 3 {
+		t.Fatalf("%s failed: unavailable region must be skipped", name+"/GetDatabase")
+	}
+}
+
+func TestRestClient_FailoverTransportError(t *testing.T) {
+	name := "TestRestClient_FailoverTransportError"
+	var brokenHits int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the request reaches the server, but the connection is dropped before a response is sent
+		atomic.AddInt32(&brokenHits, 1)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		_ = conn.Close()
+	}))
+	defer broken.Close()
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+	healthy := _newFakeRegion()
+	defer healthy.server.Close()
+
+	newClient := func(firstRegion string) *gocosmos.RestClient {
+		global := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"enableMultipleWriteLocations":true,`+
+				`"writableLocations":[{"name":"West US","databaseAccountEndpoint":"%s/"},{"name":"East US","databaseAccountEndpoint":"%s/"}],`+
+				`"readableLocations":[{"name":"West US","databaseAccountEndpoint":"%s/"},{"name":"East US","databaseAccountEndpoint":"%s/"}]}`,
+				firstRegion, healthy.server.URL, firstRegion, healthy.server.URL)))
+		}))
+		t.Cleanup(global.Close)
+		client, err := gocosmos.NewRestClient(nil, "AccountEndpoint="+global.URL+";AccountKey="+_testAccountKey+";PreferredRegions=West US, East US")
+		if err != nil {
+			t.Fatalf("%s failed: %s", name, err)
+		}
+		return client
+	}
+
+	client := newClient(broken.URL)
+	if result := client.CreateDatabase(gocosmos.DatabaseSpec{Id: "mydb"}); result.Error() == nil {
+		t.Fatalf("%s failed: expected transport error", name+"/CreateDatabase")
+	}
+	if atomic.LoadInt32(&brokenHits) == 0 || atomic.LoadInt32(&healthy.hits) != 0 {
+		t.Fatalf("%s failed: write request that may have reached the server must not be replayed", name+"/CreateDatabase")
+	}
+	if result := client.GetDatabase("mydb"); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetDatabase", result.Error())
+	}
+	if atomic.LoadInt32(&healthy.hits) != 1 {
+		t.Fatalf("%s failed: read request must fail over on transport error", name+"/GetDatabase")
+	}
+
+	client = newClient(refused.URL)
+	if result := client.CreateDatabase(gocosmos.DatabaseSpec{Id: "mydb"}); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/CreateDatabase", result.Error())
+	}
+	if atomic.LoadInt32(&healthy.hits) != 2 {
+		t.Fatalf("%s failed: write request must fail over when the connection is refused", name+"/CreateDatabase")
+	}
+}
@@ -0,0 +1,97 @@
+package gocosmos_test
+
+import (
+	"github.com/btnguyen2k/gocosmos"
+	"testing"
+)
+
+func TestRestClient_Udf(t *testing.T) {
+	name := "TestRestClient_Udf"
+	client := _newRestClient(t, name)
+
+	dbname := testDb
+	collname := testTable
+	_ensureDatabase(client, gocosmos.DatabaseSpec{Id: dbname, Ru: 400})
+	defer client.DeleteDatabase(dbname)
+	_ensureCollection(client, gocosmos.CollectionSpec{DbName: dbname, CollName: collname,
+		PartitionKeyInfo: map[string]interface{}{"paths": []string{"/id"}, "kind": "Hash"}})
+
+	spec := gocosmos.UdfSpec{DbName: dbname, CollName: collname, Id: "udf1", Body: "function(x) { return x * 2; }"}
+	if result := client.CreateUdf(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/CreateUdf", result.Error())
+	} else if result.Id != spec.Id || result.Rid == "" {
+		t.Fatalf("%s failed: unexpected result %#v", name+"/CreateUdf", result.UdfInfo)
+	}
+	if result := client.CreateUdf(spec); result.StatusCode != 409 {
+		t.Fatalf("%s failed: expected status code 409 but received %d", name+"/CreateUdf", result.StatusCode)
+	}
+	spec.Body = "function(x) { return x * 3; }"
+	if result := client.ReplaceUdf(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ReplaceUdf", result.Error())
+	}
+	if result := client.GetUdf(dbname, collname, spec.Id); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetUdf", result.Error())
+	} else if result.Body != spec.Body {
+		t.Fatalf("%s failed: expected body %#v but received %#v", name+"/GetUdf", spec.Body, result.Body)
+	}
+	if result := client.ListUdfs(dbname, collname); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ListUdfs", result.Error())
+	} else if result.Count != 1 || len(result.Udfs) != 1 {
+		t.Fatalf("%s failed: expected 1 UDF but received %d", name+"/ListUdfs", result.Count)
+	}
+	if result := client.DeleteUdf(dbname, collname, spec.Id); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/DeleteUdf", result.Error())
+	}
+	if result := client.GetUdf(dbname, collname, spec.Id); result.StatusCode != 404 {
+		t.Fatalf("%s failed: expected status code 404 but received %d", name+"/GetUdf", result.StatusCode)
+	}
+}
+
+func TestRestClient_Trigger(t *testing.T) {
+	name := "TestRestClient_Trigger"
+	client := _newRestClient(t, name)
+
+	dbname := testDb
+	collname := testTable
+	_ensureDatabase(client, gocosmos.DatabaseSpec{Id: dbname, Ru: 400})
+	defer client.DeleteDatabase(dbname)
+	_ensureCollection(client, gocosmos.CollectionSpec{DbName: dbname, CollName: collname,
+		PartitionKeyInfo: map[string]interface{}{"paths": []string{"/id"}, "kind": "Hash"}})
+
+	spec := gocosmos.TriggerSpec{DbName: dbname, CollName: collname, Id: "addTimestamp", TriggerType: "Pre", TriggerOperation: "Create",
+		Body: `function addTimestamp() { var req = getContext().getRequest(); var doc = req.getBody(); doc.triggered = true; req.setBody(doc); }`}
+	if result := client.CreateTrigger(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/CreateTrigger", result.Error())
+	} else if result.TriggerType != "Pre" || result.TriggerOperation != "Create" {
+		t.Fatalf("%s failed: unexpected result %#v", name+"/CreateTrigger", result.TriggerInfo)
+	}
+	spec.TriggerOperation = "All"
+	if result := client.ReplaceTrigger(spec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ReplaceTrigger", result.Error())
+	}
+	if result := client.GetTrigger(dbname, collname, spec.Id); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/GetTrigger", result.Error())
+	} else if result.TriggerOperation != "All" {
+		t.Fatalf("%s failed: expected operation %#v but received %#v", name+"/GetTrigger", "All", result.TriggerOperation)
+	}
+	if result := client.ListTriggers(dbname, collname); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/ListTriggers", result.Error())
+	} else if result.Count != 1 || len(result.Triggers) != 1 {
+		t.Fatalf("%s failed: expected 1 trigger but received %d", name+"/ListTriggers", result.Count)
+	}
+
+	docSpec := gocosmos.DocumentSpec{DbName: dbname, CollName: collname, PartitionKeyValues: []interface{}{"1"},
+		DocumentData: map[string]interface{}{"id": "1"}, PreTriggerInclude: []string{spec.Id}}
+	if result := client.CreateDocument(docSpec); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/CreateDocument", result.Error())
+	} else if result.DocInfo["triggered"] != true {
+		t.Fatalf("%s failed: trigger was not executed %#v", name+"/CreateDocument", result.DocInfo)
+	}
+
+	if result := client.DeleteTrigger(dbname, collname, spec.Id); result.Error() != nil {
+		t.Fatalf("%s failed: %s", name+"/DeleteTrigger", result.Error())
+	}
+	if result := client.GetTrigger(dbname, collname, spec.Id); result.StatusCode != 404 {
+		t.Fatalf("%s failed: expected status code 404 but received %d", name+"/GetTrigger", result.StatusCode)
+	}
+}
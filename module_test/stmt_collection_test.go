@@ -1,10 +1,16 @@
 package gocosmos_test
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/btnguyen2k/gocosmos"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -652,3 +658,254 @@ func TestStmtListCollections_Query_DefaultDb(t *testing.T) {
 		t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName, err)
 	}
 }
+
+/*----------------------------------------------------------------------*/
+
+func TestStmtDescribeCollection_Exec(t *testing.T) {
+	testName := "TestStmtDescribeCollection_Exec"
+	db := _openDb(t, testName)
+	_, err := db.Exec("DESCRIBE dbtemp.tbltemp")
+	if !errors.Is(err, gocosmos.ErrExecNotSupported) {
+		t.Fatalf("%s failed: expected ErrExecNotSupported but received %#v", testName, err)
+	}
+}
+
+func TestStmtDescribeCollection_Query(t *testing.T) {
+	testName := "TestStmtDescribeCollection_Query"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username WITH uk=/email WITH ru=500", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	dbRows, err := db.Query(fmt.Sprintf("DESCRIBE %s.tbltemp", dbname))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/query", err)
+	}
+	// collection's properties are named as in the REST API, computed columns as in SHOW THROUGHPUT
+	expectedCols := []string{"id", "partitionKey", "indexingPolicy", "uniqueKeyPolicy", "conflictResolutionPolicy", "geospatialConfig",
+		"defaultTtl", "_rid", "_ts", "_self", "_etag", "ru", "maxru", "shared_throughput", "num_pkranges"}
+	if cols, _ := dbRows.Columns(); !reflect.DeepEqual(cols, expectedCols) {
+		t.Fatalf("%s failed: <columns> expected %#v but received %#v", testName, expectedCols, cols)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/fetch_rows", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("%s failed: <num-rows> expected %#v but received %#v", testName, 1, len(rows))
+	}
+	row := rows[0]
+	if row["id"] != "tbltemp" || row["ru"] != 500 || row["maxru"] != 0 || row["shared_throughput"] != false || row["defaultTtl"] != nil {
+		t.Fatalf("%s failed: received %#v", testName, row)
+	}
+	if pk, ok := row["partitionKey"].(gocosmos.PkInfo); !ok || !reflect.DeepEqual(pk.Paths(), []string{"/username"}) {
+		t.Fatalf("%s failed: <partitionKey> received %#v", testName, row["partitionKey"])
+	}
+	if uk, _ := json.Marshal(row["uniqueKeyPolicy"]); !strings.Contains(string(uk), `"/email"`) {
+		t.Fatalf("%s failed: <uniqueKeyPolicy> received %s", testName, uk)
+	}
+	if numPkranges, ok := row["num_pkranges"].(int); !ok || numPkranges < 1 {
+		t.Fatalf("%s failed: <num_pkranges> received %#v", testName, row["num_pkranges"])
+	}
+
+	_, err = db.Query(fmt.Sprintf("DESCRIBE %s.tbl_not_found", dbname))
+	if !errors.Is(err, gocosmos.ErrNotFound) {
+		t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName, err)
+	}
+}
+
+func TestStmtShowThroughput_Query(t *testing.T) {
+	testName := "TestStmtShowThroughput_Query"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE %s WITH maxru=4000", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tblshared WITH pk=/id", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbldedicated WITH pk=/id WITH ru=600", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	testData := []struct {
+		sql                         string
+		id, typ                     string
+		ru, maxru                   int
+		autopilot, sharedThroughput bool
+	}{
+		{sql: "SHOW THROUGHPUT FOR DATABASE " + dbname, id: dbname, typ: "database", maxru: 4000, autopilot: true},
+		{sql: "SHOW THROUGHPUT FOR COLLECTION " + dbname + ".tblshared", id: "tblshared", typ: "collection", maxru: 4000, autopilot: true, sharedThroughput: true},
+		{sql: "SHOW THROUGHPUT FOR TABLE " + dbname + ".tbldedicated", id: "tbldedicated", typ: "collection", ru: 600},
+	}
+	for _, testCase := range testData {
+		dbRows, err := db.Query(testCase.sql)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName+"/"+testCase.id, err)
+		}
+		rows, err := _fetchAllRows(dbRows)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName+"/"+testCase.id, err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("%s failed: <num-rows> expected %#v but received %#v", testName+"/"+testCase.id, 1, len(rows))
+		}
+		row := rows[0]
+		if row["id"] != testCase.id || row["type"] != testCase.typ || row["maxru"] != testCase.maxru ||
+			row["autopilot"] != testCase.autopilot || row["shared_throughput"] != testCase.sharedThroughput {
+			t.Fatalf("%s failed: received %#v", testName+"/"+testCase.id, row)
+		}
+		if testCase.ru > 0 && row["ru"] != testCase.ru {
+			t.Fatalf("%s failed: <ru> expected %#v but received %#v", testName+"/"+testCase.id, testCase.ru, row["ru"])
+		}
+		if (testCase.typ == "database") != (row["num_pkranges"] == nil) {
+			t.Fatalf("%s failed: <num_pkranges> received %#v", testName+"/"+testCase.id, row["num_pkranges"])
+		}
+	}
+
+	_, err := db.Query("SHOW THROUGHPUT FOR DATABASE db_not_found")
+	if !errors.Is(err, gocosmos.ErrNotFound) {
+		t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName, err)
+	}
+}
+
+func TestStmtCreateAlterCollection_Ttl(t *testing.T) {
+	testName := "TestStmtCreateAlterCollection_Ttl"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+
+	testData := []struct {
+		name     string
+		sql      string
+		expected interface{}
+	}{
+		{name: "create", sql: "CREATE COLLECTION %s.tbltemp WITH pk=/id WITH TTL=3600", expected: 3600},
+		{name: "alter_no_expiry", sql: "ALTER COLLECTION %s.tbltemp WITH TTL=-1", expected: -1},
+		{name: "create_index_keeps_ttl", sql: "CREATE EXCLUDED INDEX ON %s.tbltemp (/content/*)", expected: -1},
+		{name: "alter_ru_keeps_ttl", sql: "ALTER COLLECTION %s.tbltemp WITH RU=500", expected: -1},
+		{name: "alter_off_and_ru", sql: "ALTER COLLECTION %s.tbltemp WITH TTL=OFF, RU=600", expected: nil},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := db.Exec(fmt.Sprintf(testCase.sql, dbname)); err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			dbRows, err := db.Query(fmt.Sprintf("DESCRIBE %s.tbltemp", dbname))
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			rows, err := _fetchAllRows(dbRows)
+			if err != nil || len(rows) != 1 {
+				t.Fatalf("%s failed: %s / %#v", testName+"/"+testCase.name, err, rows)
+			}
+			if ttl := rows[0]["defaultTtl"]; ttl != testCase.expected {
+				t.Fatalf("%s failed: <defaultTtl> expected %#v but received %#v", testName+"/"+testCase.name, testCase.expected, ttl)
+			}
+		})
+	}
+}
+
+func TestStmtAlterCollection_Exec_ThroughputFirst(t *testing.T) {
+	testName := "TestStmtAlterCollection_Exec_ThroughputFirst"
+	var mutex sync.Mutex
+	var requests []string
+	hasOffer := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"id":"myaccount"}`))
+		case r.URL.Path == "/offers" && r.Method == "POST":
+			offers := `[]` // the collection has no dedicated throughput
+			if hasOffer {
+				offers = `[{"id":"offer1","_rid":"offer1","offerVersion":"V2","resource":"dbs/db/colls/coll/","offerResourceId":"collrid","content":{"offerThroughput":400}}]`
+			}
+			_, _ = w.Write([]byte(`{"_rid":"","_count":1,"Offers":` + offers + `}`))
+		case r.Method == "PUT":
+			_, _ = w.Write([]byte(`{"id":"offer1","_rid":"offer1","content":{"offerThroughput":500}}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"mytable","_rid":"collrid","partitionKey":{"paths":["/pk"],"kind":"Hash"}}`))
+		}
+	}))
+	defer server.Close()
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// changing the throughput fails: the TTL must not be changed either
+	if _, err := db.Exec("ALTER COLLECTION mytable WITH RU=500, TTL=3600"); !errors.Is(err, gocosmos.ErrNotFound) {
+		t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName+"/no_offer", err)
+	}
+	for _, req := range requests {
+		if req == "PUT /dbs/mydb/colls/mytable" {
+			t.Fatalf("%s failed: collection must not be replaced, requests %#v", testName+"/no_offer", requests)
+		}
+	}
+
+	mutex.Lock()
+	requests, hasOffer = nil, true
+	mutex.Unlock()
+	if _, err := db.Exec("ALTER COLLECTION mytable WITH RU=500, TTL=3600"); err != nil {
+		t.Fatalf("%s failed: %s", testName+"/ok", err)
+	}
+	expected := []string{"GET /dbs/mydb/colls/mytable", "POST /offers", "PUT /offers/offer1", "PUT /dbs/mydb/colls/mytable"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("%s failed: expected requests %#v but received %#v", testName+"/ok", expected, requests)
+	}
+}
+
+func TestStmtAlterCollection_Exec_RetainsConflictPolicy(t *testing.T) {
+	testName := "TestStmtAlterCollection_Exec_RetainsConflictPolicy"
+	var mutex sync.Mutex
+	var replaceBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"id":"myaccount"}`))
+		case r.Method == "PUT":
+			_ = json.NewDecoder(r.Body).Decode(&replaceBody)
+			_, _ = w.Write([]byte(`{"id":"mytable","_rid":"collrid"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"mytable","_rid":"collrid","partitionKey":{"paths":["/pk"],"kind":"Hash"},` +
+				`"conflictResolutionPolicy":{"mode":"Custom","conflictResolutionProcedure":"dbs/mydb/colls/mytable/sprocs/resolver"}}`))
+		}
+	}))
+	defer server.Close()
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// replacing the collection must not reset its custom conflict resolution policy
+	if _, err := db.Exec("ALTER COLLECTION mytable WITH TTL=3600"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if policy, _ := replaceBody["conflictResolutionPolicy"].(map[string]interface{}); policy["mode"] != "Custom" ||
+		policy["conflictResolutionProcedure"] != "dbs/mydb/colls/mytable/sprocs/resolver" {
+		t.Fatalf("%s failed: unexpected conflict resolution policy sent %#v", testName, replaceBody["conflictResolutionPolicy"])
+	}
+}
@@ -651,3 +651,129 @@ func TestStmtDelete_WithPK_SubPartitions(t *testing.T) {
 		})
 	}
 }
+
+func TestStmtDelete_MultiDocs(t *testing.T) {
+	testName := "TestStmtDelete_MultiDocs"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/app,/username", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		_, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp (id,app,username,grade) VALUES (:1,$2,@3,$4)`, dbname),
+			fmt.Sprintf("%d", i), fmt.Sprintf("app%d", i%2), fmt.Sprintf("user%d", i%3), i)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName+"/insert", err)
+		}
+	}
+
+	testData := []struct {
+		name         string
+		sql          string
+		args         []interface{}
+		mustError    bool
+		affectedRows int64
+	}{
+		{name: "error_max_rows", sql: `DELETE FROM %s.tbltemp WHERE c.grade >= @1 WITH MAX_ROWS=2`, args: []interface{}{5}, mustError: true},
+		{name: "predicate", sql: `DELETE FROM %s.tbltemp WHERE c.grade >= @1 WITH MAX_ROWS=5`, args: []interface{}{5}, affectedRows: 5},
+		{name: "predicate_concurrency", sql: `DELETE FROM %s.tbltemp WHERE c.app = $1 WITH CONCURRENCY=4`, args: []interface{}{"app1"}, affectedRows: 2},
+		{name: "predicate_with_pk", sql: `DELETE FROM %s.tbltemp WHERE c.username="user0" WITH pk=/app,/username`, affectedRows: 1},
+		{name: "no_match", sql: `DELETE FROM %s.tbltemp WHERE c.grade > 100`, affectedRows: 0},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			execResult, err := db.Exec(fmt.Sprintf(testCase.sql, dbname), testCase.args...)
+			if testCase.mustError {
+				if err == nil {
+					t.Fatalf("%s failed: expected error", testName+"/"+testCase.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			affectedRows, err := execResult.RowsAffected()
+			if err != nil || affectedRows != testCase.affectedRows {
+				t.Fatalf("%s failed: expected %d affected-rows but received %d / %s", testName+"/"+testCase.name, testCase.affectedRows, affectedRows, err)
+			}
+		})
+	}
+
+	dbRows, err := db.Query(fmt.Sprintf(`SELECT * FROM c WITH db=%s WITH collection=tbltemp WITH cross_partition=true`, dbname))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/select", err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil || len(rows) != 2 { // documents with ids 2 and 4
+		t.Fatalf("%s failed: expected 2 rows but received %d / %s", testName+"/select", len(rows), err)
+	}
+}
+
+func TestStmtDelete_MultiDocsConcurrentWriter(t *testing.T) {
+	testName := "TestStmtDelete_MultiDocsConcurrentWriter"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	sql := `DELETE FROM mytable WHERE c.status = "archived"`
+	fake.recheck = func(doc map[string]interface{}) bool { return doc["status"] == "archived" }
+	concurrentWrites, concurrentStatus := 0, ""
+	fake.beforeWrite = func(id string) {
+		if concurrentWrites > 0 {
+			concurrentWrites--
+			doc := fake.get(collPath, id)
+			doc["status"] = concurrentStatus
+			fake.put(collPath, doc)
+		}
+	}
+	testCases := []struct {
+		name             string
+		concurrentWrites int
+		concurrentStatus string
+		deleted          bool
+		mustError        bool
+	}{
+		// the document is modified after the query but still matches: it is re-checked and deleted
+		{name: "still_matches", concurrentWrites: 1, concurrentStatus: "archived", deleted: true},
+		// the document is modified after the query and no longer matches: it is not deleted
+		{name: "no_longer_matches", concurrentWrites: 1, concurrentStatus: "active"},
+		// the document keeps being modified concurrently: the statement gives up with ErrPreconditionFailure
+		{name: "give_up", concurrentWrites: 100, concurrentStatus: "archived", mustError: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p", "status": "archived"})
+			concurrentWrites, concurrentStatus = testCase.concurrentWrites, testCase.concurrentStatus
+			execResult, err := db.Exec(sql)
+			if testCase.mustError {
+				if !errors.Is(err, gocosmos.ErrPreconditionFailure) {
+					t.Fatalf("%s failed: expected ErrPreconditionFailure but received %#v", testName+"/"+testCase.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			expectedRows := int64(0)
+			if testCase.deleted {
+				expectedRows = 1
+			}
+			if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != expectedRows {
+				t.Fatalf("%s failed: expected %d affected-rows but received %d / %s", testName+"/"+testCase.name, expectedRows, affectedRows, err)
+			}
+			if doc := fake.get(collPath, "1"); (doc == nil) != testCase.deleted {
+				t.Fatalf("%s failed: unexpected document %#v", testName+"/"+testCase.name, doc)
+			}
+		})
+	}
+}
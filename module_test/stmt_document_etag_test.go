@@ -0,0 +1,52 @@
+package gocosmos_test
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/btnguyen2k/gocosmos"
+	"testing"
+)
+
+func TestStmt_ExpectedEtag(t *testing.T) {
+	name := "TestStmt_ExpectedEtag"
+	fake := _newFakeDocServer()
+	defer fake.server.Close()
+
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+fake.server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	testData := []struct {
+		name   string
+		sql    string
+		args   []interface{}
+		method string
+	}{
+		{name: "update_where_etag", sql: `UPDATE mytable SET a=1 WHERE id=@1 AND _etag=@2 AND tenant="\"t1\""`, args: []interface{}{"1", `"etag1"`}, method: "PATCH"},
+		{name: "update_if_match", sql: `UPDATE mytable SET a=1 WHERE id=@1 AND tenant="\"t1\"" WITH IF_MATCH=@2`, args: []interface{}{"1", `"etag1"`}, method: "PATCH"},
+		{name: "delete_where_etag", sql: `DELETE FROM mytable WHERE id=@1 AND _etag=@2 AND tenant="\"t1\""`, args: []interface{}{"1", `"etag1"`}, method: "DELETE"},
+		{name: "delete_if_match", sql: `DELETE FROM mytable WHERE id=@1 AND tenant="\"t1\"" WITH IF_MATCH=@2`, args: []interface{}{"1", `"etag1"`}, method: "DELETE"},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake.status = 0
+			execResult, err := db.Exec(testCase.sql, testCase.args...)
+			if err != nil {
+				t.Fatalf("%s failed: %s", name+"/"+testCase.name, err)
+			}
+			if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+				t.Fatalf("%s failed: expected 1 affected-row but received %d / %s", name+"/"+testCase.name, affectedRows, err)
+			}
+			if fake.method != testCase.method || fake.ifMatchHeader != `"etag1"` {
+				t.Fatalf("%s failed: unexpected request {method: %s / if-match: %s}", name+"/"+testCase.name, fake.method, fake.ifMatchHeader)
+			}
+
+			fake.status = 412
+			if _, err := db.Exec(testCase.sql, testCase.args...); !errors.Is(err, gocosmos.ErrPreconditionFailure) {
+				t.Fatalf("%s failed: expected ErrPreconditionFailure but received %s", name+"/"+testCase.name, err)
+			}
+		})
+	}
+}
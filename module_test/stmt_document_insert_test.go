@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/btnguyen2k/gocosmos"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -652,3 +653,339 @@ func TestStmtInsert_WithPK_SubPartitions(t *testing.T) {
 		})
 	}
 }
+
+func TestStmtInsert_MultiRows(t *testing.T) {
+	testName := "TestStmtInsert_MultiRows"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username WITH uk=/email", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s.tbltemp (id,username,email) VALUES (:1,:2,:3), (:4,:5,:6), ("\"3\"","\"user3\"",:7) WITH pk=/username, CONCURRENCY=2`, dbname)
+	execResult, err := db.Exec(sql, "1", "user1", "user1@domain.com", "2", "user2", "user2@domain.com", "user3@domain.com")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/insert", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 3 {
+		t.Fatalf("%s failed: expected 3 affected-rows but received %d / %s", testName+"/insert", affectedRows, err)
+	}
+
+	sql = fmt.Sprintf(`INSERT INTO %s.tbltemp (id,username,email) VALUES (:1,:2,:3), (:4,:5,:6)`, dbname)
+	_, err = db.Exec(sql, "4", "user4", "user4@domain.com", "1", "user1", "user1@domain.com")
+	if !errors.Is(err, gocosmos.ErrConflict) || !strings.HasPrefix(err.Error(), "row 2:") {
+		t.Fatalf("%s failed: expected ErrConflict on row 2 but received %#v", testName+"/conflict", err)
+	}
+
+	sql = fmt.Sprintf(`UPSERT INTO %s.tbltemp (id,username,email) VALUES (:1,:2,:3), (:4,:5,:6)`, dbname)
+	execResult, err = db.Exec(sql, "5", "user5", "user5@domain.com", "1", "user1", "user1@domain.com")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/upsert", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 2 {
+		t.Fatalf("%s failed: expected 2 affected-rows but received %d / %s", testName+"/upsert", affectedRows, err)
+	}
+}
+
+func TestStmtInsert_MultiRowsPartialFailure(t *testing.T) {
+	testName := "TestStmtInsert_MultiRowsPartialFailure"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	fake.put("/dbs/mydb/colls/mytable", map[string]interface{}{"id": "2", "pk": "p"})
+
+	// database/sql discards RowsAffected on error: the error reports the partial outcome
+	_, err := db.Exec(`INSERT INTO mytable (id,pk) VALUES ("\"1\"","\"p\""), ("\"2\"","\"p\""), ("\"3\"","\"p\""), ("\"4\"","\"p\"") WITH PK=/pk, CONCURRENCY=1`)
+	if !errors.Is(err, gocosmos.ErrConflict) || !strings.HasPrefix(err.Error(), "row 2:") ||
+		!strings.HasSuffix(err.Error(), "(1 row(s) written, not written: row 2-4)") {
+		t.Fatalf("%s failed: expected ErrConflict on row 2 but received %#v", testName, err)
+	}
+	if doc := fake.get("/dbs/mydb/colls/mytable", "1"); doc == nil {
+		t.Fatalf("%s failed: row 1 must be written", testName)
+	}
+	if doc := fake.get("/dbs/mydb/colls/mytable", "3"); doc != nil {
+		t.Fatalf("%s failed: rows after the failing one must not be written", testName)
+	}
+}
+
+func TestStmtInsertSelect(t *testing.T) {
+	testName := "TestStmtInsertSelect"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tblsrc WITH pk=/username", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbldest WITH pk=/grade", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		_, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tblsrc (id,username,grade) VALUES (:1,:2,:3)`, dbname),
+			fmt.Sprintf("%d", i), fmt.Sprintf("user%d", i), i%3)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName+"/insert", err)
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s.tbldest SELECT c.id, c.grade, c.username AS owner FROM tblsrc c WHERE c.grade > @1 WITH CONCURRENCY=3`, dbname)
+	execResult, err := db.Exec(sql, 0)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/insert_select", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 6 {
+		t.Fatalf("%s failed: expected 6 affected-rows but received %d / %s", testName+"/insert_select", affectedRows, err)
+	}
+
+	if _, err = db.Exec(sql, 0); !errors.Is(err, gocosmos.ErrConflict) {
+		t.Fatalf("%s failed: expected ErrConflict but received %#v", testName+"/insert_select", err)
+	}
+
+	sql = fmt.Sprintf(`UPSERT INTO %s.tbldest SELECT c.id, c.grade FROM c WITH collection=tblsrc, PK=/grade`, dbname)
+	execResult, err = db.Exec(sql)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/upsert_select", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 10 {
+		t.Fatalf("%s failed: expected 10 affected-rows but received %d / %s", testName+"/upsert_select", affectedRows, err)
+	}
+}
+
+func TestStmtInsertValue(t *testing.T) {
+	testName := "TestStmtInsertValue"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/customer/country,/customer/name", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+
+	type customer struct {
+		Country string `json:"country"`
+		Name    string `json:"name"`
+	}
+	type order struct {
+		Id       string   `json:"id"`
+		Customer customer `json:"customer"`
+		Items    []string `json:"items"`
+	}
+	testData := []struct {
+		name string
+		sql  string
+		arg  interface{}
+	}{
+		{name: "struct", sql: `INSERT INTO %s.tbltemp VALUE @1`, arg: order{Id: "1", Customer: customer{Country: "VN", Name: "Thanh"}, Items: []string{"apple"}}},
+		{name: "map", sql: `INSERT INTO %s.tbltemp VALUE :1 WITH PK=/customer/country,/customer/name`, arg: map[string]interface{}{"id": "2", "customer": map[string]interface{}{"country": "US", "name": "Bob"}}},
+		{name: "raw_json", sql: `INSERT INTO %s.tbltemp VALUE $1`, arg: json.RawMessage(`{"id":"3","customer":{"country":"US","name":"Alice"}}`)},
+		{name: "bytes", sql: `UPSERT INTO %s.tbltemp VALUE $1`, arg: []byte(`{"id":"3","customer":{"country":"US","name":"Alice"},"vip":true}`)},
+		{name: "literal", sql: `INSERT INTO %s.tbltemp VALUE '{"id":"4","customer":{"country":"IE","name":"O''Brien"}}'`},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			args := make([]interface{}, 0)
+			if testCase.arg != nil {
+				args = append(args, testCase.arg)
+			}
+			execResult, err := db.Exec(fmt.Sprintf(testCase.sql, dbname), args...)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+				t.Fatalf("%s failed: expected 1 affected-row but received %d / %s", testName+"/"+testCase.name, affectedRows, err)
+			}
+		})
+	}
+
+	client := _newRestClient(t, testName)
+	getResult := client.GetDocument(gocosmos.DocReq{DbName: dbname, CollName: "tbltemp", DocId: "4", PartitionKeyValues: []interface{}{"IE", "O'Brien"}})
+	if getResult.Error() != nil {
+		t.Fatalf("%s failed: %s", testName+"/GetDocument", getResult.Error())
+	}
+	if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp VALUE @1`, dbname), "[1,2,3]"); err == nil {
+		t.Fatalf("%s failed: expected error for non-object value", testName)
+	}
+}
+
+func TestStmtInsert_Ttl(t *testing.T) {
+	testName := "TestStmtInsert_Ttl"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/id WITH TTL=-1", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+
+	testData := []struct {
+		name string
+		id   string
+		sql  string
+		args []interface{}
+	}{
+		{name: "insert", id: "1", sql: `INSERT INTO %s.tbltemp (id, name) VALUES ("\"1\"", :1) WITH TTL=3600`, args: []interface{}{"session 1"}},
+		{name: "upsert", id: "2", sql: `UPSERT INTO %s.tbltemp (id, name) VALUES (:1, :2) WITH PK=/id, TTL=3600`, args: []interface{}{"2", "session 2"}},
+		{name: "insert_value", id: "3", sql: `INSERT INTO %s.tbltemp VALUE @1 WITH TTL=3600`, args: []interface{}{map[string]interface{}{"id": "3", "ttl": 10}}},
+		{name: "insert_select", id: "4", sql: `INSERT INTO %s.tbltemp SELECT "4" AS id FROM tbltemp c WHERE c.id="1" WITH TTL=3600`},
+	}
+	client := _newRestClient(t, testName)
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := db.Exec(fmt.Sprintf(testCase.sql, dbname), testCase.args...); err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			getResult := client.GetDocument(gocosmos.DocReq{DbName: dbname, CollName: "tbltemp", DocId: testCase.id, PartitionKeyValues: []interface{}{testCase.id}})
+			if getResult.Error() != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, getResult.Error())
+			}
+			if ttl := getResult.DocInfo["ttl"]; ttl != 3600.0 {
+				t.Fatalf("%s failed: <ttl> expected %#v but received %#v", testName+"/"+testCase.name, 3600.0, ttl)
+			}
+		})
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp (id) VALUES ("\"5\"") WITH TTL=OFF`, dbname)); err == nil {
+		t.Fatalf("%s failed: expected error for invalid TTL", testName)
+	}
+}
+
+func TestStmtInsertSelect_Paging(t *testing.T) {
+	testName := "TestStmtInsertSelect_Paging"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	for i := 1; i <= 250; i++ {
+		fake.put("/dbs/mydb/colls/tblsrc", map[string]interface{}{"id": fmt.Sprintf("d%03d", i), "pk": "p", "grade": float64(i)})
+	}
+
+	execResult, err := db.Exec(`INSERT INTO tbldest SELECT * FROM tblsrc c WITH PK=/pk`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 250 {
+		t.Fatalf("%s failed: expected 250 affected-rows but received %d / %s", testName, affectedRows, err)
+	}
+	if fake.queryPages != 3 {
+		t.Fatalf("%s failed: expected documents to be fetched in 3 pages but received %d", testName, fake.queryPages)
+	}
+	if doc := fake.get("/dbs/mydb/colls/tbldest", "d250"); doc == nil || doc["grade"] != 250.0 {
+		t.Fatalf("%s failed: unexpected document %#v", testName, doc)
+	}
+	if fake.queryPlans != 1 {
+		t.Fatalf("%s failed: expected the query plan to be fetched once but it was fetched %d times", testName, fake.queryPlans)
+	}
+
+	// the query plan and partition key ranges needed to merge results are fetched once, not for each page
+	fake.reset()
+	fake.planInfo = map[string]interface{}{"distinctType": "None", "rewrittenQuery": "SELECT * FROM c"}
+	execResult, err = db.Exec(`INSERT INTO tbldest3 SELECT * FROM tblsrc c WITH PK=/pk`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 250 {
+		t.Fatalf("%s failed: expected 250 affected-rows but received %d / %s", testName, affectedRows, err)
+	}
+	if fake.queryPages != 3 || fake.queryPlans != 1 || fake.pkrangeGets != 1 {
+		t.Fatalf("%s failed: expected 3 pages, 1 query plan and 1 pk-ranges requests but received %d, %d and %d",
+			testName, fake.queryPages, fake.queryPlans, fake.pkrangeGets)
+	}
+	fake.planInfo = nil
+
+	// the failing row is numbered across pages
+	fake.put("/dbs/mydb/colls/tbldest2", map[string]interface{}{"id": "d150", "pk": "p"})
+	_, err = db.Exec(`INSERT INTO tbldest2 SELECT * FROM tblsrc c WITH PK=/pk, CONCURRENCY=1`)
+	if !errors.Is(err, gocosmos.ErrConflict) || !strings.Contains(err.Error(), "row 150:") ||
+		!strings.HasSuffix(err.Error(), "(149 row(s) written, not written: row 150-200, and all following rows)") {
+		t.Fatalf("%s failed: expected ErrConflict at row 150 but received %#v", testName, err)
+	}
+	if doc := fake.get("/dbs/mydb/colls/tbldest2", "d151"); doc != nil {
+		t.Fatalf("%s failed: rows after the failing one must not be written", testName)
+	}
+}
+
+func TestStmtInsertSelect_Aggregate(t *testing.T) {
+	testName := "TestStmtInsertSelect_Aggregate"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	fake.put("/dbs/mydb/colls/tblsrc", map[string]interface{}{"id": "d1", "pk": "p1", "a": 3.0})
+	fake.put("/dbs/mydb/colls/tblsrc", map[string]interface{}{"id": "d2", "pk": "p2", "a": 7.0})
+
+	// each partition key range returns a partial aggregate, they must be merged into a single row
+	fake.numPkranges = 2
+	fake.planInfo = map[string]interface{}{"distinctType": "None", "aggregates": []string{"Max"},
+		"groupByExpressions": []string{}, "groupByAliasToAggregateType": map[string]string{"m": "Max"},
+		"rewrittenQuery": `SELECT [] AS groupByItems, {"m": {"item": MAX(c.a)}} AS payload FROM c`}
+	fake.rangeQuery = func(pkRangeId string) []interface{} {
+		partial := map[string]float64{"0": 3, "1": 7}[pkRangeId]
+		return []interface{}{map[string]interface{}{"groupByItems": []interface{}{}, "payload": map[string]interface{}{"m": map[string]interface{}{"item": partial}}}}
+	}
+	execResult, err := db.Exec(`INSERT INTO tbldest SELECT MAX(c.a) AS m FROM tblsrc c WITH PK=/pk`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+		t.Fatalf("%s failed: expected 1 affected-row but received %d / %s", testName, affectedRows, err)
+	}
+	if len(fake.requests) != 1 {
+		t.Fatalf("%s failed: expected 1 document to be written but received %#v", testName, fake.requests)
+	}
+	for _, doc := range fake.colls["/dbs/mydb/colls/tbldest"] {
+		if doc["m"] != 7.0 {
+			t.Fatalf("%s failed: expected the merged aggregate 7 but received %#v", testName, doc)
+		}
+	}
+}
+
+func TestStmtInsertValue_CallerValueUnchanged(t *testing.T) {
+	testName := "TestStmtInsertValue_CallerValueUnchanged"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+
+	for _, sql := range []string{`INSERT INTO mytable VALUE @1`, `UPSERT INTO mytable VALUE @1 WITH TTL=60`} {
+		doc := map[string]interface{}{"pk": "p", "name": "Thanh"}
+		if _, err := db.Exec(sql, doc); err != nil {
+			t.Fatalf("%s failed: %s", testName+"/"+sql, err)
+		}
+		// neither the auto-generated id nor the ttl must be written to the caller's map
+		if expected := map[string]interface{}{"pk": "p", "name": "Thanh"}; !reflect.DeepEqual(doc, expected) {
+			t.Fatalf("%s failed: caller's value must not be modified, expected %#v but received %#v", testName+"/"+sql, expected, doc)
+		}
+	}
+	if n := len(fake.requests); n != 2 {
+		t.Fatalf("%s failed: expected 2 documents to be written but received %d requests", testName, n)
+	}
+}
@@ -0,0 +1,109 @@
+package gocosmos_test
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStmtReturning(t *testing.T) {
+	testName := "TestStmtReturning"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+
+	testData := []struct {
+		name     string
+		sql      string
+		args     []interface{}
+		numRows  int
+		expected map[string]interface{}
+	}{
+		{name: "insert", sql: `INSERT INTO %s.tbltemp (id,username,grade) VALUES (@1,@2,@3) RETURNING id, grade`, args: []interface{}{"1", "user1", 1.0},
+			numRows: 1, expected: map[string]interface{}{"id": "1", "grade": 1.0}},
+		{name: "insert_multi_rows", sql: `INSERT INTO %s.tbltemp (id,username,grade) VALUES (:1,:1,2),(:2,:2,3) RETURNING id`, args: []interface{}{"2", "3"},
+			numRows: 2},
+		{name: "upsert", sql: `UPSERT INTO %s.tbltemp VALUE @1 RETURNING *`, args: []interface{}{map[string]interface{}{"id": "1", "username": "user1", "grade": 4.0}},
+			numRows: 1, expected: map[string]interface{}{"id": "1", "username": "user1", "grade": 4.0}},
+		{name: "update", sql: `UPDATE %s.tbltemp SET grade=$1 WHERE id=$2 RETURNING grade`, args: []interface{}{5.0, "1", "user1"},
+			numRows: 1, expected: map[string]interface{}{"grade": 5.0}},
+		{name: "update_not_found", sql: `UPDATE %s.tbltemp SET grade=$1 WHERE id=$2 RETURNING grade`, args: []interface{}{5.0, "0", "user0"},
+			numRows: 0},
+		{name: "update_multi_docs", sql: `UPDATE %s.tbltemp SET grade=10 WHERE c.grade < 5 RETURNING id, grade`, args: []interface{}{},
+			numRows: 2},
+		{name: "delete", sql: `DELETE FROM %s.tbltemp WHERE id=:1 RETURNING username, grade`, args: []interface{}{"1", "user1"},
+			numRows: 1, expected: map[string]interface{}{"username": "user1", "grade": 5.0}},
+		{name: "delete_not_found", sql: `DELETE FROM %s.tbltemp WHERE id=:1 RETURNING *`, args: []interface{}{"1", "user1"},
+			numRows: 0},
+		{name: "delete_multi_docs", sql: `DELETE FROM %s.tbltemp WHERE c.grade >= 10 RETURNING id`, args: []interface{}{},
+			numRows: 2},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbRows, err := db.Query(fmt.Sprintf(testCase.sql, dbname), testCase.args...)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			rows, err := _fetchAllRows(dbRows)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if len(rows) != testCase.numRows {
+				t.Fatalf("%s failed: expected %d rows but received %d", testName+"/"+testCase.name, testCase.numRows, len(rows))
+			}
+			if testCase.expected != nil {
+				for k, v := range testCase.expected {
+					if rows[0][k] != v {
+						t.Fatalf("%s failed: expected %#v for field %s but received %#v", testName+"/"+testCase.name, v, k, rows[0][k])
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := db.Query(fmt.Sprintf(`DELETE FROM %s.tbltemp WHERE id=:1`, dbname), "2", "2"); err == nil {
+		t.Fatalf("%s failed: expected error for query without RETURNING clause", testName)
+	}
+}
+
+func TestStmtReturning_DeleteConcurrentWriter(t *testing.T) {
+	testName := "TestStmtReturning_DeleteConcurrentWriter"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p", "grade": 1.0})
+
+	// another writer modifies the document right after its pre-delete image is fetched: the image is fetched again
+	concurrentWrites := 1
+	fake.beforeWrite = func(id string) {
+		if concurrentWrites > 0 {
+			concurrentWrites--
+			doc := fake.get(collPath, id)
+			doc["grade"] = 2.0
+			fake.put(collPath, doc)
+		}
+	}
+	dbRows, err := db.Query(`DELETE FROM mytable WHERE id=:1 AND pk=:2 RETURNING grade`, "1", "p")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil || len(rows) != 1 || rows[0]["grade"] != 2.0 {
+		t.Fatalf("%s failed: expected the latest pre-delete image but received %#v / %s", testName, rows, err)
+	}
+	if doc := fake.get(collPath, "1"); doc != nil {
+		t.Fatalf("%s failed: document must be deleted", testName)
+	}
+}
@@ -720,6 +720,195 @@ func TestStmtSelect_Query_SelectPlaceholder(t *testing.T) {
 	}
 }
 
+func TestStmtSelect_Query_SelectNamedParams(t *testing.T) {
+	testName := "TestStmtSelect_Query_SelectNamedParams"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username WITH uk=/email", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("%02d", i)
+		username := "user" + strconv.Itoa(i%4)
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp (id,username,email,grade) VALUES (@id,@username,@email,@grade) WITH pk=/username`, dbname),
+			sql.Named("id", id), sql.Named("username", username), sql.Named("email", "user"+id+"@domain.com"), sql.Named("grade", i)); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+	}
+
+	if dbRows, err := db.Query(fmt.Sprintf(`SELECT * FROM c WHERE c.username=@username AND c.id>@id ORDER BY c.id WITH database=%s WITH collection=tbltemp`, dbname),
+		sql.Named("id", "30"), sql.Named("username", "user0")); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	} else {
+		rows, err := _fetchAllRows(dbRows)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if len(rows) != 17 {
+			t.Fatalf("%s failed: <num-document> expected %#v but received %#v", testName, 17, len(rows))
+		}
+		for _, row := range rows {
+			id := row["id"].(string)
+			if id <= "30" {
+				t.Fatalf("%s failed: document #%s should not be returned", testName, id)
+			}
+		}
+	}
+
+	if _, err := db.Query(fmt.Sprintf(`SELECT * FROM c WHERE c.username=@username AND c.id>@id WITH database=%s WITH collection=tbltemp`, dbname), sql.Named("id", "30")); err == nil || strings.Index(err.Error(), "missing value") < 0 {
+		t.Fatalf("%s failed: expecting 'missing value' but received %s", testName, err)
+	}
+	if _, err := db.Query(fmt.Sprintf(`SELECT * FROM c WHERE c.username=@username AND c.id>@id WITH database=%s WITH collection=tbltemp`, dbname), "user0", "30"); err == nil || strings.Index(err.Error(), "must not be mixed") < 0 {
+		t.Fatalf("%s failed: expecting 'must not be mixed' but received %s", testName, err)
+	}
+	if _, err := db.Query(fmt.Sprintf(`SELECT * FROM c WHERE c.username=@username AND c.id>$1 WITH database=%s WITH collection=tbltemp`, dbname), sql.Named("username", "user0"), "30"); err == nil || strings.Index(err.Error(), "must not be mixed") < 0 {
+		t.Fatalf("%s failed: expecting 'must not be mixed' but received %s", testName, err)
+	}
+}
+
+func TestStmtSelect_Query_PointRead(t *testing.T) {
+	testName := "TestStmtSelect_Query_PointRead"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("%02d", i)
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp (id,username,grade) VALUES (:1,:2,:3)`, dbname), id, "user"+strconv.Itoa(i%2), i); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+	}
+
+	pointRead := fmt.Sprintf(`SELECT * FROM c WHERE c.id=@1 AND c.username=@2 WITH database=%s WITH collection=tbltemp`, dbname)
+	query := fmt.Sprintf(`SELECT * FROM c WHERE c.id=@1 AND c.username=@2 AND c.grade>=0 WITH database=%s WITH collection=tbltemp`, dbname)
+	for _, testCase := range []struct {
+		id, username string
+		numRows      int
+	}{{"03", "user1", 1}, {"03", "user0", 0}, {"99", "user1", 0}} {
+		dbRows, err := db.Query(pointRead, testCase.id, testCase.username)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		rows, err := _fetchAllRows(dbRows)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if len(rows) != testCase.numRows {
+			t.Fatalf("%s failed: <num-document> expected %#v but received %#v", testName, testCase.numRows, len(rows))
+		}
+		dbRows, err = db.Query(query, testCase.id, testCase.username)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		expected, err := _fetchAllRows(dbRows)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, rows)
+		}
+	}
+}
+
+func TestStmtSelect_Query_Metrics(t *testing.T) {
+	testName := "TestStmtSelect_Query_Metrics"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("%02d", i)
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp (id,username,grade) VALUES (:1,:2,:3)`, dbname), id, "user"+strconv.Itoa(i%2), i); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+	}
+
+	var queryMetrics gocosmos.QueryMetrics
+	var indexMetrics gocosmos.IndexMetrics
+	query := fmt.Sprintf(`SELECT * FROM c WHERE c.grade>=@1 WITH database=%s WITH collection=tbltemp WITH cross_partition=true`, dbname)
+	dbRows, err := db.Query(query, 5, sql.Out{Dest: &queryMetrics}, sql.Out{Dest: &indexMetrics})
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("%s failed: <num-document> expected %#v but received %#v", testName, 5, len(rows))
+	}
+	if queryMetrics.OutputDocumentCount != 5 || queryMetrics.RetrievedDocumentCount < 5 {
+		t.Fatalf("%s failed: <query-metrics> received %#v", testName, queryMetrics)
+	}
+	if len(indexMetrics.UtilizedSingleIndexes) == 0 {
+		t.Fatalf("%s failed: <index-metrics> received %#v", testName, indexMetrics)
+	}
+
+	var unsupported string
+	if _, err := db.Query(query, 5, sql.Out{Dest: &unsupported}); err == nil {
+		t.Fatalf("%s failed: unsupported output argument must be rejected", testName)
+	}
+}
+
+func TestStmtExplain_Query(t *testing.T) {
+	testName := "TestStmtExplain_Query"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/username WITH ru=20000", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(`EXPLAIN SELECT * FROM c WITH database=%s WITH collection=tbltemp`, dbname)); err != gocosmos.ErrExecNotSupported {
+		t.Fatalf("%s failed: expected ErrExecNotSupported but received %#v", testName, err)
+	}
+
+	query := fmt.Sprintf(`EXPLAIN SELECT CROSS PARTITION * FROM c WHERE c.grade>=@min ORDER BY c.grade DESC OFFSET 1 LIMIT 5 WITH database=%s WITH collection=tbltemp`, dbname)
+	dbRows, err := db.Query(query, sql.Named("min", 3))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("%s failed: <num-rows> expected %#v but received %#v", testName, 1, len(rows))
+	}
+	row := rows[0]
+	if !reflect.DeepEqual(row["order_by"], []string{"c.grade DESC"}) {
+		t.Fatalf("%s failed: <order_by> expected %#v but received %#v", testName, []string{"c.grade DESC"}, row["order_by"])
+	}
+	if row["offset"] != 1 || row["limit"] != 5 {
+		t.Fatalf("%s failed: <offset/limit> expected 1/5 but received %#v/%#v", testName, row["offset"], row["limit"])
+	}
+	if numPkranges, ok := row["num_pkranges"].(int); !ok || numPkranges < 1 || row["client_merge"] != (numPkranges > 1) {
+		t.Fatalf("%s failed: <num_pkranges/client_merge> received %#v/%#v", testName, row["num_pkranges"], row["client_merge"])
+	}
+}
+
 func TestStmtSelect_Query_SelectPkranges(t *testing.T) {
 	testName := "TestStmtSelect_Query_SelectPkranges"
 	db := _openDb(t, testName)
@@ -782,3 +971,102 @@ func TestStmtSelect_Query_SelectPkranges(t *testing.T) {
 		}
 	}
 }
+
+func TestStmtSelect_Query_PointReadNotFound(t *testing.T) {
+	testName := "TestStmtSelect_Query_PointReadNotFound"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p"})
+	fake.queryFilter = func(doc map[string]interface{}, params map[string]interface{}) bool { return doc["id"] == params["@1"] }
+
+	stmt, err := db.Prepare(`SELECT * FROM c WHERE c.id=@1 AND c.pk=@2 WITH collection=mytable`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	defer func() { _ = stmt.Close() }()
+	for _, testCase := range []struct {
+		id      string
+		numRows int
+	}{{"1", 1}, {"2", 0}} {
+		dbRows, err := stmt.Query(testCase.id, "p")
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName+"/"+testCase.id, err)
+		}
+		rows, err := _fetchAllRows(dbRows)
+		if err != nil || len(rows) != testCase.numRows {
+			t.Fatalf("%s failed: expected %d rows but received %#v / %s", testName+"/"+testCase.id, testCase.numRows, rows, err)
+		}
+	}
+	// the point read finding no document is confirmed by the query, as the cached partition key paths may be stale
+	if len(fake.queries) != 1 || fake.collGets != 1 {
+		t.Fatalf("%s failed: expected 1 collection fetch and 1 query but received %d / %#v", testName, fake.collGets, fake.queries)
+	}
+
+	// a missing collection is an error, not an empty result
+	fake.drop(collPath)
+	if _, err := stmt.Query("1", "p"); !errors.Is(err, gocosmos.ErrNotFound) {
+		t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName+"/dropped", err)
+	}
+	if _, err := db.Query(`SELECT * FROM c WHERE c.id=@1 AND c.pk=@2 WITH collection=mytable`, "1", "p"); !errors.Is(err, gocosmos.ErrNotFound) {
+		t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName+"/dropped_unprepared", err)
+	}
+}
+
+func TestStmtSelect_Query_PointReadCachedPk(t *testing.T) {
+	testName := "TestStmtSelect_Query_PointReadCachedPk"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	db.SetMaxOpenConns(1)
+	fake.put("/dbs/mydb/colls/mytable", map[string]interface{}{"id": "1", "pk": "p"})
+
+	// db.Query prepares a new statement each time: the partition key paths must be fetched only once
+	for i := 0; i < 3; i++ {
+		dbRows, err := db.Query(`SELECT * FROM c WHERE c.id=@1 AND c.pk=@2 WITH collection=mytable`, "1", "p")
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if rows, err := _fetchAllRows(dbRows); err != nil || len(rows) != 1 {
+			t.Fatalf("%s failed: expected 1 row but received %#v / %s", testName, rows, err)
+		}
+	}
+	if len(fake.queries) != 0 || fake.collGets != 1 {
+		t.Fatalf("%s failed: expected 1 collection fetch and no queries but received %d / %#v", testName, fake.collGets, fake.queries)
+	}
+}
+
+func TestStmtSelect_Query_PointReadStalePk(t *testing.T) {
+	testName := "TestStmtSelect_Query_PointReadStalePk"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	db.SetMaxOpenConns(1)
+	query := `SELECT * FROM c WHERE c.id=@1 AND c.pk=@2 WITH collection=mytable`
+	fake.put("/dbs/mydb/colls/mytable", map[string]interface{}{"id": "1", "pk": "p"})
+	if dbRows, err := db.Query(query, "1", "p"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	} else if rows, err := _fetchAllRows(dbRows); err != nil || len(rows) != 1 {
+		t.Fatalf("%s failed: expected 1 row but received %#v / %s", testName, rows, err)
+	}
+
+	// another client re-creates the collection with a different partition key: the point read with the cached
+	// partition key path finds nothing, the query must be executed instead of reporting no rows
+	fake.pkPath = "/tenant"
+	fake.put("/dbs/mydb/colls/mytable", map[string]interface{}{"id": "1", "pk": "p", "tenant": "t"})
+	fake.reset()
+	for i := 0; i < 2; i++ {
+		dbRows, err := db.Query(query, "1", "p")
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if rows, err := _fetchAllRows(dbRows); err != nil || len(rows) != 1 {
+			t.Fatalf("%s failed: expected 1 row but received %#v / %s", testName, rows, err)
+		}
+	}
+	// the stale paths are discarded and re-fetched once, the second query is not a point read anymore
+	if len(fake.queries) != 2 || fake.collGets != 1 {
+		t.Fatalf("%s failed: expected 1 collection fetch and 2 queries but received %d / %#v", testName, fake.collGets, fake.queries)
+	}
+}
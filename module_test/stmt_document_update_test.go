@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/btnguyen2k/gocosmos"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -884,3 +885,266 @@ func TestStmtUpdate_WithPK_SubPartitions(t *testing.T) {
 		})
 	}
 }
+
+func TestStmtUpdate_MultiDocs(t *testing.T) {
+	testName := "TestStmtUpdate_MultiDocs"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	initSqls := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/app,/username", dbname),
+	}
+	for _, initSql := range initSqls {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName, err, initSql)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		_, err := db.Exec(fmt.Sprintf(`INSERT INTO %s.tbltemp (id,app,username,grade) VALUES (:1,$2,@3,$4)`, dbname),
+			fmt.Sprintf("%d", i), fmt.Sprintf("app%d", i%2), fmt.Sprintf("user%d", i%3), i)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName+"/insert", err)
+		}
+	}
+
+	testData := []struct {
+		name         string
+		sql          string
+		args         []interface{}
+		affectedRows int64
+	}{
+		{name: "predicate", sql: `UPDATE %s.tbltemp SET active=true WHERE c.grade >= @1`, args: []interface{}{5}, affectedRows: 5},
+		{name: "predicate_concurrency", sql: `UPDATE %s.tbltemp SET level=$1 WHERE c.app = $2 AND c.grade < 9 WITH CONCURRENCY=4`, args: []interface{}{"high", "app1"}, affectedRows: 4},
+		{name: "predicate_with_pk", sql: `UPDATE %s.tbltemp SET checked=true WHERE c.username="user0" WITH pk=/app,/username`, affectedRows: 4},
+		{name: "no_match", sql: `UPDATE %s.tbltemp SET active=false WHERE c.grade > 100`, affectedRows: 0},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			execResult, err := db.Exec(fmt.Sprintf(testCase.sql, dbname), testCase.args...)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			affectedRows, err := execResult.RowsAffected()
+			if err != nil || affectedRows != testCase.affectedRows {
+				t.Fatalf("%s failed: expected %d affected-rows but received %d / %s", testName+"/"+testCase.name, testCase.affectedRows, affectedRows, err)
+			}
+		})
+	}
+
+	dbRows, err := db.Query(fmt.Sprintf(`SELECT * FROM c WHERE c.active=true AND c.checked=true WITH db=%s WITH collection=tbltemp WITH cross_partition=true`, dbname))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/select", err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil || len(rows) != 2 { // documents with ids 6 and 9
+		t.Fatalf("%s failed: expected 2 rows but received %d / %s", testName+"/select", len(rows), err)
+	}
+}
+
+func TestStmtUpdate_MultiDocsProjection(t *testing.T) {
+	testName := "TestStmtUpdate_MultiDocsProjection"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	for i := 1; i <= 3; i++ {
+		fake.put(collPath, map[string]interface{}{"id": fmt.Sprintf("%d", i), "pk": "p", "grade": 1.0, "content": strings.Repeat("x", 100)})
+	}
+
+	// all writes are done via Patch API: only id, _etag and partition key values are fetched
+	execResult, err := db.Exec(`UPDATE mytable SET grade=grade+1 WHERE c.grade > 0`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/patch", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 3 {
+		t.Fatalf("%s failed: expected 3 affected-rows but received %d / %s", testName+"/patch", affectedRows, err)
+	}
+	if len(fake.queries) != 1 || !strings.HasPrefix(fake.queries[0], `SELECT c.id, c._etag, c["pk"] AS _pk0 FROM c WHERE `) {
+		t.Fatalf("%s failed: unexpected queries %#v", testName+"/patch", fake.queries)
+	}
+	for i := 1; i <= 3; i++ {
+		if doc := fake.get(collPath, fmt.Sprintf("%d", i)); doc["grade"] != 2.0 || doc["content"] == nil {
+			t.Fatalf("%s failed: unexpected document %#v", testName+"/patch", doc)
+		}
+	}
+
+	// more than MaxPatchOperations fields: the whole documents are fetched and replaced
+	fake.reset()
+	if _, err := db.Exec(`UPDATE mytable SET a1=1, a2=2, a3=3, a4=4, a5=5, a6=6, a7=7, a8=8, a9=9, a10=10, grade=grade+1 WHERE c.grade > 0`); err != nil {
+		t.Fatalf("%s failed: %s", testName+"/replace", err)
+	}
+	if len(fake.queries) != 1 || !strings.HasPrefix(fake.queries[0], `SELECT * FROM c WHERE `) {
+		t.Fatalf("%s failed: unexpected queries %#v", testName+"/replace", fake.queries)
+	}
+	if doc := fake.get(collPath, "1"); doc["grade"] != 3.0 || doc["a10"] != 10.0 || doc["content"] == nil {
+		t.Fatalf("%s failed: unexpected document %#v", testName+"/replace", doc)
+	}
+}
+
+func TestStmtUpdate_PatchFallback(t *testing.T) {
+	testName := "TestStmtUpdate_PatchFallback"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	padding := "a1=1, a2=2, a3=3, a4=4, a5=5, a6=6, a7=7, a8=8, a9=9, "
+	testCases := []struct {
+		name  string
+		sql   string
+		patch bool
+	}{
+		{name: "single_patch", sql: `UPDATE mytable SET address.city="\"Hanoi\"", tags[1]="\"b\"" WHERE id=:1 AND pk=:2`, patch: true},
+		{name: "single_replace", sql: `UPDATE mytable SET ` + padding + `address.city="\"Hanoi\"", tags[1]="\"b\"" WHERE id=:1 AND pk=:2`},
+		{name: "multi_patch", sql: `UPDATE mytable SET address.city="\"Hanoi\"", tags[1]="\"b\"" WHERE c.id=:1 AND c.pk=:2`, patch: true},
+		{name: "multi_replace", sql: `UPDATE mytable SET ` + padding + `address.city="\"Hanoi\"", tags[1]="\"b\"" WHERE c.id=:1 AND c.pk=:2`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// the parent "address" does not exist and tags[1] appends to the array: the Patch API rejects both,
+			// the result must be the same regardless of the number of fields in the SET clause
+			fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p", "tags": []interface{}{"a"}})
+			fake.reset()
+			execResult, err := db.Exec(testCase.sql, "1", "p")
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+				t.Fatalf("%s failed: expected 1 affected-rows but received %d / %s", testName+"/"+testCase.name, affectedRows, err)
+			}
+			doc := fake.get(collPath, "1")
+			if address, _ := doc["address"].(map[string]interface{}); address["city"] != "Hanoi" {
+				t.Fatalf("%s failed: unexpected document %#v", testName+"/"+testCase.name, doc)
+			}
+			if !reflect.DeepEqual(doc["tags"], []interface{}{"a", "b"}) {
+				t.Fatalf("%s failed: unexpected document %#v", testName+"/"+testCase.name, doc)
+			}
+			patched := false
+			for _, req := range fake.requests {
+				patched = patched || strings.HasPrefix(req, "PATCH ")
+			}
+			if patched != testCase.patch {
+				t.Fatalf("%s failed: unexpected requests %#v", testName+"/"+testCase.name, fake.requests)
+			}
+		})
+	}
+}
+
+func TestStmtUpdate_PatchBadRequest(t *testing.T) {
+	testName := "TestStmtUpdate_PatchBadRequest"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	fake.patchBadRequest = "Partition key provided either doesn't correspond to definition in the collection or doesn't match partition key field values specified in the document."
+	for _, sql := range []string{`UPDATE mytable SET grade=2 WHERE id=:1 AND pk=:2`, `UPDATE mytable SET grade=2 WHERE c.id=:1 AND c.pk=:2`} {
+		// a "400 Bad Request" not caused by a target path must be returned, rather than falling back to fetch/replace
+		fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p", "grade": 1.0})
+		fake.reset()
+		if _, err := db.Exec(sql, "1", "p"); err == nil || !strings.Contains(err.Error(), "Partition key provided") {
+			t.Fatalf("%s failed: expected the Patch API error but received %#v", testName+"/"+sql, err)
+		}
+		for _, req := range fake.requests {
+			if !strings.HasPrefix(req, "PATCH ") {
+				t.Fatalf("%s failed: expected no fallback but received requests %#v", testName+"/"+sql, fake.requests)
+			}
+		}
+		if doc := fake.get(collPath, "1"); doc["grade"] != 1.0 {
+			t.Fatalf("%s failed: document must not be updated, but received %#v", testName+"/"+sql, doc)
+		}
+	}
+}
+
+func TestStmtUpdate_ReplaceConcurrentWriter(t *testing.T) {
+	testName := "TestStmtUpdate_ReplaceConcurrentWriter"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	padding := "a1=1, a2=2, a3=3, a4=4, a5=5, a6=6, a7=7, a8=8, a9=9, a10=10, "
+	sql := `UPDATE mytable SET ` + padding + `counter=counter+1 WHERE id=:1 AND pk=:2`
+
+	// another writer increments the counter right before the first replacement: the update is retried, not lost
+	fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p", "counter": 1.0})
+	concurrentWrites := 1
+	fake.beforeWrite = func(id string) {
+		if concurrentWrites > 0 {
+			concurrentWrites--
+			doc := fake.get(collPath, id)
+			doc["counter"] = doc["counter"].(float64) + 1
+			fake.put(collPath, doc)
+		}
+	}
+	execResult, err := db.Exec(sql, "1", "p")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/retry", err)
+	}
+	if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+		t.Fatalf("%s failed: expected 1 affected-rows but received %d / %s", testName+"/retry", affectedRows, err)
+	}
+	if doc := fake.get(collPath, "1"); doc["counter"] != 3.0 {
+		t.Fatalf("%s failed: expected counter 3 but received %#v", testName+"/retry", doc["counter"])
+	}
+
+	// the document keeps being modified concurrently: the statement gives up with ErrPreconditionFailure
+	concurrentWrites = 100
+	if _, err := db.Exec(sql, "1", "p"); !errors.Is(err, gocosmos.ErrPreconditionFailure) {
+		t.Fatalf("%s failed: expected ErrPreconditionFailure but received %#v", testName+"/give_up", err)
+	}
+}
+
+func TestStmtUpdate_MultiDocsConcurrentWriter(t *testing.T) {
+	testName := "TestStmtUpdate_MultiDocsConcurrentWriter"
+	fake := _newFakeCosmos()
+	defer fake.server.Close()
+	db := fake.openDb(t, testName)
+	collPath := "/dbs/mydb/colls/mytable"
+	padding := "a1=1, a2=2, a3=3, a4=4, a5=5, a6=6, a7=7, a8=8, a9=9, a10=10, "
+	testCases := []struct {
+		name string
+		sql  string
+	}{
+		{name: "patch", sql: `UPDATE mytable SET counter=counter+1 WHERE c.counter > 0`},
+		{name: "replace", sql: `UPDATE mytable SET ` + padding + `counter=counter+1 WHERE c.counter > 0`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// another writer increments the counter right after the query: the document is re-checked and its update
+			// is retried, not lost
+			fake.put(collPath, map[string]interface{}{"id": "1", "pk": "p", "counter": 1.0})
+			fake.reset()
+			concurrentWrites := 1
+			fake.beforeWrite = func(id string) {
+				if concurrentWrites > 0 {
+					concurrentWrites--
+					doc := fake.get(collPath, id)
+					doc["counter"] = doc["counter"].(float64) + 1
+					fake.put(collPath, doc)
+				}
+			}
+			defer func() { fake.beforeWrite = nil }()
+			execResult, err := db.Exec(testCase.sql)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name+"/retry", err)
+			}
+			if affectedRows, err := execResult.RowsAffected(); err != nil || affectedRows != 1 {
+				t.Fatalf("%s failed: expected 1 affected-rows but received %d / %s", testName+"/"+testCase.name+"/retry", affectedRows, err)
+			}
+			if doc := fake.get(collPath, "1"); doc["counter"] != 3.0 {
+				t.Fatalf("%s failed: expected counter 3 but received %#v", testName+"/"+testCase.name+"/retry", doc["counter"])
+			}
+			if len(fake.queries) != 2 || !strings.Contains(fake.queries[1], "c.id = @__id AND c[\"pk\"] = @__pk0 AND (c.counter > 0)") {
+				t.Fatalf("%s failed: unexpected queries %#v", testName+"/"+testCase.name+"/retry", fake.queries)
+			}
+
+			// the document keeps being modified concurrently: the statement gives up with ErrPreconditionFailure
+			concurrentWrites = 100
+			if _, err := db.Exec(testCase.sql); !errors.Is(err, gocosmos.ErrPreconditionFailure) {
+				t.Fatalf("%s failed: expected ErrPreconditionFailure but received %#v", testName+"/"+testCase.name+"/give_up", err)
+			}
+		})
+	}
+}
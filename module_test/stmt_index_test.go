@@ -0,0 +1,165 @@
+package gocosmos_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/btnguyen2k/gocosmos"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func _fetchIndexingPolicy(t *testing.T, testName string, db *sql.DB, dbColl string) string {
+	dbRows, err := db.Query("DESCRIBE " + dbColl)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/describe", err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("%s failed: %s / %#v", testName+"/describe", err, rows)
+	}
+	js, _ := json.Marshal(rows[0]["indexingPolicy"])
+	return string(js)
+}
+
+func TestStmtCreateIndex_Query(t *testing.T) {
+	testName := "TestStmtCreateIndex_Query"
+	db := _openDb(t, testName)
+	_, err := db.Query("CREATE INDEX ON dbtemp.tbltemp (/name/?)")
+	if !errors.Is(err, gocosmos.ErrQueryNotSupported) {
+		t.Fatalf("%s failed: expected ErrQueryNotSupported, but received %#v", testName, err)
+	}
+}
+
+func TestStmtDropIndex_Query(t *testing.T) {
+	testName := "TestStmtDropIndex_Query"
+	db := _openDb(t, testName)
+	_, err := db.Query("DROP INDEX ON dbtemp.tbltemp (/name/?)")
+	if !errors.Is(err, gocosmos.ErrQueryNotSupported) {
+		t.Fatalf("%s failed: expected ErrQueryNotSupported, but received %#v", testName, err)
+	}
+}
+
+func TestStmtCreateCollection_Exec_IndexingPolicy(t *testing.T) {
+	testName := "TestStmtCreateCollection_Exec_IndexingPolicy"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+
+	query := fmt.Sprintf(`CREATE COLLECTION %s.tbltemp WITH pk=/id WITH INDEXING='{"indexingMode":"consistent","includedPaths":[{"path":"/*"}],"excludedPaths":[{"path":"/content/*"}]}'`, dbname)
+	if _, err := db.Exec(query); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if policy := _fetchIndexingPolicy(t, testName, db, dbname+".tbltemp"); !strings.Contains(policy, `"/content/*"`) {
+		t.Fatalf("%s failed: <indexingPolicy> received %s", testName, policy)
+	}
+}
+
+func TestStmtCreateDropIndex_Exec(t *testing.T) {
+	testName := "TestStmtCreateDropIndex_Exec"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	_, _ = db.Exec(fmt.Sprintf("CREATE DATABASE %s", dbname))
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	if _, err := db.Exec(fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/id", dbname)); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	testData := []struct {
+		name         string
+		sql          string
+		affectedRows int64
+		mustError    error
+		contains     []string
+		notContains  []string
+	}{
+		{name: "create_excluded", sql: "CREATE EXCLUDED INDEX ON %s.tbltemp (/content/*)", affectedRows: 1, contains: []string{`"/content/*"`}},
+		{name: "create_excluded_again", sql: "CREATE EXCLUDED INDEX ON %s.tbltemp (/content/*)", mustError: gocosmos.ErrConflict},
+		{name: "create_excluded_if_not_exists", sql: "CREATE EXCLUDED INDEX IF NOT EXISTS ON %s.tbltemp (/content/*)", affectedRows: 0},
+		{name: "create_composite", sql: "CREATE COMPOSITE INDEX ON %s.tbltemp (/name ASC, /age DESC)", affectedRows: 1, contains: []string{`"/name"`, `"descending"`}},
+		{name: "create_spatial", sql: "CREATE SPATIAL INDEX ON %s.tbltemp (/location/*) WITH TYPES=Point", affectedRows: 1, contains: []string{`"/location/*"`, `"Point"`}},
+		{name: "drop_composite", sql: "DROP COMPOSITE INDEX ON %s.tbltemp (/name ASC, /age DESC)", affectedRows: 1, notContains: []string{`"/name"`}},
+		{name: "drop_excluded", sql: "DROP EXCLUDED INDEX ON %s.tbltemp (/content/*)", affectedRows: 1, notContains: []string{`"/content/*"`}},
+		{name: "drop_excluded_again", sql: "DROP EXCLUDED INDEX ON %s.tbltemp (/content/*)", mustError: gocosmos.ErrNotFound},
+		{name: "drop_excluded_if_exists", sql: "DROP EXCLUDED INDEX IF EXISTS ON %s.tbltemp (/content/*)", affectedRows: 0},
+		{name: "collection_not_found", sql: "CREATE INDEX ON %s.tbl_not_found (/name/?)", mustError: gocosmos.ErrNotFound},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			execResult, err := db.Exec(fmt.Sprintf(testCase.sql, dbname))
+			if testCase.mustError != nil {
+				if !errors.Is(err, testCase.mustError) {
+					t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+testCase.name, testCase.mustError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			affectedRows, err := execResult.RowsAffected()
+			if err != nil || affectedRows != testCase.affectedRows {
+				t.Fatalf("%s failed: expected %#v affected-rows but received %#v / %s", testName+"/"+testCase.name, testCase.affectedRows, affectedRows, err)
+			}
+			policy := _fetchIndexingPolicy(t, testName+"/"+testCase.name, db, dbname+".tbltemp")
+			for _, s := range testCase.contains {
+				if !strings.Contains(policy, s) {
+					t.Fatalf("%s failed: expected %s in <indexingPolicy> but received %s", testName+"/"+testCase.name, s, policy)
+				}
+			}
+			for _, s := range testCase.notContains {
+				if strings.Contains(policy, s) {
+					t.Fatalf("%s failed: unexpected %s in <indexingPolicy> received %s", testName+"/"+testCase.name, s, policy)
+				}
+			}
+		})
+	}
+}
+
+func TestStmtCreateIndex_Exec_RetainsConflictPolicy(t *testing.T) {
+	testName := "TestStmtCreateIndex_Exec_RetainsConflictPolicy"
+	var mutex sync.Mutex
+	var replaceBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"id":"myaccount"}`))
+		case r.Method == "PUT":
+			_ = json.NewDecoder(r.Body).Decode(&replaceBody)
+			_, _ = w.Write([]byte(`{"id":"mytable","_rid":"collrid"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"mytable","_rid":"collrid","partitionKey":{"paths":["/pk"],"kind":"Hash"},` +
+				`"conflictResolutionPolicy":{"mode":"Custom","conflictResolutionProcedure":"dbs/mydb/colls/mytable/sprocs/resolver"}}`))
+		}
+	}))
+	defer server.Close()
+	db, err := sql.Open("gocosmos", "AccountEndpoint="+server.URL+";AccountKey="+_testAccountKey+";DefaultDb=mydb")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/sql.Open", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// replacing the indexing policy must not reset the collection's custom conflict resolution policy
+	if _, err := db.Exec("CREATE EXCLUDED INDEX ON mytable (/content/*)"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if policy, _ := replaceBody["conflictResolutionPolicy"].(map[string]interface{}); policy["mode"] != "Custom" ||
+		policy["conflictResolutionProcedure"] != "dbs/mydb/colls/mytable/sprocs/resolver" {
+		t.Fatalf("%s failed: unexpected conflict resolution policy sent %#v", testName, replaceBody["conflictResolutionPolicy"])
+	}
+}
@@ -0,0 +1,91 @@
+package gocosmos_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/btnguyen2k/gocosmos"
+	"testing"
+)
+
+func TestStmtCreateScript_Query(t *testing.T) {
+	testName := "TestStmtCreateScript_Query"
+	db := _openDb(t, testName)
+	_, err := db.Query("CREATE PROCEDURE dbtemp.tbltemp.sp AS 'function(){}'")
+	if !errors.Is(err, gocosmos.ErrQueryNotSupported) {
+		t.Fatalf("%s failed: expected ErrQueryNotSupported, but received %#v", testName, err)
+	}
+}
+
+func TestStmtScript_Exec(t *testing.T) {
+	testName := "TestStmtScript_Exec"
+	db := _openDb(t, testName)
+	dbname := "dbtemp"
+	defer func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname))
+	}()
+	for _, initSql := range []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname),
+		fmt.Sprintf("CREATE DATABASE %s", dbname),
+		fmt.Sprintf("CREATE COLLECTION %s.tbltemp WITH pk=/id", dbname),
+	} {
+		if _, err := db.Exec(initSql); err != nil {
+			t.Fatalf("%s failed: {error: %s / sql: %s}", testName+"/init", err, initSql)
+		}
+	}
+
+	testData := []struct {
+		name         string
+		sql          string
+		args         []interface{}
+		mustConflict bool
+		mustNotFound bool
+		affectedRows int64
+	}{
+		{name: "create_procedure", sql: fmt.Sprintf("CREATE PROCEDURE %s.tbltemp.sp1 AS 'function() { getContext().getResponse().setBody(''ok''); }'", dbname), affectedRows: 1},
+		{name: "create_procedure_conflict", sql: fmt.Sprintf("CREATE PROCEDURE %s.tbltemp.sp1 AS 'function() {}'", dbname), mustConflict: true},
+		{name: "replace_procedure", sql: fmt.Sprintf("CREATE OR REPLACE PROCEDURE %s.tbltemp.sp1 AS @1", dbname), args: []interface{}{"function() {}"}, affectedRows: 1},
+		{name: "create_function", sql: fmt.Sprintf("CREATE FUNCTION %s.tbltemp.udf1 AS 'function(x) { return x * 2; }'", dbname), affectedRows: 1},
+		{name: "create_trigger", sql: fmt.Sprintf("CREATE TRIGGER %s.tbltemp.t1 AS 'function() {}' WITH TYPE=PRE, OPERATION=CREATE", dbname), affectedRows: 1},
+		{name: "replace_trigger", sql: fmt.Sprintf("CREATE OR REPLACE TRIGGER %s.tbltemp.t1 AS 'function() {}' WITH TYPE=POST", dbname), affectedRows: 1},
+		{name: "drop_procedure", sql: fmt.Sprintf("DROP PROCEDURE %s.tbltemp.sp1", dbname), affectedRows: 1},
+		{name: "drop_procedure_not_found", sql: fmt.Sprintf("DROP PROCEDURE %s.tbltemp.sp1", dbname), mustNotFound: true},
+		{name: "drop_procedure_if_exists", sql: fmt.Sprintf("DROP PROCEDURE IF EXISTS %s.tbltemp.sp1", dbname), affectedRows: 0},
+		{name: "drop_function", sql: fmt.Sprintf("DROP FUNCTION %s.tbltemp.udf1", dbname), affectedRows: 1},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			execResult, err := db.Exec(testCase.sql, testCase.args...)
+			if testCase.mustConflict && !errors.Is(err, gocosmos.ErrConflict) {
+				t.Fatalf("%s failed: expect ErrConflict but received %#v", testName+"/"+testCase.name+"/exec", err)
+			}
+			if testCase.mustNotFound && !errors.Is(err, gocosmos.ErrNotFound) {
+				t.Fatalf("%s failed: expect ErrNotFound but received %#v", testName+"/"+testCase.name+"/exec", err)
+			}
+			if testCase.mustConflict || testCase.mustNotFound {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name+"/exec", err)
+			}
+			affectedRows, err := execResult.RowsAffected()
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name+"/rows_affected", err)
+			}
+			if affectedRows != testCase.affectedRows {
+				t.Fatalf("%s failed: expected %#v affected-rows but received %#v", testName+"/"+testCase.name, testCase.affectedRows, affectedRows)
+			}
+		})
+	}
+
+	dbRows, err := db.Query(fmt.Sprintf("LIST TRIGGERS FROM %s.tbltemp", dbname))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/list", err)
+	}
+	rows, err := _fetchAllRows(dbRows)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/list", err)
+	}
+	if len(rows) != 1 || rows[0]["id"] != "t1" || rows[0]["triggerType"] != "Post" || rows[0]["triggerOperation"] != "All" {
+		t.Fatalf("%s failed: unexpected rows %#v", testName+"/list", rows)
+	}
+}
@@ -2,6 +2,7 @@ package gocosmos
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/tls"
@@ -15,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/btnguyen2k/consu/checksum"
@@ -30,6 +32,7 @@ const (
 	settingVersion            = "VERSION"
 	settingAutoId             = "AUTOID"
 	settingInsecureSkipVerify = "INSECURESKIPVERIFY"
+	settingPreferredRegions   = "PREFERREDREGIONS"
 
 	// DefaultApiVersion holds the default REST API version if not specified in the connection string.
 	//
@@ -44,12 +47,14 @@ const (
 // httpClient is reused if supplied. Otherwise, a new http.Client instance is created.
 // connStr is expected to be in the following format:
 //
-//	AccountEndpoint=<cosmosdb-restapi-endpoint>;AccountKey=<account-key>[;TimeoutMs=<timeout-in-ms>][;Version=<cosmosdb-api-version>][;AutoId=<true/false>][;InsecureSkipVerify=<true/false>]
+//	AccountEndpoint=<cosmosdb-restapi-endpoint>;AccountKey=<account-key>[;TimeoutMs=<timeout-in-ms>][;Version=<cosmosdb-api-version>][;AutoId=<true/false>][;InsecureSkipVerify=<true/false>][;PreferredRegions=<region1>,<region2>...]
 //
 // If not supplied, default value for TimeoutMs is 10 seconds, Version is DefaultApiVersion (which is "2020-07-15"), AutoId is true, and InsecureSkipVerify is false
 //
 // - AutoId is added since v0.1.2
 // - InsecureSkipVerify is added since v0.1.4
+// - PreferredRegions is added since v1.2.0: if supplied, the client discovers the account's regions, routes reads to the first available preferred region and writes to the write region, and fails over automatically.
+// The region topology is then refreshed in the background every 5 minutes, until Close is called.
 func NewRestClient(httpClient *http.Client, connStr string) (*RestClient, error) {
 	params := make(map[string]string)
 	parts := strings.Split(connStr, ";")
@@ -96,14 +101,23 @@ func NewRestClient(httpClient *http.Client, connStr string) (*RestClient, error)
 			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify}},
 		}
 	}
-	return &RestClient{
+	var regions *regionRouter
+	if preferredRegions := strings.TrimSpace(params[settingPreferredRegions]); preferredRegions != "" {
+		regions = newRegionRouter(endpoint, strings.Split(preferredRegions, ","))
+	}
+	client := &RestClient{
 		client:     gjrc.NewGjrc(httpClient, time.Duration(timeoutMs)*time.Millisecond),
 		endpoint:   endpoint,
 		authKey:    key,
 		apiVersion: apiVersion,
 		autoId:     autoId,
 		params:     params,
-	}, nil
+		regions:    regions,
+	}
+	if regions != nil {
+		regions.start(client)
+	}
+	return client, nil
 }
 
 // RestClient is REST-based client for Azure Cosmos DB
@@ -114,6 +128,15 @@ type RestClient struct {
 	apiVersion string            // Azure Cosmos DB API version
 	autoId     bool              // if true and value for 'id' field is not specified, CreateDocument
 	params     map[string]string // parsed parameters
+	regions    *regionRouter     // (since v1.2.0) multi-region routing, nil if not enabled
+
+	accountMutex        sync.RWMutex
+	account             *DatabaseAccountInfo // (since v1.2.0) cached account info, used to validate requested consistency levels
+	accountFetchFailure time.Time            // (since v1.2.0) last time fetching the account info failed
+	accountFetching     int32                // (since v1.2.0) 1 if the account info is being fetched in the background
+
+	pkPathsMutex sync.RWMutex
+	pkPaths      map[string]pkPathsEntry // (since v1.2.0) cached partition key paths of collections, keyed by "<db-name>/<collection-name>"
 }
 
 func (c *RestClient) buildJsonRequest(method, url string, params interface{}) (*http.Request, error) {
@@ -218,6 +241,17 @@ func (c *RestClient) SetAutoId(value bool) *RestClient {
 	return c
 }
 
+// Close stops the background refresh of the region topology (see PreferredRegions in NewRestClient).
+// The client can still be used after Close, in which case the background refresh is restarted.
+//
+// @Available since v1.2.0
+func (c *RestClient) Close() error {
+	if c.regions != nil {
+		c.regions.stop()
+	}
+	return nil
+}
+
 /*----------------------------------------------------------------------*/
 
 // DatabaseSpec specifies a Cosmos DB database specifications for creation.
@@ -245,7 +279,7 @@ func (c *RestClient) CreateDatabase(spec DatabaseSpec) *RespCreateDb {
 		req.Header.Set(restApiHeaderOfferAutopilotSettings, fmt.Sprintf(`{"maxThroughput":%d}`, spec.MaxRu))
 	}
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespCreateDb{RestResponse: c.buildRestResponse(resp), DbInfo: DbInfo{Id: spec.Id}}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.DbInfo))
@@ -264,7 +298,7 @@ func (c *RestClient) GetDatabase(dbName string) *RespGetDb {
 	}
 	req = c.addAuthHeader(req, method, "dbs", "dbs/"+dbName)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespGetDb{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.DbInfo))
@@ -283,7 +317,7 @@ func (c *RestClient) DeleteDatabase(dbName string) *RespDeleteDb {
 	}
 	req = c.addAuthHeader(req, method, "dbs", "dbs/"+dbName)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespDeleteDb{RestResponse: c.buildRestResponse(resp)}
 	return result
 }
@@ -299,7 +333,7 @@ func (c *RestClient) ListDatabases() *RespListDb {
 	}
 	req = c.addAuthHeader(req, method, "dbs", "")
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespListDb{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &result)
@@ -325,6 +359,14 @@ type CollectionSpec struct {
 	PartitionKeyInfo map[string]interface{}
 	IndexingPolicy   map[string]interface{}
 	UniqueKeyPolicy  map[string]interface{}
+	// ConflictResolutionPolicy (since v1.2.0) specifies how conflicts are resolved in multi-region writes accounts, for example:
+	// {"mode":"LastWriterWins","conflictResolutionPath":"/_ts"} or {"mode":"Custom","conflictResolutionProcedure":"dbs/db/colls/coll/sprocs/sp"}.
+	// Note: the conflict resolution policy can not be changed once the collection is created; ReplaceCollection sends it as-is,
+	// hence it must be the collection's existing policy (or nil, which the server takes as the default policy).
+	ConflictResolutionPolicy map[string]interface{}
+	// DefaultTtl (since v1.2.0) specifies the default time-to-live of documents in seconds, -1 means documents do not expire unless
+	// they have their own "ttl" field. nil means TTL is off; note that ReplaceCollection with nil DefaultTtl turns TTL off.
+	DefaultTtl *int
 }
 
 // CreateCollection invokes Cosmos DB API to create a new collection.
@@ -341,6 +383,12 @@ func (c *RestClient) CreateCollection(spec CollectionSpec) *RespCreateColl {
 	if spec.UniqueKeyPolicy != nil {
 		params[restApiParamUniqueKeyPolicy] = spec.UniqueKeyPolicy
 	}
+	if spec.ConflictResolutionPolicy != nil {
+		params[restApiParamConflictResolutionPolicy] = spec.ConflictResolutionPolicy
+	}
+	if spec.DefaultTtl != nil {
+		params[restApiParamDefaultTtl] = *spec.DefaultTtl
+	}
 	req, err := c.buildJsonRequest(method, urlEndpoint, params)
 	if err != nil {
 		return &RespCreateColl{RestResponse: RestResponse{CallErr: err}, CollInfo: CollInfo{Id: spec.CollName}}
@@ -353,7 +401,7 @@ func (c *RestClient) CreateCollection(spec CollectionSpec) *RespCreateColl {
 		req.Header.Set(restApiHeaderOfferAutopilotSettings, fmt.Sprintf(`{"maxThroughput":%d}`, spec.MaxRu))
 	}
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespCreateColl{RestResponse: c.buildRestResponse(resp), CollInfo: CollInfo{Id: spec.CollName}}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.CollInfo))
@@ -375,6 +423,13 @@ func (c *RestClient) ReplaceCollection(spec CollectionSpec) *RespReplaceColl {
 	if spec.IndexingPolicy != nil {
 		params[restApiParamIndexingPolicy] = spec.IndexingPolicy
 	}
+	if spec.ConflictResolutionPolicy != nil {
+		// (since v1.2.0) the policy can not be changed, but omitting it resets a custom policy to the default one
+		params[restApiParamConflictResolutionPolicy] = spec.ConflictResolutionPolicy
+	}
+	if spec.DefaultTtl != nil {
+		params[restApiParamDefaultTtl] = *spec.DefaultTtl
+	}
 	// The unique index cannot be modified. To change the unique index, remove the collection and re-create a new one.
 	// if spec.UniqueKeyPolicy != nil {
 	// 	params[restApiParamUniqueKeyPolicy] = spec.UniqueKeyPolicy
@@ -391,7 +446,7 @@ func (c *RestClient) ReplaceCollection(spec CollectionSpec) *RespReplaceColl {
 		req.Header.Set(restApiHeaderOfferAutopilotSettings, fmt.Sprintf(`{"maxThroughput":%d}`, spec.MaxRu))
 	}
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespReplaceColl{RestResponse: c.buildRestResponse(resp), CollInfo: CollInfo{Id: spec.CollName}}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.CollInfo))
@@ -401,23 +456,110 @@ func (c *RestClient) ReplaceCollection(spec CollectionSpec) *RespReplaceColl {
 
 // GetCollection invokes Cosmos DB API to get an existing collection.
 //
+// (since v1.2.0) The progress of re-indexing the collection after its indexing policy has changed is reported via RespGetColl.IndexTransformationProgress.
+//
 // See: https://docs.microsoft.com/en-us/rest/api/cosmos-db/get-a-collection
 func (c *RestClient) GetCollection(dbName, collName string) *RespGetColl {
 	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName
 	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
 	if err != nil {
-		return &RespGetColl{RestResponse: RestResponse{CallErr: err}}
+		return &RespGetColl{RestResponse: RestResponse{CallErr: err}, IndexTransformationProgress: -1}
 	}
 	req = c.addAuthHeader(req, method, "colls", "dbs/"+dbName+"/colls/"+collName)
+	req.Header.Set(restApiHeaderPopulateQuotaInfo, "true")
 
-	resp := c.client.Do(req)
-	result := &RespGetColl{RestResponse: c.buildRestResponse(resp)}
+	resp := c.do(req)
+	result := &RespGetColl{RestResponse: c.buildRestResponse(resp), IndexTransformationProgress: -1}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.CollInfo))
+		if v, err := strconv.Atoi(result.RespHeader[respHeaderIndexTransformationProgress]); err == nil {
+			result.IndexTransformationProgress = v
+		}
+	}
+	if result.Error() == nil {
+		c.cachePkPaths(dbName, collName, pkPathsEntry{paths: result.CollInfo.PartitionKey.Paths(), fetchedAt: time.Now()})
 	}
 	return result
 }
 
+const (
+	// pkPathsCacheTtl is how long the cached partition key paths of a collection are used before being fetched again.
+	pkPathsCacheTtl = 5 * time.Minute
+
+	// pkPathsRetryInterval is how long a failure to fetch the partition key paths of a collection is cached.
+	pkPathsRetryInterval = 1 * time.Minute
+)
+
+// pkPathsEntry is a cached result of fetching the partition key paths of a collection.
+type pkPathsEntry struct {
+	paths     []string
+	fetchedAt time.Time // zero if fetching failed
+	failedAt  time.Time // zero if fetching succeeded
+}
+
+func (c *RestClient) cachePkPaths(dbName, collName string, entry pkPathsEntry) {
+	c.pkPathsMutex.Lock()
+	defer c.pkPathsMutex.Unlock()
+	if c.pkPaths == nil {
+		c.pkPaths = make(map[string]pkPathsEntry)
+	}
+	c.pkPaths[dbName+"/"+collName] = entry
+}
+
+func (c *RestClient) forgetPkPaths(dbName, collName string) {
+	c.pkPathsMutex.Lock()
+	defer c.pkPathsMutex.Unlock()
+	delete(c.pkPaths, dbName+"/"+collName)
+}
+
+// collectionPkPaths returns the partition key paths of a collection, fetched via GetCollection and cached, so that
+// statements do not need a round-trip to the server each time they are prepared. ok is false if the paths are not
+// available, i.e. fetching them failed (the failure is cached for a minute).
+//
+// @Available since v1.2.0
+func (c *RestClient) collectionPkPaths(dbName, collName string) (paths []string, ok bool) {
+	c.pkPathsMutex.RLock()
+	entry, cached := c.pkPaths[dbName+"/"+collName]
+	c.pkPathsMutex.RUnlock()
+	if cached && time.Since(entry.fetchedAt) < pkPathsCacheTtl {
+		return entry.paths, true
+	}
+	if cached && time.Since(entry.failedAt) < pkPathsRetryInterval {
+		return nil, false
+	}
+	getCollResult := c.GetCollection(dbName, collName)
+	if getCollResult.Error() != nil {
+		c.cachePkPaths(dbName, collName, pkPathsEntry{failedAt: time.Now()})
+		return nil, false
+	}
+	return getCollResult.CollInfo.PartitionKey.Paths(), true
+}
+
+// indexTransformationPollInterval is the interval between two consecutive polls of WaitForIndexTransformation.
+var indexTransformationPollInterval = 1 * time.Second
+
+// WaitForIndexTransformation polls an existing collection until re-indexing it (after its indexing policy has been changed,
+// see ReplaceCollection) completes, or the context is done.
+//
+// The last response of RestClient.GetCollection is returned; its CallErr is set to the context's error if the context is done
+// before the transformation completes. If the server does not report the progress, the transformation is considered complete.
+//
+// @Available since v1.2.0
+func (c *RestClient) WaitForIndexTransformation(ctx context.Context, dbName, collName string) *RespGetColl {
+	for {
+		result := c.GetCollection(dbName, collName)
+		if result.Error() != nil || result.IndexTransformationProgress < 0 || result.IndexTransformationProgress >= 100 {
+			return result
+		}
+		select {
+		case <-ctx.Done():
+			result.CallErr = ctx.Err()
+			return result
+		case <-time.After(indexTransformationPollInterval):
+		}
+	}
+}
+
 // DeleteCollection invokes Cosmos DB API to delete an existing collection.
 //
 // See: https://docs.microsoft.com/en-us/rest/api/cosmos-db/delete-a-collection.
@@ -429,8 +571,12 @@ func (c *RestClient) DeleteCollection(dbName, collName string) *RespDeleteColl {
 	}
 	req = c.addAuthHeader(req, method, "colls", "dbs/"+dbName+"/colls/"+collName)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespDeleteColl{RestResponse: c.buildRestResponse(resp)}
+	if result.Error() == nil {
+		// (since v1.2.0) a collection re-created with the same name may have a different partition key
+		c.forgetPkPaths(dbName, collName)
+	}
 	return result
 }
 
@@ -445,7 +591,7 @@ func (c *RestClient) ListCollections(dbName string) *RespListColl {
 	}
 	req = c.addAuthHeader(req, method, "colls", "dbs/"+dbName)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespListColl{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &result)
@@ -472,7 +618,7 @@ func (c *RestClient) GetPkranges(dbName, collName string) *RespGetPkranges {
 	}
 	req = c.addAuthHeader(req, method, "pkranges", "dbs/"+dbName+"/colls/"+collName)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespGetPkranges{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &result)
@@ -482,6 +628,469 @@ func (c *RestClient) GetPkranges(dbName, collName string) *RespGetPkranges {
 
 /*----------------------------------------------------------------------*/
 
+// SprocSpec specifies a Cosmos DB stored procedure specifications for creation/replacement.
+//
+// @Available since v1.2.0
+type SprocSpec struct {
+	DbName, CollName string
+	Id               string // user-generated unique name for the stored procedure
+	Body             string // body of the stored procedure (JavaScript code)
+}
+
+// CreateSproc invokes Cosmos DB API to create a new stored procedure.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/create-a-stored-procedure.
+//
+// @Available since v1.2.0
+func (c *RestClient) CreateSproc(spec SprocSpec) *RespCreateSproc {
+	method, urlEndpoint := "POST", c.endpoint+"/dbs/"+spec.DbName+"/colls/"+spec.CollName+"/sprocs"
+	req, err := c.buildJsonRequest(method, urlEndpoint, map[string]interface{}{"id": spec.Id, "body": spec.Body})
+	if err != nil {
+		return &RespCreateSproc{RestResponse: RestResponse{CallErr: err}, SprocInfo: SprocInfo{Id: spec.Id}}
+	}
+	req = c.addAuthHeader(req, method, "sprocs", "dbs/"+spec.DbName+"/colls/"+spec.CollName)
+
+	resp := c.do(req)
+	result := &RespCreateSproc{RestResponse: c.buildRestResponse(resp), SprocInfo: SprocInfo{Id: spec.Id}}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.SprocInfo))
+	}
+	return result
+}
+
+// ReplaceSproc invokes Cosmos DB API to replace an existing stored procedure.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/replace-a-stored-procedure.
+//
+// @Available since v1.2.0
+func (c *RestClient) ReplaceSproc(spec SprocSpec) *RespReplaceSproc {
+	method, urlEndpoint := "PUT", c.endpoint+"/dbs/"+spec.DbName+"/colls/"+spec.CollName+"/sprocs/"+spec.Id
+	req, err := c.buildJsonRequest(method, urlEndpoint, map[string]interface{}{"id": spec.Id, "body": spec.Body})
+	if err != nil {
+		return &RespReplaceSproc{RestResponse: RestResponse{CallErr: err}, SprocInfo: SprocInfo{Id: spec.Id}}
+	}
+	req = c.addAuthHeader(req, method, "sprocs", "dbs/"+spec.DbName+"/colls/"+spec.CollName+"/sprocs/"+spec.Id)
+
+	resp := c.do(req)
+	result := &RespReplaceSproc{RestResponse: c.buildRestResponse(resp), SprocInfo: SprocInfo{Id: spec.Id}}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.SprocInfo))
+	}
+	return result
+}
+
+// GetSproc invokes Cosmos DB API to get an existing stored procedure.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/stored-procedures.
+//
+// @Available since v1.2.0
+func (c *RestClient) GetSproc(dbName, collName, sprocId string) *RespGetSproc {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/sprocs/"+sprocId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespGetSproc{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "sprocs", "dbs/"+dbName+"/colls/"+collName+"/sprocs/"+sprocId)
+
+	resp := c.do(req)
+	result := &RespGetSproc{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.SprocInfo))
+	}
+	return result
+}
+
+// DeleteSproc invokes Cosmos DB API to delete an existing stored procedure.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/delete-a-stored-procedure.
+//
+// @Available since v1.2.0
+func (c *RestClient) DeleteSproc(dbName, collName, sprocId string) *RespDeleteSproc {
+	method, urlEndpoint := "DELETE", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/sprocs/"+sprocId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespDeleteSproc{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "sprocs", "dbs/"+dbName+"/colls/"+collName+"/sprocs/"+sprocId)
+
+	resp := c.do(req)
+	result := &RespDeleteSproc{RestResponse: c.buildRestResponse(resp)}
+	return result
+}
+
+// ListSprocs invokes Cosmos DB API to list all stored procedures of a collection.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/list-stored-procedures.
+//
+// @Available since v1.2.0
+func (c *RestClient) ListSprocs(dbName, collName string) *RespListSprocs {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/sprocs"
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespListSprocs{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "sprocs", "dbs/"+dbName+"/colls/"+collName)
+
+	resp := c.do(req)
+	result := &RespListSprocs{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &result)
+		if result.CallErr == nil {
+			sort.Slice(result.Sprocs, func(i, j int) bool {
+				// sort stored procedures by id
+				return result.Sprocs[i].Id < result.Sprocs[j].Id
+			})
+		}
+	}
+	return result
+}
+
+// UdfSpec specifies a Cosmos DB user-defined function specifications for creation/replacement.
+//
+// @Available since v1.2.0
+type UdfSpec struct {
+	DbName, CollName string
+	Id               string // user-generated unique name for the user-defined function
+	Body             string // body of the user-defined function (JavaScript code)
+}
+
+// CreateUdf invokes Cosmos DB API to create a new user-defined function.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/create-a-user-defined-function.
+//
+// @Available since v1.2.0
+func (c *RestClient) CreateUdf(spec UdfSpec) *RespCreateUdf {
+	method, urlEndpoint := "POST", c.endpoint+"/dbs/"+spec.DbName+"/colls/"+spec.CollName+"/udfs"
+	req, err := c.buildJsonRequest(method, urlEndpoint, map[string]interface{}{"id": spec.Id, "body": spec.Body})
+	if err != nil {
+		return &RespCreateUdf{RestResponse: RestResponse{CallErr: err}, UdfInfo: UdfInfo{Id: spec.Id}}
+	}
+	req = c.addAuthHeader(req, method, "udfs", "dbs/"+spec.DbName+"/colls/"+spec.CollName)
+
+	resp := c.do(req)
+	result := &RespCreateUdf{RestResponse: c.buildRestResponse(resp), UdfInfo: UdfInfo{Id: spec.Id}}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.UdfInfo))
+	}
+	return result
+}
+
+// ReplaceUdf invokes Cosmos DB API to replace an existing user-defined function.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/replace-a-user-defined-function.
+//
+// @Available since v1.2.0
+func (c *RestClient) ReplaceUdf(spec UdfSpec) *RespReplaceUdf {
+	method, urlEndpoint := "PUT", c.endpoint+"/dbs/"+spec.DbName+"/colls/"+spec.CollName+"/udfs/"+spec.Id
+	req, err := c.buildJsonRequest(method, urlEndpoint, map[string]interface{}{"id": spec.Id, "body": spec.Body})
+	if err != nil {
+		return &RespReplaceUdf{RestResponse: RestResponse{CallErr: err}, UdfInfo: UdfInfo{Id: spec.Id}}
+	}
+	req = c.addAuthHeader(req, method, "udfs", "dbs/"+spec.DbName+"/colls/"+spec.CollName+"/udfs/"+spec.Id)
+
+	resp := c.do(req)
+	result := &RespReplaceUdf{RestResponse: c.buildRestResponse(resp), UdfInfo: UdfInfo{Id: spec.Id}}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.UdfInfo))
+	}
+	return result
+}
+
+// GetUdf invokes Cosmos DB API to get an existing user-defined function.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/user-defined-functions.
+//
+// @Available since v1.2.0
+func (c *RestClient) GetUdf(dbName, collName, udfId string) *RespGetUdf {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/udfs/"+udfId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespGetUdf{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "udfs", "dbs/"+dbName+"/colls/"+collName+"/udfs/"+udfId)
+
+	resp := c.do(req)
+	result := &RespGetUdf{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.UdfInfo))
+	}
+	return result
+}
+
+// DeleteUdf invokes Cosmos DB API to delete an existing user-defined function.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/delete-a-user-defined-function.
+//
+// @Available since v1.2.0
+func (c *RestClient) DeleteUdf(dbName, collName, udfId string) *RespDeleteUdf {
+	method, urlEndpoint := "DELETE", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/udfs/"+udfId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespDeleteUdf{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "udfs", "dbs/"+dbName+"/colls/"+collName+"/udfs/"+udfId)
+
+	resp := c.do(req)
+	result := &RespDeleteUdf{RestResponse: c.buildRestResponse(resp)}
+	return result
+}
+
+// ListUdfs invokes Cosmos DB API to list all user-defined functions of a collection.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/list-user-defined-functions.
+//
+// @Available since v1.2.0
+func (c *RestClient) ListUdfs(dbName, collName string) *RespListUdfs {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/udfs"
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespListUdfs{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "udfs", "dbs/"+dbName+"/colls/"+collName)
+
+	resp := c.do(req)
+	result := &RespListUdfs{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &result)
+		if result.CallErr == nil {
+			sort.Slice(result.Udfs, func(i, j int) bool {
+				// sort user-defined functions by id
+				return result.Udfs[i].Id < result.Udfs[j].Id
+			})
+		}
+	}
+	return result
+}
+
+// TriggerSpec specifies a Cosmos DB trigger specifications for creation/replacement.
+//
+// @Available since v1.2.0
+type TriggerSpec struct {
+	DbName, CollName string
+	Id               string // user-generated unique name for the trigger
+	Body             string // body of the trigger (JavaScript code)
+	TriggerType      string // accepted values: "Pre" or "Post"
+	TriggerOperation string // accepted values: "All", "Create", "Replace" or "Delete"
+}
+
+func (spec TriggerSpec) toParams() map[string]interface{} {
+	return map[string]interface{}{
+		"id":               spec.Id,
+		"body":             spec.Body,
+		"triggerType":      spec.TriggerType,
+		"triggerOperation": spec.TriggerOperation,
+	}
+}
+
+// CreateTrigger invokes Cosmos DB API to create a new trigger.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/create-a-trigger.
+//
+// @Available since v1.2.0
+func (c *RestClient) CreateTrigger(spec TriggerSpec) *RespCreateTrigger {
+	method, urlEndpoint := "POST", c.endpoint+"/dbs/"+spec.DbName+"/colls/"+spec.CollName+"/triggers"
+	req, err := c.buildJsonRequest(method, urlEndpoint, spec.toParams())
+	if err != nil {
+		return &RespCreateTrigger{RestResponse: RestResponse{CallErr: err}, TriggerInfo: TriggerInfo{Id: spec.Id}}
+	}
+	req = c.addAuthHeader(req, method, "triggers", "dbs/"+spec.DbName+"/colls/"+spec.CollName)
+
+	resp := c.do(req)
+	result := &RespCreateTrigger{RestResponse: c.buildRestResponse(resp), TriggerInfo: TriggerInfo{Id: spec.Id}}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.TriggerInfo))
+	}
+	return result
+}
+
+// ReplaceTrigger invokes Cosmos DB API to replace an existing trigger.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/replace-a-trigger.
+//
+// @Available since v1.2.0
+func (c *RestClient) ReplaceTrigger(spec TriggerSpec) *RespReplaceTrigger {
+	method, urlEndpoint := "PUT", c.endpoint+"/dbs/"+spec.DbName+"/colls/"+spec.CollName+"/triggers/"+spec.Id
+	req, err := c.buildJsonRequest(method, urlEndpoint, spec.toParams())
+	if err != nil {
+		return &RespReplaceTrigger{RestResponse: RestResponse{CallErr: err}, TriggerInfo: TriggerInfo{Id: spec.Id}}
+	}
+	req = c.addAuthHeader(req, method, "triggers", "dbs/"+spec.DbName+"/colls/"+spec.CollName+"/triggers/"+spec.Id)
+
+	resp := c.do(req)
+	result := &RespReplaceTrigger{RestResponse: c.buildRestResponse(resp), TriggerInfo: TriggerInfo{Id: spec.Id}}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.TriggerInfo))
+	}
+	return result
+}
+
+// GetTrigger invokes Cosmos DB API to get an existing trigger.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/triggers.
+//
+// @Available since v1.2.0
+func (c *RestClient) GetTrigger(dbName, collName, triggerId string) *RespGetTrigger {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/triggers/"+triggerId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespGetTrigger{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "triggers", "dbs/"+dbName+"/colls/"+collName+"/triggers/"+triggerId)
+
+	resp := c.do(req)
+	result := &RespGetTrigger{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.TriggerInfo))
+	}
+	return result
+}
+
+// DeleteTrigger invokes Cosmos DB API to delete an existing trigger.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/delete-a-trigger.
+//
+// @Available since v1.2.0
+func (c *RestClient) DeleteTrigger(dbName, collName, triggerId string) *RespDeleteTrigger {
+	method, urlEndpoint := "DELETE", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/triggers/"+triggerId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespDeleteTrigger{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "triggers", "dbs/"+dbName+"/colls/"+collName+"/triggers/"+triggerId)
+
+	resp := c.do(req)
+	result := &RespDeleteTrigger{RestResponse: c.buildRestResponse(resp)}
+	return result
+}
+
+// ListTriggers invokes Cosmos DB API to list all triggers of a collection.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/list-triggers.
+//
+// @Available since v1.2.0
+func (c *RestClient) ListTriggers(dbName, collName string) *RespListTriggers {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/triggers"
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespListTriggers{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "triggers", "dbs/"+dbName+"/colls/"+collName)
+
+	resp := c.do(req)
+	result := &RespListTriggers{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &result)
+		if result.CallErr == nil {
+			sort.Slice(result.Triggers, func(i, j int) bool {
+				// sort triggers by id
+				return result.Triggers[i].Id < result.Triggers[j].Id
+			})
+		}
+	}
+	return result
+}
+
+/*----------------------------------------------------------------------*/
+
+// ConflictReq specifies a request to access an item in the conflicts feed of a collection.
+//
+// @Available since v1.2.0
+type ConflictReq struct {
+	DbName, CollName, ConflictId string
+	PartitionKeyValues           []interface{} // partition key values of the conflicting document, can be omitted
+}
+
+// ListConflicts invokes Cosmos DB API to list all items in the conflicts feed of a collection.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/conflicts.
+//
+// @Available since v1.2.0
+func (c *RestClient) ListConflicts(dbName, collName string) *RespListConflicts {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/conflicts"
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespListConflicts{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "conflicts", "dbs/"+dbName+"/colls/"+collName)
+	req.Header.Set(restApiHeaderEnableCrossPartitionQuery, "true")
+	req.Header.Set(restApiHeaderPageSize, "100")
+
+	var result *RespListConflicts
+	for {
+		resp := c.do(req)
+		tempResult := &RespListConflicts{RestResponse: c.buildRestResponse(resp)}
+		if tempResult.CallErr == nil {
+			tempResult.CallErr = json.Unmarshal(tempResult.RespBody, &tempResult)
+		}
+		continuationToken := tempResult.RespHeader[respHeaderContinuation]
+		if result == nil {
+			result = tempResult
+		} else {
+			requestCharge := result.RequestCharge + tempResult.RequestCharge
+			result.RestResponse = tempResult.RestResponse
+			result.RequestCharge = requestCharge
+			result.Count += tempResult.Count
+			result.Conflicts = append(result.Conflicts, tempResult.Conflicts...)
+		}
+		if result.CallErr != nil || continuationToken == "" {
+			break
+		}
+		req.Header.Set(restApiHeaderContinuation, continuationToken)
+	}
+	return result
+}
+
+// GetConflict invokes Cosmos DB API to get an item in the conflicts feed of a collection.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/conflicts.
+//
+// @Available since v1.2.0
+func (c *RestClient) GetConflict(r ConflictReq) *RespGetConflict {
+	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+r.DbName+"/colls/"+r.CollName+"/conflicts/"+r.ConflictId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespGetConflict{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "conflicts", "dbs/"+r.DbName+"/colls/"+r.CollName+"/conflicts/"+r.ConflictId)
+	if len(r.PartitionKeyValues) > 0 {
+		jsPkValues, _ := json.Marshal(r.PartitionKeyValues)
+		req.Header.Set(restApiHeaderPartitionKey, string(jsPkValues))
+	}
+
+	resp := c.do(req)
+	result := &RespGetConflict{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.ConflictInfo))
+	}
+	return result
+}
+
+// DeleteConflict invokes Cosmos DB API to delete an item from the conflicts feed of a collection.
+//
+// Deleting a conflict marks it as resolved; the conflicting version of the document is discarded.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/conflicts.
+//
+// @Available since v1.2.0
+func (c *RestClient) DeleteConflict(r ConflictReq) *RespDeleteConflict {
+	method, urlEndpoint := "DELETE", c.endpoint+"/dbs/"+r.DbName+"/colls/"+r.CollName+"/conflicts/"+r.ConflictId
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespDeleteConflict{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "conflicts", "dbs/"+r.DbName+"/colls/"+r.CollName+"/conflicts/"+r.ConflictId)
+	if len(r.PartitionKeyValues) > 0 {
+		jsPkValues, _ := json.Marshal(r.PartitionKeyValues)
+		req.Header.Set(restApiHeaderPartitionKey, string(jsPkValues))
+	}
+
+	resp := c.do(req)
+	result := &RespDeleteConflict{RestResponse: c.buildRestResponse(resp)}
+	return result
+}
+
+/*----------------------------------------------------------------------*/
+
 // DocumentSpec specifies a Cosmos DB document specifications for creation.
 type DocumentSpec struct {
 	DbName, CollName   string
@@ -489,6 +1098,20 @@ type DocumentSpec struct {
 	IndexingDirective  string // accepted value "", "Include" or "Exclude"
 	PartitionKeyValues []interface{}
 	DocumentData       DocInfo
+	PreTriggerInclude  []string // (since v1.2.0) ids of pre-triggers to be executed as part of the operation
+	PostTriggerInclude []string // (since v1.2.0) ids of post-triggers to be executed as part of the operation
+}
+
+// addTriggerIncludeHeaders adds the headers to invoke pre-triggers and post-triggers as part of the request.
+//
+// @Available since v1.2.0
+func addTriggerIncludeHeaders(req *http.Request, preTriggers, postTriggers []string) {
+	if len(preTriggers) > 0 {
+		req.Header.Set(restApiHeaderPreTriggerInclude, strings.Join(preTriggers, ","))
+	}
+	if len(postTriggers) > 0 {
+		req.Header.Set(restApiHeaderPostTriggerInclude, strings.Join(postTriggers, ","))
+	}
 }
 
 // CreateDocument invokes Cosmos DB API to create a new document.
@@ -514,8 +1137,9 @@ func (c *RestClient) CreateDocument(spec DocumentSpec) *RespCreateDoc {
 	}
 	jsPkValues, _ := json.Marshal(spec.PartitionKeyValues)
 	req.Header.Set(restApiHeaderPartitionKey, string(jsPkValues))
+	addTriggerIncludeHeaders(req, spec.PreTriggerInclude, spec.PostTriggerInclude)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespCreateDoc{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.DocInfo))
@@ -539,8 +1163,9 @@ func (c *RestClient) ReplaceDocument(matchEtag string, spec DocumentSpec) *RespR
 	}
 	jsPkValues, _ := json.Marshal(spec.PartitionKeyValues)
 	req.Header.Set(restApiHeaderPartitionKey, string(jsPkValues))
+	addTriggerIncludeHeaders(req, spec.PreTriggerInclude, spec.PostTriggerInclude)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespReplaceDoc{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.DocInfo))
@@ -548,20 +1173,101 @@ func (c *RestClient) ReplaceDocument(matchEtag string, spec DocumentSpec) *RespR
 	return result
 }
 
+// MaxPatchOperations is the maximum number of operations allowed in one RestClient.PatchDocument call.
+//
+// @Available since v1.2.0
+const MaxPatchOperations = 10
+
+// PatchOperation specifies an operation of a partial document update.
+//
+// @Available since v1.2.0
+type PatchOperation struct {
+	Op    string      // accepted values: "add", "set", "replace", "remove", "incr" or "move"
+	Path  string      // path of the target field in JSON pointer notation, e.g. "/address/city" or "/tags/0"
+	Value interface{} // value of the operation, not used by "remove" and "move"
+	From  string      // source path of the "move" operation
+}
+
+// MarshalJSON implements json.Marshaler/MarshalJSON.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	op := map[string]interface{}{"op": o.Op, "path": o.Path}
+	switch o.Op {
+	case "remove":
+	case "move":
+		op["from"] = o.From
+	default:
+		op["value"] = o.Value
+	}
+	return json.Marshal(op)
+}
+
+// PatchDocReq specifies a request to partially update a document.
+//
+// @Available since v1.2.0
+type PatchDocReq struct {
+	DbName, CollName, DocId string
+	PartitionKeyValues      []interface{}
+	Operations              []PatchOperation // at most MaxPatchOperations operations
+	Condition               string           // if not empty, the operations are applied only if the document matches the filter predicate, e.g. "FROM c WHERE c.status = 'active'"
+	MatchEtag               string           // if not empty, add "If-Match" header to request
+	PreTriggerInclude       []string         // ids of pre-triggers to be executed as part of the patch operation
+	PostTriggerInclude      []string         // ids of post-triggers to be executed as part of the patch operation
+}
+
+// PatchDocument invokes Cosmos DB API to partially update an existing document.
+//
+// See: https://learn.microsoft.com/en-us/rest/api/cosmos-db/patch-a-document.
+//
+// @Available since v1.2.0
+func (c *RestClient) PatchDocument(r PatchDocReq) *RespPatchDoc {
+	if n := len(r.Operations); n == 0 || n > MaxPatchOperations {
+		return &RespPatchDoc{RestResponse: RestResponse{CallErr: fmt.Errorf("number of patch operations must be between 1 and %d, got %d", MaxPatchOperations, n)}}
+	}
+	params := map[string]interface{}{"operations": r.Operations}
+	if r.Condition != "" {
+		params["condition"] = r.Condition
+	}
+	method, urlEndpoint := "PATCH", c.endpoint+"/dbs/"+r.DbName+"/colls/"+r.CollName+"/docs/"+r.DocId
+	req, err := c.buildJsonRequest(method, urlEndpoint, params)
+	if err != nil {
+		return &RespPatchDoc{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "docs", "dbs/"+r.DbName+"/colls/"+r.CollName+"/docs/"+r.DocId)
+	req.Header.Set(httpHeaderContentType, "application/json_patch+json")
+	if r.MatchEtag != "" {
+		req.Header.Set(httpHeaderIfMatch, r.MatchEtag)
+	}
+	jsPkValues, _ := json.Marshal(r.PartitionKeyValues)
+	req.Header.Set(restApiHeaderPartitionKey, string(jsPkValues))
+	addTriggerIncludeHeaders(req, r.PreTriggerInclude, r.PostTriggerInclude)
+
+	resp := c.do(req)
+	result := &RespPatchDoc{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.DocInfo))
+	}
+	return result
+}
+
 // DocReq specifies a document request.
 type DocReq struct {
 	DbName, CollName, DocId string
 	PartitionKeyValues      []interface{}
-	MatchEtag               string // if not empty, add "If-Match" header to request
-	NotMatchEtag            string // if not empty, add "If-None-Match" header to request
-	ConsistencyLevel        string // accepted values: "", "Strong", "Bounded", "Session" or "Eventual"
-	SessionToken            string // string token used with session level consistency
+	MatchEtag               string   // if not empty, add "If-Match" header to request
+	NotMatchEtag            string   // if not empty, add "If-None-Match" header to request
+	ConsistencyLevel        string   // accepted values: "", "Strong", "Bounded", "Session" or "Eventual"; (since v1.2.0) must not be stronger than the account's default consistency level
+	SessionToken            string   // string token used with session level consistency
+	PreTriggerInclude       []string // (since v1.2.0) ids of pre-triggers to be executed as part of the delete operation
+	PostTriggerInclude      []string // (since v1.2.0) ids of post-triggers to be executed as part of the delete operation
 }
 
 // GetDocument invokes Cosmos DB API to get an existing document.
 //
 // See: https://docs.microsoft.com/en-us/rest/api/cosmos-db/get-a-document.
 func (c *RestClient) GetDocument(r DocReq) *RespGetDoc {
+	if err := c.validateConsistencyLevel(r.ConsistencyLevel); err != nil {
+		return &RespGetDoc{RestResponse: RestResponse{CallErr: err}}
+	}
 	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+r.DbName+"/colls/"+r.CollName+"/docs/"+r.DocId
 	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
 	if err != nil {
@@ -580,7 +1286,7 @@ func (c *RestClient) GetDocument(r DocReq) *RespGetDoc {
 		req.Header.Set(restApiHeaderSessionToken, r.SessionToken)
 	}
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespGetDoc{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil && result.StatusCode != 304 {
 		result.CallErr = json.Unmarshal(result.RespBody, &(result.DocInfo))
@@ -603,12 +1309,36 @@ func (c *RestClient) DeleteDocument(r DocReq) *RespDeleteDoc {
 	if r.MatchEtag != "" {
 		req.Header.Set(httpHeaderIfMatch, r.MatchEtag)
 	}
+	addTriggerIncludeHeaders(req, r.PreTriggerInclude, r.PostTriggerInclude)
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespDeleteDoc{RestResponse: c.buildRestResponse(resp)}
 	return result
 }
 
+// DeleteAllItemsByPartitionKey invokes Cosmos DB API to delete all documents belonging to a logical partition.
+//
+// The deletion is carried out by the server in the background: documents of the partition may still be returned by queries
+// for a short while after this call returns.
+//
+// See: https://learn.microsoft.com/en-us/azure/cosmos-db/nosql/how-to-delete-by-partition-key.
+//
+// @Available since v1.2.0
+func (c *RestClient) DeleteAllItemsByPartitionKey(dbName, collName string, pkValues []interface{}) *RespDeleteAllItemsByPk {
+	method, urlEndpoint := "POST", c.endpoint+"/dbs/"+dbName+"/colls/"+collName+"/operations/partitionkeydelete"
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespDeleteAllItemsByPk{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "partitionkey", "dbs/"+dbName+"/colls/"+collName)
+	jsPkValues, _ := json.Marshal(pkValues)
+	req.Header.Set(restApiHeaderPartitionKey, string(jsPkValues))
+
+	resp := c.do(req)
+	result := &RespDeleteAllItemsByPk{RestResponse: c.buildRestResponse(resp)}
+	return result
+}
+
 // QueryReq specifies a query request to query for documents.
 type QueryReq struct {
 	DbName, CollName      string
@@ -619,11 +1349,15 @@ type QueryReq struct {
 	PkValue               string // (since v0.1.8) if non-empty, query will perform only on the partition that PkValue maps to (if PkRangeId and PkValue are specified, PkRangeId takes priority)
 	ContinuationToken     string
 	CrossPartitionEnabled bool
-	ConsistencyLevel      string // accepted values: "", "Strong", "Bounded", "Session" or "Eventual"
+	ConsistencyLevel      string // accepted values: "", "Strong", "Bounded", "Session" or "Eventual"; (since v1.2.0) must not be stronger than the account's default consistency level
 	SessionToken          string // string token used with session level consistency
+	PopulateIndexMetrics  bool   // (since v1.2.0) if true, server returns index utilization metrics (see RespQueryDocs.IndexMetrics); this option incurs overhead, use it for debugging only
 }
 
 func (c *RestClient) buildQueryRequest(query QueryReq) (*http.Request, error) {
+	if err := c.validateConsistencyLevel(query.ConsistencyLevel); err != nil {
+		return nil, err
+	}
 	method, urlEndpoint := "POST", c.endpoint+"/dbs/"+query.DbName+"/colls/"+query.CollName+"/docs"
 	requestBody := make(map[string]interface{}, 0)
 	requestBody[restApiParamQuery] = query.Query
@@ -639,6 +1373,9 @@ func (c *RestClient) buildQueryRequest(query QueryReq) (*http.Request, error) {
 	req.Header.Set(httpHeaderContentType, "application/query+json")
 	req.Header.Set(restApiHeaderIsQuery, "true")
 	req.Header.Set(restApiHeaderPopulateMetrics, "true")
+	if query.PopulateIndexMetrics {
+		req.Header.Set(restApiHeaderPopulateIndexMetrics, "true")
+	}
 	if query.MaxItemCount > 0 {
 		req.Header.Set(restApiHeaderPageSize, strconv.Itoa(query.MaxItemCount))
 	}
@@ -667,6 +1404,8 @@ func (c *RestClient) mergeQueryResults(existingResp, newResp *RespQueryDocs, que
 	result := &temp
 	if existingResp != nil {
 		result.RequestCharge += existingResp.RequestCharge
+		result.QueryMetrics = existingResp.QueryMetrics.Add(newResp.QueryMetrics)
+		result.IndexMetrics = existingResp.IndexMetrics.Add(newResp.IndexMetrics)
 		if newResp.Error() == nil {
 			result = result.merge(queryPlan, existingResp)
 		}
@@ -823,16 +1562,19 @@ func (c *RestClient) queryDocumentsSimple(query QueryReq, queryPlan *RespQueryPl
 		req.Header.Set(restApiHeaderPageSize, "100")
 	}
 	for {
-		resp := c.client.Do(req)
+		resp := c.do(req)
 		tempResult := &RespQueryDocs{RestResponse: c.buildRestResponse(resp)}
 		if tempResult.CallErr == nil {
 			tempResult.ContinuationToken = tempResult.RespHeader[respHeaderContinuation]
 			tempResult.CallErr = json.Unmarshal(tempResult.RespBody, &tempResult)
+			tempResult.populateMetrics()
 		}
 		if result != nil {
 			// append returned document list
 			tempResult.Count += result.Count
 			tempResult.RequestCharge += result.RequestCharge
+			tempResult.QueryMetrics = result.QueryMetrics.Add(tempResult.QueryMetrics)
+			tempResult.IndexMetrics = result.IndexMetrics.Add(tempResult.IndexMetrics)
 			tempResult.Documents = append(result.Documents, tempResult.Documents...)
 		}
 		result = tempResult
@@ -852,11 +1594,12 @@ func (c *RestClient) queryDocumentsCall(query QueryReq) *RespQueryDocs {
 	if err != nil {
 		return &RespQueryDocs{RestResponse: RestResponse{CallErr: err}}
 	}
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespQueryDocs{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.ContinuationToken = result.RespHeader[respHeaderContinuation]
 		result.CallErr = json.Unmarshal(result.RespBody, &result)
+		result.populateMetrics()
 	}
 	return result
 }
@@ -884,11 +1627,21 @@ func (c *RestClient) QueryDocuments(query QueryReq) *RespQueryDocs {
 	if queryPlan.Error() != nil {
 		return &RespQueryDocs{RestResponse: queryPlan.RestResponse}
 	}
+	return c.queryDocumentsWithPlan(query, queryPlan, nil)
+}
 
-	if queryPlan.QueryInfo.DistinctType != "None" || queryPlan.QueryInfo.RewrittenQuery != "" {
-		pkranges := c.GetPkranges(query.DbName, query.CollName)
-		if pkranges.Error() != nil {
-			return &RespQueryDocs{RestResponse: pkranges.RestResponse}
+// queryDocumentsWithPlan executes the query the same way as QueryDocuments, but with the supplied query plan. The
+// partition key ranges are fetched if needed and pkranges is nil. Callers fetching a query page by page use this
+// function to avoid re-fetching the query plan and partition key ranges for each page.
+//
+// @Available since v1.2.0
+func (c *RestClient) queryDocumentsWithPlan(query QueryReq, queryPlan *RespQueryPlan, pkranges *RespGetPkranges) *RespQueryDocs {
+	if queryPlan.needsMerge() {
+		if pkranges == nil {
+			pkranges = c.GetPkranges(query.DbName, query.CollName)
+			if pkranges.Error() != nil {
+				return &RespQueryDocs{RestResponse: pkranges.RestResponse}
+			}
 		}
 		return c.queryAndMerge(query, pkranges, queryPlan)
 	}
@@ -939,6 +1692,9 @@ func (c *RestClient) QueryDocumentsCrossPartition(query QueryReq) *RespQueryDocs
 //
 // Available since v0.1.8
 func (c *RestClient) QueryPlan(query QueryReq) *RespQueryPlan {
+	if err := c.validateConsistencyLevel(query.ConsistencyLevel); err != nil {
+		return &RespQueryPlan{RestResponse: RestResponse{CallErr: err}}
+	}
 	method, urlEndpoint := "POST", c.endpoint+"/dbs/"+query.DbName+"/colls/"+query.CollName+"/docs"
 	requestBody := make(map[string]interface{}, 0)
 	requestBody[restApiParamQuery] = query.Query
@@ -967,7 +1723,7 @@ func (c *RestClient) QueryPlan(query QueryReq) *RespQueryPlan {
 	req.Header.Set(restApiHeaderSupportedQueryFeatures, "NonValueAggregate, Aggregate, Distinct, MultipleOrderBy, OffsetAndLimit, OrderBy, Top, CompositeAggregate, GroupBy, MultipleAggregates")
 	req.Header.Set(restApiHeaderEnableCrossPartitionQuery, "true")
 	req.Header.Set(restApiHeaderParallelizeCrossPartitionQuery, "true")
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespQueryPlan{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.CallErr = json.Unmarshal(result.RespBody, &result)
@@ -980,7 +1736,7 @@ type ListDocsReq struct {
 	DbName, CollName  string
 	MaxItemCount      int
 	ContinuationToken string
-	ConsistencyLevel  string // accepted values: "", "Strong", "Bounded", "Session" or "Eventual"
+	ConsistencyLevel  string // accepted values: "", "Strong", "Bounded", "Session" or "Eventual"; (since v1.2.0) must not be stronger than the account's default consistency level
 	SessionToken      string // string token used with session level consistency
 	NotMatchEtag      string
 	PkRangeId         string
@@ -990,7 +1746,7 @@ type ListDocsReq struct {
 func (c *RestClient) getChangeFeed(r ListDocsReq, req *http.Request) *RespListDocs {
 	var result *RespListDocs
 	for {
-		resp := c.client.Do(req)
+		resp := c.do(req)
 		tempResult := &RespListDocs{RestResponse: c.buildRestResponse(resp)}
 		if 300 <= tempResult.StatusCode && tempResult.StatusCode < 400 {
 			// not an error, the status code 3xx indicates that there is currently no item from the change feed
@@ -1029,6 +1785,9 @@ func (c *RestClient) getChangeFeed(r ListDocsReq, req *http.Request) *RespListDo
 // Note: if fetching incremental feed (ListDocsReq.IsIncrementalFeed = true), it is the caller responsibility to
 // resubmit the request with proper value of etag (ListDocsReq.NotMatchEtag)
 func (c *RestClient) ListDocuments(r ListDocsReq) *RespListDocs {
+	if err := c.validateConsistencyLevel(r.ConsistencyLevel); err != nil {
+		return &RespListDocs{RestResponse: RestResponse{CallErr: err}}
+	}
 	method, urlEndpoint := "GET", c.endpoint+"/dbs/"+r.DbName+"/colls/"+r.CollName+"/docs"
 	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
 	if err != nil {
@@ -1065,7 +1824,7 @@ func (c *RestClient) ListDocuments(r ListDocsReq) *RespListDocs {
 	// fetch documents from table/collection
 	var result *RespListDocs
 	for {
-		resp := c.client.Do(req)
+		resp := c.do(req)
 		tempResult := &RespListDocs{RestResponse: c.buildRestResponse(resp)}
 		if tempResult.CallErr == nil {
 			tempResult.ContinuationToken = tempResult.RespHeader[respHeaderContinuation]
@@ -1122,7 +1881,7 @@ func (c *RestClient) QueryOffers(query string) *RespQueryOffers {
 	req.Header.Set(httpHeaderContentType, "application/query+json")
 	req.Header.Set(restApiHeaderIsQuery, "true")
 
-	resp := c.client.Do(req)
+	resp := c.do(req)
 	result := &RespQueryOffers{RestResponse: c.buildRestResponse(resp)}
 	if result.CallErr == nil {
 		result.ContinuationToken = result.RespHeader[respHeaderContinuation]
@@ -1208,7 +1967,7 @@ func (c *RestClient) ReplaceOfferForResource(rid string, ru, maxru int) *RespRep
 		for k, v := range headers {
 			req.Header.Set(k, v)
 		}
-		resp := c.client.Do(req)
+		resp := c.do(req)
 		result := &RespReplaceOffer{RestResponse: c.buildRestResponse(resp)}
 		if result.CallErr == nil {
 			if (headers[restApiHeaderMigrateToAutopilotThroughput] == "true" && maxru > 0) || (headers[restApiHeaderMigrateToManualThroughput] == "true" && ru > 0) {
@@ -1319,7 +2078,7 @@ func (pk PkInfo) Version() int {
 
 func (pk PkInfo) Paths() []string {
 	paths, err := reddo.ToSlice(pk["paths"], reddo.TypeString)
-	if err == nil {
+	if err == nil && paths != nil {
 		return paths.([]string)
 	}
 	return nil
@@ -1341,6 +2100,8 @@ type CollInfo struct {
 	PartitionKey             PkInfo                 `json:"partitionKey"`             // partitioning configuration settings for collection
 	ConflictResolutionPolicy map[string]interface{} `json:"conflictResolutionPolicy"` // conflict resolution policy settings for collection
 	GeospatialConfig         map[string]interface{} `json:"geospatialConfig"`         // Geo-spatial configuration settings for collection
+	UniqueKeyPolicy          map[string]interface{} `json:"uniqueKeyPolicy"`          // (since v1.2.0) unique key policy settings for collection
+	DefaultTtl               *int                   `json:"defaultTtl,omitempty"`     // (since v1.2.0) default time-to-live (in seconds) of documents; nil: TTL is off, -1: TTL is on but documents do not expire by default
 }
 
 func (c *CollInfo) toMap() map[string]interface{} {
@@ -1359,6 +2120,8 @@ func (c *CollInfo) toMap() map[string]interface{} {
 		"partitionKey":             c.PartitionKey,
 		"conflictResolutionPolicy": c.ConflictResolutionPolicy,
 		"geospatialConfig":         c.GeospatialConfig,
+		"uniqueKeyPolicy":          c.UniqueKeyPolicy,
+		"defaultTtl":               c.DefaultTtl,
 	}
 }
 
@@ -1378,6 +2141,7 @@ type RespReplaceColl struct {
 type RespGetColl struct {
 	RestResponse
 	CollInfo
+	IndexTransformationProgress int // (since v1.2.0) progress (0-100) of re-indexing the collection after its indexing policy changed, -1 if not reported by the server
 }
 
 // RespDeleteColl captures the response from RestClient.DeleteCollection call.
@@ -1392,6 +2156,199 @@ type RespListColl struct {
 	Collections  []CollInfo `json:"DocumentCollections"`
 }
 
+// SprocInfo captures info of a Cosmos DB stored procedure.
+//
+// @Available since v1.2.0
+type SprocInfo struct {
+	Id   string `json:"id"`    // user-generated unique name for the stored procedure
+	Body string `json:"body"`  // body of the stored procedure
+	Rid  string `json:"_rid"`  // (system generated property) _rid attribute of the stored procedure
+	Ts   int64  `json:"_ts"`   // (system-generated property) _ts attribute of the stored procedure
+	Self string `json:"_self"` // (system-generated property) _self attribute of the stored procedure
+	Etag string `json:"_etag"` // (system-generated property) _etag attribute of the stored procedure
+}
+
+func (s *SprocInfo) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":    s.Id,
+		"body":  s.Body,
+		"_rid":  s.Rid,
+		"_ts":   s.Ts,
+		"_self": s.Self,
+		"_etag": s.Etag,
+	}
+}
+
+// RespCreateSproc captures the response from RestClient.CreateSproc call.
+//
+// @Available since v1.2.0
+type RespCreateSproc struct {
+	RestResponse
+	SprocInfo
+}
+
+// RespReplaceSproc captures the response from RestClient.ReplaceSproc call.
+//
+// @Available since v1.2.0
+type RespReplaceSproc struct {
+	RestResponse
+	SprocInfo
+}
+
+// RespGetSproc captures the response from RestClient.GetSproc call.
+//
+// @Available since v1.2.0
+type RespGetSproc struct {
+	RestResponse
+	SprocInfo
+}
+
+// RespDeleteSproc captures the response from RestClient.DeleteSproc call.
+//
+// @Available since v1.2.0
+type RespDeleteSproc struct {
+	RestResponse
+}
+
+// RespListSprocs captures the response from RestClient.ListSprocs call.
+//
+// @Available since v1.2.0
+type RespListSprocs struct {
+	RestResponse `json:"-"`
+	Count        int         `json:"_count"` // number of stored procedures returned from the list operation
+	Sprocs       []SprocInfo `json:"StoredProcedures"`
+}
+
+// UdfInfo captures info of a Cosmos DB user-defined function.
+//
+// @Available since v1.2.0
+type UdfInfo struct {
+	Id   string `json:"id"`    // user-generated unique name for the user-defined function
+	Body string `json:"body"`  // body of the user-defined function
+	Rid  string `json:"_rid"`  // (system generated property) _rid attribute of the user-defined function
+	Ts   int64  `json:"_ts"`   // (system-generated property) _ts attribute of the user-defined function
+	Self string `json:"_self"` // (system-generated property) _self attribute of the user-defined function
+	Etag string `json:"_etag"` // (system-generated property) _etag attribute of the user-defined function
+}
+
+func (u *UdfInfo) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":    u.Id,
+		"body":  u.Body,
+		"_rid":  u.Rid,
+		"_ts":   u.Ts,
+		"_self": u.Self,
+		"_etag": u.Etag,
+	}
+}
+
+// RespCreateUdf captures the response from RestClient.CreateUdf call.
+//
+// @Available since v1.2.0
+type RespCreateUdf struct {
+	RestResponse
+	UdfInfo
+}
+
+// RespReplaceUdf captures the response from RestClient.ReplaceUdf call.
+//
+// @Available since v1.2.0
+type RespReplaceUdf struct {
+	RestResponse
+	UdfInfo
+}
+
+// RespGetUdf captures the response from RestClient.GetUdf call.
+//
+// @Available since v1.2.0
+type RespGetUdf struct {
+	RestResponse
+	UdfInfo
+}
+
+// RespDeleteUdf captures the response from RestClient.DeleteUdf call.
+//
+// @Available since v1.2.0
+type RespDeleteUdf struct {
+	RestResponse
+}
+
+// RespListUdfs captures the response from RestClient.ListUdfs call.
+//
+// @Available since v1.2.0
+type RespListUdfs struct {
+	RestResponse `json:"-"`
+	Count        int       `json:"_count"` // number of user-defined functions returned from the list operation
+	Udfs         []UdfInfo `json:"UserDefinedFunctions"`
+}
+
+// TriggerInfo captures info of a Cosmos DB trigger.
+//
+// @Available since v1.2.0
+type TriggerInfo struct {
+	Id               string `json:"id"`               // user-generated unique name for the trigger
+	Body             string `json:"body"`             // body of the trigger
+	TriggerType      string `json:"triggerType"`      // type of the trigger, "Pre" or "Post"
+	TriggerOperation string `json:"triggerOperation"` // operation that fires the trigger, "All", "Create", "Replace" or "Delete"
+	Rid              string `json:"_rid"`             // (system generated property) _rid attribute of the trigger
+	Ts               int64  `json:"_ts"`              // (system-generated property) _ts attribute of the trigger
+	Self             string `json:"_self"`            // (system-generated property) _self attribute of the trigger
+	Etag             string `json:"_etag"`            // (system-generated property) _etag attribute of the trigger
+}
+
+func (t *TriggerInfo) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":               t.Id,
+		"body":             t.Body,
+		"triggerType":      t.TriggerType,
+		"triggerOperation": t.TriggerOperation,
+		"_rid":             t.Rid,
+		"_ts":              t.Ts,
+		"_self":            t.Self,
+		"_etag":            t.Etag,
+	}
+}
+
+// RespCreateTrigger captures the response from RestClient.CreateTrigger call.
+//
+// @Available since v1.2.0
+type RespCreateTrigger struct {
+	RestResponse
+	TriggerInfo
+}
+
+// RespReplaceTrigger captures the response from RestClient.ReplaceTrigger call.
+//
+// @Available since v1.2.0
+type RespReplaceTrigger struct {
+	RestResponse
+	TriggerInfo
+}
+
+// RespGetTrigger captures the response from RestClient.GetTrigger call.
+//
+// @Available since v1.2.0
+type RespGetTrigger struct {
+	RestResponse
+	TriggerInfo
+}
+
+// RespDeleteTrigger captures the response from RestClient.DeleteTrigger call.
+//
+// @Available since v1.2.0
+type RespDeleteTrigger struct {
+	RestResponse
+}
+
+// RespListTriggers captures the response from RestClient.ListTriggers call.
+//
+// @Available since v1.2.0
+type RespListTriggers struct {
+	RestResponse `json:"-"`
+	Count        int           `json:"_count"` // number of triggers returned from the list operation
+	Triggers     []TriggerInfo `json:"Triggers"`
+}
+
 // QueriedDocs is list of returned documents from a query such as result from RestClient.QueryDocuments call.
 // A query can return a list of documents or a list of scalar values.
 //
@@ -1783,6 +2740,14 @@ type RespReplaceDoc struct {
 	DocInfo
 }
 
+// RespPatchDoc captures the response from RestClient.PatchDocument call.
+//
+// @Available since v1.2.0
+type RespPatchDoc struct {
+	RestResponse
+	DocInfo
+}
+
 // RespGetDoc captures the response from RestClient.GetDocument call.
 type RespGetDoc struct {
 	RestResponse
@@ -1794,6 +2759,13 @@ type RespDeleteDoc struct {
 	RestResponse
 }
 
+// RespDeleteAllItemsByPk captures the response from RestClient.DeleteAllItemsByPartitionKey call.
+//
+// @Available since v1.2.0
+type RespDeleteAllItemsByPk struct {
+	RestResponse
+}
+
 // RespQueryDocs captures the response from RestClient.QueryDocuments call.
 type RespQueryDocs struct {
 	RestResponse       `json:"-"`
@@ -1802,6 +2774,15 @@ type RespQueryDocs struct {
 	ContinuationToken  string         `json:"-"`
 	QueryPlan          *RespQueryPlan `json:"-"` // (available since v0.2.0) the query plan used to execute the query
 	RewrittenDocuments QueriedDocs    `json:"-"` // (available since v0.2.0) the original returned documents from the execution of RespQueryPlan.QueryInfo.RewrittenQuery
+	QueryMetrics       *QueryMetrics  `json:"-"` // (available since v1.2.0) query execution metrics, aggregated across pages and partition key ranges; nil if not returned by server
+	IndexMetrics       *IndexMetrics  `json:"-"` // (available since v1.2.0) index utilization metrics, aggregated across pages and partition key ranges; nil if not requested (see QueryReq.PopulateIndexMetrics)
+}
+
+// Available since v1.2.0
+func (r *RespQueryDocs) populateMetrics() *RespQueryDocs {
+	r.QueryMetrics = parseQueryMetrics(r.RespHeader[respHeaderQueryMetrics])
+	r.IndexMetrics = parseIndexMetrics(r.RespHeader[respHeaderIndexMetrics])
+	return r
 }
 
 // Available since v0.2.0
@@ -1848,6 +2829,24 @@ type RespQueryPlan struct {
 		HasSelectValue              bool              `json:"hasSelectValue"`
 		DCountInfo                  typDCountInfo     `json:"dCountInfo"`
 	} `json:"queryInfo"`
+	QueryRanges []QueryRange `json:"queryRanges"` // (since v1.2.0) ranges of effective partition key values targeted by the query
+}
+
+// QueryRange is a range of effective partition key values targeted by a query, see RespQueryPlan.
+//
+// @Available since v1.2.0
+type QueryRange struct {
+	Min            string `json:"min"`            // lower bound of the range
+	Max            string `json:"max"`            // upper bound of the range
+	IsMinInclusive bool   `json:"isMinInclusive"` // true if Min belongs to the range
+	IsMaxInclusive bool   `json:"isMaxInclusive"` // true if Max belongs to the range
+}
+
+// overlaps tests if the query range overlaps the partition key range [MinInclusive, MaxExclusive).
+func (r QueryRange) overlaps(pkrange PkrangeInfo) bool {
+	minCmp := strings.Compare(strings.ToUpper(r.Min), strings.ToUpper(pkrange.MaxExclusive))
+	maxCmp := strings.Compare(strings.ToUpper(r.Max), strings.ToUpper(pkrange.MinInclusive))
+	return minCmp < 0 && (maxCmp > 0 || (maxCmp == 0 && r.IsMaxInclusive))
 }
 
 // IsDistinctQuery tests if duplicates are eliminated in the query's projection.
@@ -1871,6 +2870,11 @@ func (qp *RespQueryPlan) IsOrderByQuery() bool {
 	return len(qp.QueryInfo.OrderByExpressions) > 0
 }
 
+// needsMerge tests if the results of the query must be merged across partition key ranges, see queryAndMerge.
+func (qp *RespQueryPlan) needsMerge() bool {
+	return qp.QueryInfo.DistinctType != "None" || qp.QueryInfo.RewrittenQuery != ""
+}
+
 // RespListDocs captures the response from RestClient.ListDocuments call.
 type RespListDocs struct {
 	RestResponse      `json:"-"`
@@ -1906,7 +2910,7 @@ func (o OfferInfo) OfferThroughput() int {
 		o._s = semita.NewSemita(o.Content)
 	}
 	v, err := o._s.GetValueOfType("offerThroughput", reddo.TypeInt)
-	if err == nil {
+	if err == nil && v != nil {
 		return int(v.(int64))
 	}
 	return 0
@@ -1920,7 +2924,21 @@ func (o OfferInfo) MaxThroughputEverProvisioned() int {
 		o._s = semita.NewSemita(o.Content)
 	}
 	v, err := o._s.GetValueOfType("offerMinimumThroughputParameters.maxThroughputEverProvisioned", reddo.TypeInt)
-	if err == nil {
+	if err == nil && v != nil {
+		return int(v.(int64))
+	}
+	return 0
+}
+
+// AutopilotMaxThroughput returns the maximum throughput of autopilot (autoscale) setting, 0 if autopilot is not enabled.
+//
+// @Available since v1.2.0
+func (o OfferInfo) AutopilotMaxThroughput() int {
+	if o._s == nil {
+		o._s = semita.NewSemita(o.Content)
+	}
+	v, err := o._s.GetValueOfType("offerAutopilotSettings.maxThroughput", reddo.TypeInt)
+	if err == nil && v != nil && v.(int64) > 0 {
 		return int(v.(int64))
 	}
 	return 0
@@ -1980,3 +2998,49 @@ type RespGetPkranges struct {
 	Pkranges     []PkrangeInfo `json:"PartitionKeyRanges"`
 	Count        int           `json:"_count"` // number of records returned from the operation
 }
+
+// ConflictInfo captures info of an item in the conflicts feed of a Cosmos DB collection.
+//
+// @Available since v1.2.0
+type ConflictInfo struct {
+	Id            string `json:"id"`            // unique id of the conflict
+	ResourceId    string `json:"resourceId"`    // _rid of the conflicting resource
+	ResourceType  string `json:"resourceType"`  // type of the conflicting resource, e.g. "document"
+	OperationType string `json:"operationType"` // operation that caused the conflict, e.g. "create", "replace" or "delete"
+	Content       string `json:"content"`       // JSON-encoded content of the conflicting resource
+	Rid           string `json:"_rid"`          // (system generated property) _rid attribute of the conflict
+	Ts            int64  `json:"_ts"`           // (system-generated property) _ts attribute of the conflict
+	Self          string `json:"_self"`         // (system-generated property) _self attribute of the conflict
+	Etag          string `json:"_etag"`         // (system-generated property) _etag attribute of the conflict
+}
+
+// ContentAsDoc parses the content of the conflicting resource and returns it as a DocInfo.
+func (ci ConflictInfo) ContentAsDoc() (DocInfo, error) {
+	var doc DocInfo
+	err := json.Unmarshal([]byte(ci.Content), &doc)
+	return doc, err
+}
+
+// RespListConflicts captures the response from RestClient.ListConflicts call.
+//
+// @Available since v1.2.0
+type RespListConflicts struct {
+	RestResponse `json:"-"`
+	Count        int            `json:"_count"` // number of conflicts returned from the list operation
+	Conflicts    []ConflictInfo `json:"Conflicts"`
+}
+
+// RespGetConflict captures the response from RestClient.GetConflict call.
+//
+// @Available since v1.2.0
+type RespGetConflict struct {
+	RestResponse
+	ConflictInfo
+}
+
+// RespDeleteConflict captures the response from RestClient.DeleteConflict call.
+//
+// @Available since v1.2.0
+type RespDeleteConflict struct {
+	RestResponse
+}
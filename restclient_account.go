@@ -0,0 +1,429 @@
+package gocosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btnguyen2k/consu/gjrc"
+)
+
+const (
+	// regionRefreshInterval is the interval at which the account topology is refreshed in the background.
+	regionRefreshInterval = 5 * time.Minute
+
+	// regionUnavailableDuration is how long a regional endpoint is skipped after it has been marked as unavailable.
+	regionUnavailableDuration = 5 * time.Minute
+
+	// substatus code returned along with "403 Forbidden" when writes are not allowed in the region (WriteForbidden).
+	subStatusWriteForbidden = "3"
+)
+
+// GetDatabaseAccount invokes Cosmos DB API to get the properties of the database account.
+//
+// Note: this call always goes to the global endpoint (AccountEndpoint), regardless of the PreferredRegions setting.
+//
+// @Available since v1.2.0
+func (c *RestClient) GetDatabaseAccount() *RespGetDatabaseAccount {
+	method, urlEndpoint := "GET", c.endpoint+"/"
+	req, err := c.buildJsonRequest(method, urlEndpoint, nil)
+	if err != nil {
+		return &RespGetDatabaseAccount{RestResponse: RestResponse{CallErr: err}}
+	}
+	req = c.addAuthHeader(req, method, "", "")
+
+	// regional routing depends on the result of this call, hence do not route it
+	resp := c.client.Do(req)
+	result := &RespGetDatabaseAccount{RestResponse: c.buildRestResponse(resp)}
+	if result.CallErr == nil {
+		result.CallErr = json.Unmarshal(result.RespBody, &(result.DatabaseAccountInfo))
+		if result.CallErr == nil {
+			// cache a copy, so that callers modifying the result do not affect the cached account info
+			info := result.DatabaseAccountInfo
+			info.WritableLocations = append([]AccountLocation(nil), info.WritableLocations...)
+			info.ReadableLocations = append([]AccountLocation(nil), info.ReadableLocations...)
+			c.accountMutex.Lock()
+			c.account = &info
+			c.accountMutex.Unlock()
+		}
+	}
+	return result
+}
+
+// consistencyStrength maps consistency levels to their strengths, a larger value means a stronger consistency.
+var consistencyStrength = map[string]int{
+	"eventual":         1,
+	"consistentprefix": 2,
+	"session":          3,
+	"boundedstaleness": 4,
+	"bounded":          4,
+	"strong":           5,
+}
+
+// validateConsistencyLevel checks if the requested consistency level is allowed by the account.
+//
+// A request can only relax (not strengthen) the account's default consistency level. Only the documented consistency
+// levels are validated, other values are left for the server to judge. The validation never blocks a request on fetching
+// the account info: if the account info is not cached yet, it is fetched in the background (and not re-fetched within a
+// minute after a failure) and the request is sent unvalidated.
+//
+// @Available since v1.2.0
+func (c *RestClient) validateConsistencyLevel(level string) error {
+	requested, ok := consistencyStrength[strings.ToLower(level)]
+	if !ok {
+		return nil
+	}
+	c.accountMutex.RLock()
+	account, lastFailure := c.account, c.accountFetchFailure
+	c.accountMutex.RUnlock()
+	if account == nil {
+		if time.Since(lastFailure) >= time.Minute && atomic.CompareAndSwapInt32(&c.accountFetching, 0, 1) {
+			go func() {
+				defer atomic.StoreInt32(&c.accountFetching, 0)
+				if result := c.GetDatabaseAccount(); result.Error() != nil {
+					c.accountMutex.Lock()
+					c.accountFetchFailure = time.Now()
+					c.accountMutex.Unlock()
+				}
+			}()
+		}
+		return nil
+	}
+	defaultLevel := account.ConsistencyPolicy.DefaultConsistencyLevel
+	if accountDefault, ok := consistencyStrength[strings.ToLower(defaultLevel)]; ok && requested > accountDefault {
+		return fmt.Errorf("requested consistency level %s is stronger than the account's default consistency level %s", level, defaultLevel)
+	}
+	return nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// regionRouter routes requests to regional endpoints of a multi-region Cosmos DB account.
+//
+// Reads are sent to the first available readable region in the preferred regions list, writes are sent to the
+// (first available) write region. When a regional endpoint fails, it is marked as unavailable and requests fail over
+// to the next candidate.
+type regionRouter struct {
+	globalEndpoint   string
+	preferredRegions []string // lower-cased region names, in order of preference
+
+	mutex          sync.RWMutex
+	readEndpoints  []string             // candidate endpoints for read requests, in order of preference
+	writeEndpoints []string             // candidate endpoints for write requests, in order of preference
+	unavailable    map[string]time.Time // endpoint -> when it was marked as unavailable
+	lastRefresh    time.Time            // last time the topology was fetched successfully
+	lastFailure    time.Time            // last time fetching the topology failed
+
+	refreshing      int32         // 1 if a topology refresh is in progress
+	refreshInterval time.Duration // interval of the background refresh
+
+	tickerMutex sync.Mutex
+	ticking     int32         // 1 if the background refresh is running
+	stopCh      chan struct{} // closed to stop the background refresh
+}
+
+func newRegionRouter(globalEndpoint string, preferredRegions []string) *regionRouter {
+	router := &regionRouter{globalEndpoint: globalEndpoint, unavailable: make(map[string]time.Time), refreshInterval: regionRefreshInterval}
+	for _, region := range preferredRegions {
+		if region = strings.ToLower(strings.TrimSpace(region)); region != "" {
+			router.preferredRegions = append(router.preferredRegions, region)
+		}
+	}
+	return router
+}
+
+// orderByPreference returns endpoints of the supplied locations, those in preferred regions come first.
+func (r *regionRouter) orderByPreference(locations []AccountLocation) []string {
+	result := make([]string, 0, len(locations))
+	for _, region := range r.preferredRegions {
+		for _, loc := range locations {
+			if strings.ToLower(loc.Name) == region {
+				result = append(result, strings.TrimSuffix(loc.Endpoint, "/"))
+			}
+		}
+	}
+	for _, loc := range locations {
+		result = append(result, strings.TrimSuffix(loc.Endpoint, "/"))
+	}
+	return result
+}
+
+func _appendUnique(dst []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range dst {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found && v != "" {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+func (r *regionRouter) update(topology *DatabaseAccountInfo) {
+	writeEndpoints := _appendUnique(nil, r.orderByPreference(topology.WritableLocations)...)
+	readEndpoints := _appendUnique(nil, r.orderByPreference(topology.ReadableLocations)...)
+	readEndpoints = _appendUnique(readEndpoints, writeEndpoints...)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.writeEndpoints = _appendUnique(writeEndpoints, r.globalEndpoint)
+	r.readEndpoints = _appendUnique(readEndpoints, r.globalEndpoint)
+	r.lastRefresh = time.Now()
+}
+
+// refresh fetches the account topology and updates the routing tables.
+// If a refresh is already in progress, this function returns immediately.
+func (r *regionRouter) refresh(c *RestClient) {
+	if !atomic.CompareAndSwapInt32(&r.refreshing, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&r.refreshing, 0)
+	if result := c.GetDatabaseAccount(); result.Error() == nil {
+		r.update(&result.DatabaseAccountInfo)
+	} else {
+		// keep using the current routing tables, the next refresh will try again
+		r.mutex.Lock()
+		r.lastFailure = time.Now()
+		r.mutex.Unlock()
+	}
+}
+
+// ensureLoaded fetches the account topology synchronously if it has never been fetched.
+// After a failed fetch, requests go to the global endpoint and the fetch is not retried within a minute.
+func (r *regionRouter) ensureLoaded(c *RestClient) {
+	r.mutex.RLock()
+	lastRefresh, lastFailure := r.lastRefresh, r.lastFailure
+	r.mutex.RUnlock()
+	if lastRefresh.IsZero() && time.Since(lastFailure) >= time.Minute {
+		r.refresh(c)
+	}
+}
+
+// start refreshes the account topology in the background, every refreshInterval, until stop is called.
+// Calling start while the background refresh is running is a no-op.
+func (r *regionRouter) start(c *RestClient) {
+	if atomic.LoadInt32(&r.ticking) == 1 {
+		return
+	}
+	r.tickerMutex.Lock()
+	defer r.tickerMutex.Unlock()
+	if r.ticking == 1 {
+		return
+	}
+	stopCh := make(chan struct{})
+	r.stopCh = stopCh
+	atomic.StoreInt32(&r.ticking, 1)
+	go func() {
+		ticker := time.NewTicker(r.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				r.refresh(c)
+			}
+		}
+	}()
+}
+
+// stop stops the background refresh started by start.
+func (r *regionRouter) stop() {
+	r.tickerMutex.Lock()
+	defer r.tickerMutex.Unlock()
+	if r.ticking == 1 {
+		close(r.stopCh)
+		atomic.StoreInt32(&r.ticking, 0)
+	}
+}
+
+func (r *regionRouter) markUnavailable(endpoint string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.unavailable[endpoint] = time.Now()
+}
+
+// candidates returns the endpoints to try for a request, available endpoints first.
+func (r *regionRouter) candidates(isRead bool) []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	endpoints := r.writeEndpoints
+	if isRead {
+		endpoints = r.readEndpoints
+	}
+	if len(endpoints) == 0 {
+		return []string{r.globalEndpoint}
+	}
+	available, unavailable := make([]string, 0, len(endpoints)), make([]string, 0)
+	for _, endpoint := range endpoints {
+		if t, ok := r.unavailable[endpoint]; ok && time.Since(t) < regionUnavailableDuration {
+			unavailable = append(unavailable, endpoint)
+		} else {
+			available = append(available, endpoint)
+		}
+	}
+	return append(available, unavailable...)
+}
+
+func _isReadRequest(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead ||
+		req.Header.Get(restApiHeaderIsQuery) != "" || req.Header.Get(restApiHeaderIsQueryPlanRequest) != ""
+}
+
+// _isDialError returns true if the error indicates that the request never reached the server,
+// e.g. the host could not be resolved or the connection was refused.
+func _isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// _shouldFailover returns true if the request should be retried against the next regional endpoint.
+//
+// Transport errors are retried for read requests only: a write request may have reached the server before the connection
+// failed, replaying it could apply the write twice. Write requests fail over on transport errors only if the request
+// provably never reached the server (see _isDialError).
+func _shouldFailover(resp *gjrc.GjrcResponse, isRead bool) bool {
+	httpResp := resp.HttpResponse()
+	if httpResp == nil {
+		// transport error, the regional endpoint is not reachable
+		err := resp.Error()
+		return err != nil && (isRead || _isDialError(err))
+	}
+	switch httpResp.StatusCode {
+	case 503:
+		return true
+	case 403:
+		return httpResp.Header.Get(respHeaderSubStatus) == subStatusWriteForbidden
+	}
+	return false
+}
+
+// do sends the request, routing it to a regional endpoint if multi-region routing is enabled.
+func (c *RestClient) do(req *http.Request) *gjrc.GjrcResponse {
+	if c.regions == nil {
+		return c.client.Do(req)
+	}
+	c.regions.start(c) // restart the background refresh if the client is used after Close
+	c.regions.ensureLoaded(c)
+	isRead := _isReadRequest(req)
+	candidates := c.regions.candidates(isRead)
+	var resp *gjrc.GjrcResponse
+	for i, endpoint := range candidates {
+		regionalReq, err := _rerouteRequest(req, endpoint, i > 0)
+		if err != nil {
+			// the original request's body may have been consumed by a previous attempt, do not re-send it
+			if resp == nil {
+				resp = _failedResponse(req, err)
+			}
+			return resp
+		}
+		resp = c.client.Do(regionalReq)
+		if !_shouldFailover(resp, isRead) || i == len(candidates)-1 {
+			return resp
+		}
+		c.regions.markUnavailable(endpoint)
+		go c.regions.refresh(c)
+	}
+	return resp
+}
+
+// _failedTransport is a http.RoundTripper that fails every request with the supplied error, without sending it.
+type _failedTransport struct {
+	err error
+}
+
+// RoundTrip implements http.RoundTripper/RoundTrip.
+func (t _failedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, t.err
+}
+
+// _failedResponse returns a GjrcResponse capturing the supplied error as the error performing the request.
+func _failedResponse(req *http.Request, err error) *gjrc.GjrcResponse {
+	return gjrc.NewGjrc(&http.Client{Transport: _failedTransport{err: err}}, 0).Do(req)
+}
+
+// _rerouteRequest clones the request, pointing it to the supplied endpoint.
+func _rerouteRequest(req *http.Request, endpoint string, resetBody bool) (*http.Request, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	result := req.Clone(req.Context())
+	result.URL.Scheme, result.URL.Host, result.Host = u.Scheme, u.Host, u.Host
+	if resetBody && req.GetBody != nil {
+		if result.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// AccountLocation captures a regional endpoint of a Cosmos DB account.
+//
+// @Available since v1.2.0
+type AccountLocation struct {
+	Name     string `json:"name"`                    // name of the region, e.g. "West US"
+	Endpoint string `json:"databaseAccountEndpoint"` // endpoint of the region
+}
+
+// AccountConsistencyPolicy captures the default consistency policy of a Cosmos DB account.
+//
+// @Available since v1.2.0
+type AccountConsistencyPolicy struct {
+	DefaultConsistencyLevel string `json:"defaultConsistencyLevel"` // "Strong", "BoundedStaleness", "Session", "ConsistentPrefix" or "Eventual"
+	MaxStalenessPrefix      int64  `json:"maxStalenessPrefix"`      // (bounded staleness only) max number of stale requests tolerated
+	MaxIntervalInSeconds    int64  `json:"maxIntervalInSeconds"`    // (bounded staleness only) max lag time tolerated
+}
+
+// DatabaseAccountInfo captures info of a Cosmos DB database account.
+//
+// @Available since v1.2.0
+type DatabaseAccountInfo struct {
+	Id                           string                   `json:"id"`                           // name of the account
+	Rid                          string                   `json:"_rid"`                         // (system generated property) _rid attribute of the account
+	WritableLocations            []AccountLocation        `json:"writableLocations"`            // regions that accept writes
+	ReadableLocations            []AccountLocation        `json:"readableLocations"`            // regions that accept reads
+	EnableMultipleWriteLocations bool                     `json:"enableMultipleWriteLocations"` // true if writes are accepted in all regions
+	ConsistencyPolicy            AccountConsistencyPolicy `json:"userConsistencyPolicy"`        // default consistency policy of the account
+	// QueryEngineConfiguration is the JSON-encoded query engine configuration, see QueryEngineConfig.
+	QueryEngineConfiguration string `json:"queryEngineConfiguration"`
+}
+
+// QueryEngineConfig parses and returns the query engine configuration (e.g. "maxSqlQueryInputLength", "maxJoinsPerSqlQuery"...).
+func (info DatabaseAccountInfo) QueryEngineConfig() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if info.QueryEngineConfiguration == "" {
+		return result, nil
+	}
+	err := json.Unmarshal([]byte(info.QueryEngineConfiguration), &result)
+	return result, err
+}
+
+// RespGetDatabaseAccount captures the response from RestClient.GetDatabaseAccount call.
+//
+// @Available since v1.2.0
+type RespGetDatabaseAccount struct {
+	RestResponse
+	DatabaseAccountInfo
+}
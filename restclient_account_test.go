@@ -0,0 +1,108 @@
+package gocosmos
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const _testAccountKey = "C2y6yDjf5/R+ob0N8A7Cgv30VRDJIWEHLM+4QDU5DE2nQ9nDuVTqobD4b8mGGyPMbIZnqyMsEcaGQy67XIw/Jw=="
+
+func TestRegionRouter_BackgroundRefresh(t *testing.T) {
+	testName := "TestRegionRouter_BackgroundRefresh"
+	var accountHits int32
+	global := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&accountHits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"writableLocations":[],"readableLocations":[]}`))
+	}))
+	defer global.Close()
+	client, err := NewRestClient(nil, "AccountEndpoint="+global.URL+";AccountKey="+_testAccountKey+";PreferredRegions=West US")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	_ = client.Close()
+	client.regions.refreshInterval = 10 * time.Millisecond
+	client.regions.start(client)
+
+	// an idle client must keep its topology fresh
+	time.Sleep(100 * time.Millisecond)
+	if hits := atomic.LoadInt32(&accountHits); hits < 2 {
+		t.Fatalf("%s failed: expected the topology to be refreshed in the background, but it was fetched %d time(s)", testName, hits)
+	}
+
+	_ = client.Close()
+	time.Sleep(20 * time.Millisecond)
+	hits := atomic.LoadInt32(&accountHits)
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&accountHits) != hits {
+		t.Fatalf("%s failed: background refresh must stop after Close", testName)
+	}
+}
+
+func TestRestClient_do_RerouteFailure(t *testing.T) {
+	testName := "TestRestClient_do_RerouteFailure"
+	var bodies []string
+	region := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(503)
+		_, _ = w.Write([]byte(`{"code":"ServiceUnavailable"}`))
+	}))
+	defer region.Close()
+	global := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, "global:"+string(body))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"writableLocations":[{"name":"West US","databaseAccountEndpoint":"%s/"},`+
+			`{"name":"East US","databaseAccountEndpoint":"http://[::1"}],"readableLocations":[]}`, region.URL)))
+	}))
+	defer global.Close()
+	client, err := NewRestClient(nil, "AccountEndpoint="+global.URL+";AccountKey="+_testAccountKey+";PreferredRegions=West US")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// the second candidate endpoint is invalid: the last response must be returned, the original request must not be re-sent
+	result := client.CreateDatabase(DatabaseSpec{Id: "mydb"})
+	if result.StatusCode != 503 {
+		t.Fatalf("%s failed: expected status 503 but received %d (%s)", testName, result.StatusCode, result.Error())
+	}
+	if len(bodies) != 1 || bodies[0] != `{"id":"mydb"}` {
+		t.Fatalf("%s failed: expected a single request to the first region but received %#v", testName, bodies)
+	}
+}
+
+func TestRestClient_do_RerouteFailureFirstAttempt(t *testing.T) {
+	testName := "TestRestClient_do_RerouteFailureFirstAttempt"
+	var dataHits int32
+	global := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			atomic.AddInt32(&dataHits, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"writableLocations":[{"name":"West US","databaseAccountEndpoint":"http://[::1"}],"readableLocations":[]}`))
+	}))
+	defer global.Close()
+	client, err := NewRestClient(nil, "AccountEndpoint="+global.URL+";AccountKey="+_testAccountKey+";PreferredRegions=West US")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// the request must fail with the reroute error, rather than bypassing the routing
+	if result := client.CreateDatabase(DatabaseSpec{Id: "mydb"}); result.Error() == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+	if hits := atomic.LoadInt32(&dataHits); hits != 0 {
+		t.Fatalf("%s failed: the original request must not be sent, but %d request(s) received", testName, hits)
+	}
+}
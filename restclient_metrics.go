@@ -0,0 +1,216 @@
+package gocosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// QueryMetrics captures the query execution metrics returned by the server (response header x-ms-documentdb-query-metrics).
+//
+// Metrics of a query spanning multiple pages or partition key ranges are aggregated: times and counts are summed up,
+// IndexUtilizationRatio is averaged, weighted by RetrievedDocumentCount.
+//
+// @Available since v1.2.0
+type QueryMetrics struct {
+	TotalExecutionTimeInMs         float64 // total time to execute the query on server
+	QueryCompileTimeInMs           float64 // time to compile the query
+	QueryLogicalPlanBuildTimeInMs  float64 // time to build the logical query plan
+	QueryPhysicalPlanBuildTimeInMs float64 // time to build the physical query plan
+	QueryOptimizationTimeInMs      float64 // time to optimize the query
+	VMExecutionTimeInMs            float64 // time spent in the query runtime
+	IndexLookupTimeInMs            float64 // time spent in the physical index layer
+	DocumentLoadTimeInMs           float64 // time to load documents
+	SystemFunctionExecuteTimeInMs  float64 // time to execute system (built-in) functions
+	UserFunctionExecuteTimeInMs    float64 // time to execute user-defined functions
+	DocumentWriteTimeInMs          float64 // time to write the query result
+	RetrievedDocumentCount         int64   // number of documents retrieved (loaded) by the query
+	RetrievedDocumentSize          int64   // total size, in bytes, of retrieved documents
+	OutputDocumentCount            int64   // number of documents returned by the query
+	OutputDocumentSize             int64   // total size, in bytes, of returned documents
+	IndexUtilizationRatio          float64 // ratio of the number of documents matched by the filter to the number of documents loaded
+}
+
+// parseQueryMetrics parses the value of response header x-ms-documentdb-query-metrics, which is a list of key=value
+// pairs delimited by semicolons. nil is returned if the header value is empty.
+func parseQueryMetrics(headerValue string) *QueryMetrics {
+	headerValue = strings.TrimSpace(headerValue)
+	if headerValue == "" {
+		return nil
+	}
+	metrics := &QueryMetrics{}
+	for _, pair := range strings.Split(headerValue, ";") {
+		tokens := strings.SplitN(pair, "=", 2)
+		if len(tokens) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(tokens[0]), strings.TrimSpace(tokens[1])
+		f, _ := strconv.ParseFloat(value, 64)
+		switch strings.ToLower(key) {
+		case "totalexecutiontimeinms":
+			metrics.TotalExecutionTimeInMs = f
+		case "querycompiletimeinms":
+			metrics.QueryCompileTimeInMs = f
+		case "querylogicalplanbuildtimeinms":
+			metrics.QueryLogicalPlanBuildTimeInMs = f
+		case "queryphysicalplanbuildtimeinms":
+			metrics.QueryPhysicalPlanBuildTimeInMs = f
+		case "queryoptimizationtimeinms":
+			metrics.QueryOptimizationTimeInMs = f
+		case "vmexecutiontimeinms":
+			metrics.VMExecutionTimeInMs = f
+		case "indexlookuptimeinms":
+			metrics.IndexLookupTimeInMs = f
+		case "documentloadtimeinms":
+			metrics.DocumentLoadTimeInMs = f
+		case "systemfunctionexecutetimeinms":
+			metrics.SystemFunctionExecuteTimeInMs = f
+		case "userfunctionexecutetimeinms":
+			metrics.UserFunctionExecuteTimeInMs = f
+		case "writeoutputtimeinms", "documentwritetimeinms":
+			metrics.DocumentWriteTimeInMs = f
+		case "retrieveddocumentcount":
+			metrics.RetrievedDocumentCount = int64(f)
+		case "retrieveddocumentsize":
+			metrics.RetrievedDocumentSize = int64(f)
+		case "outputdocumentcount":
+			metrics.OutputDocumentCount = int64(f)
+		case "outputdocumentsize":
+			metrics.OutputDocumentSize = int64(f)
+		case "indexutilizationratio":
+			metrics.IndexUtilizationRatio = f
+		}
+	}
+	return metrics
+}
+
+// Add returns the aggregation of this metrics and another one. Either of them can be nil.
+func (m *QueryMetrics) Add(other *QueryMetrics) *QueryMetrics {
+	if m == nil || other == nil {
+		if m == nil {
+			m = other
+		}
+		if m == nil {
+			return nil
+		}
+		result := *m
+		return &result
+	}
+	result := QueryMetrics{
+		TotalExecutionTimeInMs:         m.TotalExecutionTimeInMs + other.TotalExecutionTimeInMs,
+		QueryCompileTimeInMs:           m.QueryCompileTimeInMs + other.QueryCompileTimeInMs,
+		QueryLogicalPlanBuildTimeInMs:  m.QueryLogicalPlanBuildTimeInMs + other.QueryLogicalPlanBuildTimeInMs,
+		QueryPhysicalPlanBuildTimeInMs: m.QueryPhysicalPlanBuildTimeInMs + other.QueryPhysicalPlanBuildTimeInMs,
+		QueryOptimizationTimeInMs:      m.QueryOptimizationTimeInMs + other.QueryOptimizationTimeInMs,
+		VMExecutionTimeInMs:            m.VMExecutionTimeInMs + other.VMExecutionTimeInMs,
+		IndexLookupTimeInMs:            m.IndexLookupTimeInMs + other.IndexLookupTimeInMs,
+		DocumentLoadTimeInMs:           m.DocumentLoadTimeInMs + other.DocumentLoadTimeInMs,
+		SystemFunctionExecuteTimeInMs:  m.SystemFunctionExecuteTimeInMs + other.SystemFunctionExecuteTimeInMs,
+		UserFunctionExecuteTimeInMs:    m.UserFunctionExecuteTimeInMs + other.UserFunctionExecuteTimeInMs,
+		DocumentWriteTimeInMs:          m.DocumentWriteTimeInMs + other.DocumentWriteTimeInMs,
+		RetrievedDocumentCount:         m.RetrievedDocumentCount + other.RetrievedDocumentCount,
+		RetrievedDocumentSize:          m.RetrievedDocumentSize + other.RetrievedDocumentSize,
+		OutputDocumentCount:            m.OutputDocumentCount + other.OutputDocumentCount,
+		OutputDocumentSize:             m.OutputDocumentSize + other.OutputDocumentSize,
+	}
+	if result.RetrievedDocumentCount > 0 {
+		result.IndexUtilizationRatio = (m.IndexUtilizationRatio*float64(m.RetrievedDocumentCount) +
+			other.IndexUtilizationRatio*float64(other.RetrievedDocumentCount)) / float64(result.RetrievedDocumentCount)
+	} else {
+		result.IndexUtilizationRatio = (m.IndexUtilizationRatio + other.IndexUtilizationRatio) / 2
+	}
+	return &result
+}
+
+// SingleIndexMetrics describes a single (range) index utilized by, or that would benefit, a query.
+//
+// @Available since v1.2.0
+type SingleIndexMetrics struct {
+	FilterExpression string `json:"FilterExpression"` // the filter expression the index is used for
+	IndexSpec        string `json:"IndexSpec"`        // the index path, e.g. /name/?
+	FilterPreciseSet bool   `json:"FilterPreciseSet"`
+	IndexPreciseSet  bool   `json:"IndexPreciseSet"`
+	IndexImpactScore string `json:"IndexImpactScore"` // possible values: High, Low
+}
+
+// CompositeIndexMetrics describes a composite index utilized by, or that would benefit, a query.
+//
+// @Available since v1.2.0
+type CompositeIndexMetrics struct {
+	IndexSpecs       []string `json:"IndexSpecs"` // the index paths, e.g. ["/name ASC", "/age DESC"]
+	IndexPreciseSet  bool     `json:"IndexPreciseSet"`
+	IndexImpactScore string   `json:"IndexImpactScore"` // possible values: High, Low
+}
+
+// IndexMetrics captures the index utilization metrics returned by the server (response header x-ms-cosmos-index-utilization).
+// The server returns index metrics only if requested (see QueryReq.PopulateIndexMetrics).
+//
+// Metrics of a query spanning multiple pages or partition key ranges are aggregated, duplicated entries are removed.
+//
+// @Available since v1.2.0
+type IndexMetrics struct {
+	UtilizedSingleIndexes     []SingleIndexMetrics    `json:"UtilizedSingleIndexes"`     // single indexes used by the query
+	PotentialSingleIndexes    []SingleIndexMetrics    `json:"PotentialSingleIndexes"`    // single indexes recommended to be added
+	UtilizedCompositeIndexes  []CompositeIndexMetrics `json:"UtilizedCompositeIndexes"`  // composite indexes used by the query
+	PotentialCompositeIndexes []CompositeIndexMetrics `json:"PotentialCompositeIndexes"` // composite indexes recommended to be added
+}
+
+// parseIndexMetrics parses the value of response header x-ms-cosmos-index-utilization, which is a base64-encoded
+// (or URL-encoded) JSON document. nil is returned if the header value is empty or invalid.
+func parseIndexMetrics(headerValue string) *IndexMetrics {
+	headerValue = strings.TrimSpace(headerValue)
+	if headerValue == "" {
+		return nil
+	}
+	js, err := base64.StdEncoding.DecodeString(headerValue)
+	if err != nil {
+		unescaped, err := url.QueryUnescape(headerValue)
+		if err != nil {
+			return nil
+		}
+		js = []byte(unescaped)
+	}
+	metrics := &IndexMetrics{}
+	if json.Unmarshal(js, metrics) != nil {
+		return nil
+	}
+	return metrics
+}
+
+// Add returns the aggregation of this metrics and another one. Either of them can be nil.
+func (m *IndexMetrics) Add(other *IndexMetrics) *IndexMetrics {
+	if m == nil && other == nil {
+		return nil
+	}
+	result := &IndexMetrics{}
+	for _, metrics := range []*IndexMetrics{m, other} {
+		if metrics == nil {
+			continue
+		}
+		result.UtilizedSingleIndexes = _appendDistinct(result.UtilizedSingleIndexes, metrics.UtilizedSingleIndexes)
+		result.PotentialSingleIndexes = _appendDistinct(result.PotentialSingleIndexes, metrics.PotentialSingleIndexes)
+		result.UtilizedCompositeIndexes = _appendDistinct(result.UtilizedCompositeIndexes, metrics.UtilizedCompositeIndexes)
+		result.PotentialCompositeIndexes = _appendDistinct(result.PotentialCompositeIndexes, metrics.PotentialCompositeIndexes)
+	}
+	return result
+}
+
+// _appendDistinct appends items to the target slice, skipping those already existing in the target slice.
+func _appendDistinct[T any](target, items []T) []T {
+	for _, item := range items {
+		found := false
+		for _, existing := range target {
+			if reflect.DeepEqual(existing, item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			target = append(target, item)
+		}
+	}
+	return target
+}
@@ -0,0 +1,92 @@
+package gocosmos
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func Test_parseQueryMetrics(t *testing.T) {
+	testName := "Test_parseQueryMetrics"
+	if metrics := parseQueryMetrics(""); metrics != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", testName, metrics)
+	}
+	header := "totalExecutionTimeInMs=0.56;queryCompileTimeInMs=0.05;queryLogicalPlanBuildTimeInMs=0.01;queryPhysicalPlanBuildTimeInMs=0.02;" +
+		"queryOptimizationTimeInMs=0.00;VMExecutionTimeInMs=0.12;indexLookupTimeInMs=0.05;documentLoadTimeInMs=0.03;" +
+		"systemFunctionExecuteTimeInMs=0.00;userFunctionExecuteTimeInMs=0.00;retrievedDocumentCount=4;retrievedDocumentSize=600;" +
+		"outputDocumentCount=2;outputDocumentSize=350;writeOutputTimeInMs=0.01;indexUtilizationRatio=0.50;unknownMetric=1"
+	expected := &QueryMetrics{TotalExecutionTimeInMs: 0.56, QueryCompileTimeInMs: 0.05, QueryLogicalPlanBuildTimeInMs: 0.01,
+		QueryPhysicalPlanBuildTimeInMs: 0.02, VMExecutionTimeInMs: 0.12, IndexLookupTimeInMs: 0.05, DocumentLoadTimeInMs: 0.03,
+		DocumentWriteTimeInMs: 0.01, RetrievedDocumentCount: 4, RetrievedDocumentSize: 600, OutputDocumentCount: 2, OutputDocumentSize: 350,
+		IndexUtilizationRatio: 0.5}
+	if metrics := parseQueryMetrics(header); !reflect.DeepEqual(metrics, expected) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expected, metrics)
+	}
+}
+
+func TestQueryMetrics_Add(t *testing.T) {
+	testName := "TestQueryMetrics_Add"
+	var nilMetrics *QueryMetrics
+	if metrics := nilMetrics.Add(nil); metrics != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", testName, metrics)
+	}
+	m1 := &QueryMetrics{TotalExecutionTimeInMs: 1.5, RetrievedDocumentCount: 1, OutputDocumentCount: 1, IndexUtilizationRatio: 1}
+	m2 := &QueryMetrics{TotalExecutionTimeInMs: 0.5, RetrievedDocumentCount: 3, OutputDocumentCount: 2, IndexUtilizationRatio: 0}
+	if metrics := nilMetrics.Add(m1); !reflect.DeepEqual(metrics, m1) || metrics == m1 {
+		t.Fatalf("%s failed: expected a copy of %#v but received %#v", testName, m1, metrics)
+	}
+	expected := &QueryMetrics{TotalExecutionTimeInMs: 2, RetrievedDocumentCount: 4, OutputDocumentCount: 3, IndexUtilizationRatio: 0.25}
+	if metrics := m1.Add(m2); !reflect.DeepEqual(metrics, expected) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expected, metrics)
+	}
+}
+
+func Test_parseIndexMetrics(t *testing.T) {
+	testName := "Test_parseIndexMetrics"
+	js := `{"UtilizedSingleIndexes":[{"FilterExpression":"(ROOT.name = \"a\")","IndexSpec":"/name/?","FilterPreciseSet":true,"IndexPreciseSet":true,"IndexImpactScore":"High"}],` +
+		`"PotentialSingleIndexes":[],"UtilizedCompositeIndexes":[],"PotentialCompositeIndexes":[{"IndexSpecs":["/name ASC","/age ASC"],"IndexPreciseSet":false,"IndexImpactScore":"High"}]}`
+	expected := &IndexMetrics{
+		UtilizedSingleIndexes:     []SingleIndexMetrics{{FilterExpression: `(ROOT.name = "a")`, IndexSpec: "/name/?", FilterPreciseSet: true, IndexPreciseSet: true, IndexImpactScore: "High"}},
+		PotentialSingleIndexes:    []SingleIndexMetrics{},
+		UtilizedCompositeIndexes:  []CompositeIndexMetrics{},
+		PotentialCompositeIndexes: []CompositeIndexMetrics{{IndexSpecs: []string{"/name ASC", "/age ASC"}, IndexImpactScore: "High"}},
+	}
+	if metrics := parseIndexMetrics(base64.StdEncoding.EncodeToString([]byte(js))); !reflect.DeepEqual(metrics, expected) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expected, metrics)
+	}
+	if metrics := parseIndexMetrics(""); metrics != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", testName, metrics)
+	}
+	if metrics := parseIndexMetrics("not a json"); metrics != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", testName, metrics)
+	}
+
+	aggregated := expected.Add(expected).Add(&IndexMetrics{UtilizedSingleIndexes: []SingleIndexMetrics{{IndexSpec: "/age/?"}}})
+	if len(aggregated.UtilizedSingleIndexes) != 2 || len(aggregated.PotentialCompositeIndexes) != 1 {
+		t.Fatalf("%s failed: duplicated entries must be removed, received %#v", testName, aggregated)
+	}
+}
+
+func Test_extractMetricsArgs(t *testing.T) {
+	testName := "Test_extractMetricsArgs"
+	queryMetrics, indexMetrics := &QueryMetrics{}, &IndexMetrics{}
+	args := []driver.NamedValue{{Ordinal: 1, Value: "a"}, {Ordinal: 2, Value: sql.Out{Dest: queryMetrics}},
+		{Ordinal: 3, Value: 3}, {Name: "im", Ordinal: 4, Value: sql.Out{Dest: indexMetrics}}}
+	remaining, qm, im, err := _extractMetricsArgs(args)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if expected := []driver.NamedValue{args[0], args[2]}; !reflect.DeepEqual(remaining, expected) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expected, remaining)
+	}
+	if qm != queryMetrics || im != indexMetrics {
+		t.Fatalf("%s failed: output arguments not extracted", testName)
+	}
+
+	var unsupported string
+	if _, _, _, err := _extractMetricsArgs([]driver.NamedValue{{Ordinal: 1, Value: sql.Out{Dest: &unsupported}}}); err == nil {
+		t.Fatalf("%s failed: unsupported output argument must be rejected", testName)
+	}
+}
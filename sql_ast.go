@@ -0,0 +1,336 @@
+package gocosmos
+
+// astStmt is a statement parsed from a query.
+//
+// @Available since v1.2.0
+type astStmt interface {
+	// withOpts returns the options specified via WITH clause.
+	withOpts() []astWithOpt
+}
+
+// astWithOpt is an option of the WITH clause, e.g. "WITH PK=/id".
+type astWithOpt struct {
+	key   string // option name, in upper case
+	value string // raw text of the option value, empty if the option has no value
+}
+
+// astWith is embedded in statements that accept a WITH clause.
+type astWith struct {
+	with []astWithOpt
+}
+
+func (n *astWith) withOpts() []astWithOpt {
+	return n.with
+}
+
+// astReturning is embedded in statements that accept a RETURNING clause.
+type astReturning struct {
+	returning []string // fields listed in the RETURNING clause, nil if the clause is not specified
+}
+
+// astWhere is a WHERE clause. It is either a list of "<field>=<value>" terms joined by AND, or a Cosmos DB SQL predicate.
+type astWhere struct {
+	text         string         // raw text of the clause
+	terms        []astWhereTerm // "<field>=<value> [AND <field>=<value>...]" form, nil if the clause is a predicate
+	predicate    string         // predicate form, placeholders rewritten to named parameters @_<index>
+	placeholders map[int]string // (predicate form) placeholder index -> parameter name
+	maxIndex     int            // (predicate form) max placeholder index
+}
+
+type astWhereTerm struct {
+	field string
+	value interface{} // a literal value or a placeholder
+}
+
+/*----------------------------------------------------------------------*/
+
+type astCreateDatabase struct {
+	astWith
+	ifNotExists bool
+	db          string
+}
+
+type astAlterDatabase struct {
+	astWith
+	db string
+}
+
+type astDropDatabase struct {
+	astWith
+	ifExists bool
+	db       string
+}
+
+type astListDatabases struct {
+	astWith
+}
+
+type astCreateCollection struct {
+	astWith
+	ifNotExists bool
+	db, coll    string
+}
+
+type astAlterCollection struct {
+	astWith
+	db, coll string
+}
+
+type astDropCollection struct {
+	astWith
+	ifExists bool
+	db, coll string
+}
+
+type astListCollections struct {
+	astWith
+	db string
+}
+
+type astDescribeCollection struct {
+	astWith
+	db, coll string
+}
+
+// astShowThroughput is "SHOW THROUGHPUT FOR DATABASE|COLLECTION ...", coll is empty for a database.
+type astShowThroughput struct {
+	astWith
+	db, coll string
+}
+
+// astIndexDef is "[INCLUDED|EXCLUDED|COMPOSITE|SPATIAL] INDEX ... ON [<db-name>.]<collection-name> (<path>[ ASC|DESC][,...])".
+type astIndexDef struct {
+	kind     string // one of indexKind... constants
+	db, coll string
+	paths    []string
+	orders   []string // (composite index) order of each path, "ascending" or "descending"
+}
+
+type astCreateIndex struct {
+	astWith
+	astIndexDef
+	ifNotExists bool
+}
+
+type astDropIndex struct {
+	astWith
+	astIndexDef
+	ifExists bool
+}
+
+type astCreateScript struct {
+	astWith
+	orReplace        bool
+	scriptType       string // one of scriptType... constants
+	db, coll, script string
+	body             interface{} // script body, either a string or a placeholder
+}
+
+type astDropScript struct {
+	astWith
+	scriptType       string
+	ifExists         bool
+	db, coll, script string
+}
+
+type astListScripts struct {
+	astWith
+	scriptType string
+	db, coll   string
+}
+
+/*----------------------------------------------------------------------*/
+
+// astInsert is "INSERT|UPSERT INTO ... (<fields>) VALUES (<values>)[, (<values>)]*".
+type astInsert struct {
+	astWith
+	astReturning
+	isUpsert               bool
+	db, coll               string
+	fields                 []string
+	rows                   [][]interface{}
+	fieldsText, valuesText string // raw text of the field list and the VALUES clause
+}
+
+// astInsertValue is "INSERT|UPSERT INTO ... VALUE <document>".
+type astInsertValue struct {
+	astWith
+	astReturning
+	isUpsert bool
+	db, coll string
+	value    interface{} // either a placeholder or the document as a JSON string
+}
+
+// astInsertSelect is "INSERT|UPSERT INTO ... SELECT ...".
+type astInsertSelect struct {
+	astWith
+	astReturning
+	isUpsert bool
+	db, coll string
+	source   *astSelect
+}
+
+// astSelect is "SELECT [CROSS PARTITION] ... FROM <collection> ...".
+type astSelect struct {
+	astWith
+	astReturning
+	isCrossPartition bool
+	body             *astSelectQuery
+	coll             string         // collection name found in the FROM clause
+	alias            string         // alias referring to documents of the collection, empty if there is none (e.g. FROM <sub-query>)
+	query            string         // the query, without CROSS PARTITION and WITH clause, placeholders rewritten to named parameters @_<index>
+	placeholders     map[int]string // placeholder index -> parameter name
+	maxIndex         int            // max placeholder index
+}
+
+// astExplain is "EXPLAIN SELECT ...".
+type astExplain struct {
+	query *astSelect
+}
+
+func (n *astExplain) withOpts() []astWithOpt {
+	return n.query.withOpts()
+}
+
+// astUpdate is "UPDATE ... SET|REMOVE ... WHERE ...".
+type astUpdate struct {
+	astWith
+	astReturning
+	db, coll   string
+	fields     []string
+	values     []interface{} // a literal value, a placeholder, incrValue, appendValue or removeValue
+	updateText string        // raw text of the SET/REMOVE clauses
+	where      *astWhere
+}
+
+// astDelete is "DELETE FROM ... WHERE ...".
+type astDelete struct {
+	astWith
+	astReturning
+	db, coll string
+	where    *astWhere
+}
+
+// astDeleteAll is "DELETE ALL FROM ... WHERE ...".
+type astDeleteAll struct {
+	astWith
+	astReturning
+	db, coll string
+	where    *astWhere
+}
+
+/*----------------------------------------------------------------------*/
+
+// astSelectQuery is a query following Cosmos DB SQL grammar (https://learn.microsoft.com/en-us/azure/cosmos-db/nosql/query/select):
+//
+//	SELECT [DISTINCT] [TOP <n>] <projection> [FROM <source> [JOIN <source>]*] [WHERE <expr>]
+//	[GROUP BY <expr>[,<expr>]*] [ORDER BY <expr> [ASC|DESC][,<expr> [ASC|DESC]]*] [OFFSET <n> LIMIT <m>]
+type astSelectQuery struct {
+	distinct    bool
+	top         astExpr
+	isValue     bool            // SELECT VALUE <expr>
+	projections []astProjection // nil means SELECT *
+	from        []astFromSource // the first item is the FROM source, the others are JOINed
+	where       astExpr
+	groupBy     []astExpr
+	orderBy     []astOrderItem
+	offset      astExpr
+	limit       astExpr
+}
+
+type astProjection struct {
+	expr  astExpr
+	alias string
+}
+
+// astFromSource is either "<container> [[AS] <alias>]" or "<alias> IN <container>" (iteration).
+type astFromSource struct {
+	container astExpr // astIdent, astProperty/astIndex rooted at astIdent, or astSubquery
+	alias     string
+	iteration bool
+}
+
+type astOrderItem struct {
+	expr astExpr
+	desc bool
+}
+
+// astExpr is a scalar expression of Cosmos DB SQL grammar.
+type astExpr interface {
+	exprNode()
+}
+
+type (
+	astIdent       struct{ name string }
+	astLiteral     struct{ value interface{} } // number (float64), string, bool, nil (null) or astUndefined
+	astPlaceholder struct{ index int }         // positional placeholder, e.g. :1, @2 or $3
+	astParam       struct {
+		name  string // named parameter, e.g. @name
+		index int    // placeholder index the parameter is bound to (see sqlParams)
+	}
+	astProperty struct {
+		target astExpr
+		name   string // c.name or c["name"]
+	}
+	astIndex struct {
+		target astExpr
+		index  astExpr // c[<expr>], where <expr> is not a string literal
+	}
+	astUnary struct {
+		op      string // -, +, ~ or NOT
+		operand astExpr
+	}
+	astBinary struct {
+		op          string // operator in upper case, e.g. AND, OR, =, !=, +, ||, ??
+		left, right astExpr
+	}
+	astBetween struct {
+		not             bool
+		expr, low, high astExpr
+	}
+	astIn struct {
+		not  bool
+		expr astExpr
+		list []astExpr
+	}
+	astLike struct {
+		not                   bool
+		expr, pattern, escape astExpr // escape is nil if not specified
+	}
+	astTernary struct {
+		cond, then, els astExpr
+	}
+	astCall struct {
+		name string // function name, e.g. CONTAINS or udf.myFunc
+		args []astExpr
+	}
+	astSubquery struct {
+		kind  string // "", EXISTS or ARRAY
+		query *astSelectQuery
+	}
+	astArray  struct{ items []astExpr }
+	astObject struct {
+		keys   []string
+		values []astExpr
+	}
+)
+
+// astUndefined is the value of literal "undefined".
+type astUndefined struct{}
+
+func (*astIdent) exprNode()       {}
+func (*astLiteral) exprNode()     {}
+func (*astPlaceholder) exprNode() {}
+func (*astParam) exprNode()       {}
+func (*astProperty) exprNode()    {}
+func (*astIndex) exprNode()       {}
+func (*astUnary) exprNode()       {}
+func (*astBinary) exprNode()      {}
+func (*astBetween) exprNode()     {}
+func (*astIn) exprNode()          {}
+func (*astLike) exprNode()        {}
+func (*astTernary) exprNode()     {}
+func (*astCall) exprNode()        {}
+func (*astSubquery) exprNode()    {}
+func (*astArray) exprNode()       {}
+func (*astObject) exprNode()      {}
@@ -0,0 +1,256 @@
+package gocosmos
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError is returned when a query cannot be parsed. It reports the position (1-based line and column) where parsing failed.
+//
+// @Available since v1.2.0
+type SyntaxError struct {
+	Line, Column int
+	Msg          string
+}
+
+// Error implements interface error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+type tokenType int
+
+const (
+	tokEOF         tokenType = iota
+	tokIdent                 // identifier or keyword, e.g. SELECT, mytable or _etag
+	tokNumber                // number literal, e.g. 1, 2.5 or 1e3
+	tokString                // double-quoted string literal
+	tokSqString              // single-quoted string literal
+	tokPlaceholder           // positional placeholder: $1, @1 or :1
+	tokParam                 // named parameter, e.g. @name
+	tokPunct                 // operator or punctuation, e.g. ( ) , . = != <=
+)
+
+// token is a lexical token of a query.
+type token struct {
+	typ       tokenType
+	text      string // raw text of the token, as found in the query
+	pos, end  int    // byte offsets of the token in the query
+	line, col int    // 1-based position of the token in the query
+}
+
+// String implements interface fmt.Stringer/String.
+func (t token) String() string {
+	if t.typ == tokEOF && t.text == "" {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// isKeyword checks if the token is one of the specified keywords (case-insensitive).
+func (t token) isKeyword(keywords ...string) bool {
+	if t.typ != tokIdent {
+		return false
+	}
+	for _, kw := range keywords {
+		if strings.EqualFold(t.text, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPunct checks if the token is one of the specified operators/punctuations.
+func (t token) isPunct(puncts ...string) bool {
+	if t.typ != tokPunct {
+		return false
+	}
+	for _, p := range puncts {
+		if t.text == p {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// punctuations, longest first
+	lexPuncts = []string{">>>", "!=", "<>", "<=", ">=", "||", "??", "<<", ">>",
+		"(", ")", "[", "]", "{", "}", ",", ".", "=", "<", ">", "+", "-", "*", "/", "%", "&", "|", "^", "~", "!", "?", ":", ";"}
+)
+
+// lexer splits a query into tokens.
+//
+// @Available since v1.2.0
+type lexer struct {
+	input     string
+	pos       int
+	line, col int
+}
+
+func _isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func _isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func _isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func (l *lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.input) {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[l.pos+offset:])
+	return r
+}
+
+// advance moves forward n bytes, keeping track of line and column.
+func (l *lexer) advance(n int) {
+	for _, r := range l.input[l.pos : l.pos+n] {
+		if r == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+	}
+	l.pos += n
+}
+
+func (l *lexer) errorf(line, col int, format string, args ...interface{}) error {
+	return &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// _tokenize splits the input into tokens. The last token is always tokEOF.
+//
+// @Available since v1.2.0
+func _tokenize(input string) ([]token, error) {
+	l := &lexer{input: input, line: 1, col: 1}
+	tokens := make([]token, 0)
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.typ == tokEOF {
+			return tokens, nil
+		}
+	}
+}
+
+// next scans the next token.
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.peekRune(0)) {
+		_, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		l.advance(size)
+	}
+	tok := token{pos: l.pos, line: l.line, col: l.col}
+	if l.pos >= len(l.input) {
+		tok.end = l.pos
+		return tok, nil
+	}
+
+	n, r := 0, l.peekRune(0)
+	switch {
+	case _isIdentStart(r):
+		tok.typ, n = tokIdent, l.scanIdent(0)
+	case _isDigit(l.input[l.pos]):
+		tok.typ, n = tokNumber, l.scanNumber()
+	case r == '"' || r == '\'':
+		tok.typ = tokString
+		if r == '\'' {
+			tok.typ = tokSqString
+		}
+		ok := false
+		if n, ok = l.scanString(byte(r)); !ok {
+			return tok, l.errorf(tok.line, tok.col, "unterminated string literal")
+		}
+	case (r == '$' || r == '@' || r == ':') && l.pos+1 < len(l.input) && _isDigit(l.input[l.pos+1]):
+		tok.typ, n = tokPlaceholder, 1
+		for l.pos+n < len(l.input) && _isDigit(l.input[l.pos+n]) {
+			n++
+		}
+	case r == '@' && _isIdentStart(l.peekRune(1)):
+		tok.typ, n = tokParam, l.scanIdent(1)
+	default:
+		for _, p := range lexPuncts {
+			if strings.HasPrefix(l.input[l.pos:], p) {
+				tok.typ, n = tokPunct, len(p)
+				break
+			}
+		}
+		if n == 0 {
+			return tok, l.errorf(tok.line, tok.col, "unexpected character %q", r)
+		}
+	}
+	tok.text = l.input[l.pos : l.pos+n]
+	tok.end = l.pos + n
+	l.advance(n)
+	return tok, nil
+}
+
+// scanIdent returns the length of the identifier starting at offset.
+func (l *lexer) scanIdent(offset int) int {
+	n := offset
+	for l.pos+n < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos+n:])
+		if !_isIdentPart(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// scanNumber returns the length of the number literal, e.g. 12, 1.5 or 2e-3.
+func (l *lexer) scanNumber() int {
+	s := l.input[l.pos:]
+	n := 0
+	digits := func() {
+		for n < len(s) && _isDigit(s[n]) {
+			n++
+		}
+	}
+	digits()
+	if n+1 < len(s) && s[n] == '.' && _isDigit(s[n+1]) {
+		n++
+		digits()
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		if m < len(s) && _isDigit(s[m]) {
+			n = m
+			digits()
+		}
+	}
+	return n
+}
+
+// scanString returns the length of the string literal enclosed by quote.
+// A backslash escapes the next character; inside a single-quoted string, two consecutive single quotes also represent one single quote.
+func (l *lexer) scanString(quote byte) (int, bool) {
+	s := l.input[l.pos:]
+	for n := 1; n < len(s); n++ {
+		switch s[n] {
+		case '\\':
+			n++
+		case quote:
+			if quote == '\'' && n+1 < len(s) && s[n+1] == '\'' {
+				n++
+				continue
+			}
+			return n + 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,1140 @@
+package gocosmos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btnguyen2k/consu/g18"
+)
+
+// sqlParser is a recursive-descent parser that builds the AST of a statement from the tokens of a query.
+//
+// @Available since v1.2.0
+type sqlParser struct {
+	query  string
+	tokens []token    // the last token is always tokEOF
+	i      int        // index of the next token
+	params *sqlParams // parameters found in the statement, shared with sub-parsers
+}
+
+// sqlParams keeps track of the parameters found in a statement.
+// Named parameters (e.g. @name) are numbered in order of their first appearance, so that they are bound the same way as positional placeholders.
+//
+// @Available since v1.2.0
+type sqlParams struct {
+	names                       []string // names of the named parameters, without the leading @
+	firstNamed, firstPositional *token
+}
+
+// index returns the 1-based index of the named parameter, registering it if it has not been seen yet.
+func (ps *sqlParams) index(name string) int {
+	if i := g18.FindInSlice(name, ps.names); i >= 0 {
+		return i + 1
+	}
+	ps.names = append(ps.names, name)
+	return len(ps.names)
+}
+
+// _parseSql parses the query and returns the AST of the statement, together with the names of the named parameters
+// (the i-th name is bound to placeholder index i+1).
+//
+// @Available since v1.2.0
+func _parseSql(query string) (astStmt, []string, error) {
+	tokens, err := _tokenize(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &sqlParser{query: query, tokens: tokens, params: &sqlParams{}}
+	stmt, err := p.parseStatement()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	if named, positional := p.params.firstNamed, p.params.firstPositional; named != nil && positional != nil {
+		tok := named
+		if positional.pos > named.pos {
+			tok = positional
+		}
+		return nil, nil, p.errorf(*tok, "named and positional parameters must not be mixed, found %s", tok)
+	}
+	return stmt, p.params.names, nil
+}
+
+// _parseLiteral parses the input as a single literal value (see sqlParser.parseLiteral).
+// Named parameters are resolved against paramNames.
+//
+// @Available since v1.2.0
+func _parseLiteral(input string, paramNames []string) (interface{}, error) {
+	tokens, err := _tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{query: input, tokens: tokens, params: &sqlParams{names: append([]string{}, paramNames...)}}
+	value, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	return value, nil
+}
+
+func (p *sqlParser) peek() token {
+	return p.tokens[p.i]
+}
+
+// peekN returns the n-th token after the next one (peekN(0) is the next token).
+func (p *sqlParser) peekN(n int) token {
+	if p.i+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.i+n]
+}
+
+func (p *sqlParser) next() token {
+	tok := p.tokens[p.i]
+	if tok.typ != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+// prevEnd returns the end offset of the last consumed token.
+func (p *sqlParser) prevEnd() int {
+	if p.i == 0 {
+		return 0
+	}
+	return p.tokens[p.i-1].end
+}
+
+// adjacent checks if the next token immediately follows the last consumed token, i.e. there is no whitespace between them.
+func (p *sqlParser) adjacent() bool {
+	return p.i > 0 && p.peek().typ != tokEOF && p.peek().pos == p.prevEnd()
+}
+
+func (p *sqlParser) errorf(tok token, format string, args ...interface{}) error {
+	return &SyntaxError{Line: tok.line, Column: tok.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *sqlParser) acceptKeyword(keywords ...string) (token, bool) {
+	if tok := p.peek(); tok.isKeyword(keywords...) {
+		return p.next(), true
+	}
+	return token{}, false
+}
+
+func (p *sqlParser) expectKeyword(keywords ...string) (token, error) {
+	if tok, ok := p.acceptKeyword(keywords...); ok {
+		return tok, nil
+	}
+	return token{}, p.errorf(p.peek(), "expecting %s but found %s", strings.Join(keywords, " or "), p.peek())
+}
+
+func (p *sqlParser) acceptPunct(punct string) bool {
+	if p.peek().isPunct(punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectPunct(punct string) (token, error) {
+	if tok := p.peek(); tok.isPunct(punct) {
+		return p.next(), nil
+	}
+	return token{}, p.errorf(p.peek(), "expecting %q but found %s", punct, p.peek())
+}
+
+// sub returns a parser for tokens in range [from, to). The token at index "to" becomes the end of the sub-query.
+func (p *sqlParser) sub(from, to int) *sqlParser {
+	tokens := make([]token, to-from+1)
+	copy(tokens, p.tokens[from:to])
+	tokens[to-from] = p.tokens[to]
+	tokens[to-from].typ = tokEOF
+	return &sqlParser{query: p.query, tokens: tokens, params: p.params}
+}
+
+// scanClause returns the index of the token that ends the clause starting at the next token:
+// the WITH or RETURNING keyword outside of parentheses/brackets/braces, or the end of the query.
+func (p *sqlParser) scanClause() int {
+	depth := 0
+	for i := p.i; ; i++ {
+		tok := p.tokens[i]
+		switch {
+		case tok.typ == tokEOF:
+			return i
+		case tok.isPunct("(", "[", "{"):
+			depth++
+		case tok.isPunct(")", "]", "}"):
+			depth--
+		case depth == 0 && tok.isKeyword("WITH", "RETURNING") && !p.tokens[i-1].isPunct("."):
+			return i
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+// parseName parses a name (e.g. database, collection or field name) consisting of letters, digits, underscores and dashes.
+func (p *sqlParser) parseName(what string) (string, error) {
+	start := p.peek()
+	if start.typ != tokIdent && start.typ != tokNumber {
+		return "", p.errorf(start, "expecting %s but found %s", what, start)
+	}
+	p.next()
+	for p.adjacent() && (p.peek().typ == tokIdent || p.peek().typ == tokNumber || p.peek().isPunct("-")) {
+		p.next()
+	}
+	return p.query[start.pos:p.prevEnd()], nil
+}
+
+// parseQualifiedName parses a dot-separated name, e.g. <db-name>.<collection-name>, which has from min to max parts.
+func (p *sqlParser) parseQualifiedName(min, max int, what string) ([]string, error) {
+	start := p.peek()
+	name, err := p.parseName(what)
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{name}
+	for len(parts) < max && p.adjacent() && p.peek().isPunct(".") {
+		p.next()
+		if !p.adjacent() {
+			return nil, p.errorf(p.peek(), "expecting %s but found %s", what, p.peek())
+		}
+		if name, err = p.parseName(what); err != nil {
+			return nil, err
+		}
+		parts = append(parts, name)
+	}
+	if len(parts) < min {
+		return nil, p.errorf(start, "expecting %s but found %q", what, p.query[start.pos:p.prevEnd()])
+	}
+	return parts, nil
+}
+
+// parseDbColl parses "[<db-name>.]<collection-name>".
+func (p *sqlParser) parseDbColl() (db, coll string, err error) {
+	parts, err := p.parseQualifiedName(1, 2, "[<db-name>.]<collection-name>")
+	if err != nil {
+		return "", "", err
+	}
+	if len(parts) == 1 {
+		return "", parts[0], nil
+	}
+	return parts[0], parts[1], nil
+}
+
+// parseDbCollScript parses "[<db-name>.]<collection-name>.<script-name>".
+func (p *sqlParser) parseDbCollScript() (db, coll, script string, err error) {
+	parts, err := p.parseQualifiedName(2, 3, "[<db-name>.]<collection-name>.<script-name>")
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(parts) == 2 {
+		return "", parts[0], parts[1], nil
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// parsePath parses a (possibly nested) field path, e.g. name, address.city or tags[0].
+func (p *sqlParser) parsePath() (string, error) {
+	start := p.peek()
+	if _, err := p.parseName("field name"); err != nil {
+		return "", err
+	}
+	for p.adjacent() {
+		if p.acceptPunct(".") {
+			if !p.adjacent() {
+				return "", p.errorf(p.peek(), "expecting field name but found %s", p.peek())
+			}
+			if _, err := p.parseName("field name"); err != nil {
+				return "", err
+			}
+		} else if p.acceptPunct("[") {
+			if tok := p.peek(); !p.adjacent() || tok.typ != tokNumber || strings.ContainsAny(tok.text, ".eE") {
+				return "", p.errorf(tok, "expecting array index but found %s", tok)
+			}
+			p.next()
+			if !p.adjacent() || !p.peek().isPunct("]") {
+				return "", p.errorf(p.peek(), "expecting \"]\" but found %s", p.peek())
+			}
+			p.next()
+		} else {
+			break
+		}
+	}
+	return p.query[start.pos:p.prevEnd()], nil
+}
+
+// acceptText consumes the tokens that spell exactly the given text, without whitespace in between.
+func (p *sqlParser) acceptText(text string) bool {
+	save, start := p.i, p.peek()
+	for tok := p.peek(); tok.typ != tokEOF && (p.i == save || p.adjacent()); tok = p.peek() {
+		n := tok.end - start.pos
+		if n > len(text) || p.query[start.pos:tok.end] != text[:n] {
+			break
+		}
+		p.next()
+		if n == len(text) {
+			return true
+		}
+	}
+	p.i = save
+	return false
+}
+
+func (p *sqlParser) parseIfNotExists() (bool, error) {
+	if _, ok := p.acceptKeyword("IF"); !ok {
+		return false, nil
+	}
+	if _, err := p.expectKeyword("NOT"); err != nil {
+		return false, err
+	}
+	_, err := p.expectKeyword("EXISTS")
+	return err == nil, err
+}
+
+func (p *sqlParser) parseIfExists() (bool, error) {
+	if _, ok := p.acceptKeyword("IF"); !ok {
+		return false, nil
+	}
+	_, err := p.expectKeyword("EXISTS")
+	return err == nil, err
+}
+
+/*----------------------------------------------------------------------*/
+
+// parsePlaceholder parses a positional placeholder (e.g. $1, @2 or :3), or a named parameter (e.g. @name).
+// A named parameter is returned as a placeholder whose index is the parameter's position in the statement (see sqlParams).
+func (p *sqlParser) parsePlaceholder() (placeholder, error) {
+	tok := p.next()
+	if tok.typ == tokParam {
+		return placeholder{p.paramIndex(tok)}, nil
+	}
+	index, err := strconv.Atoi(tok.text[1:])
+	if err != nil || index <= 0 {
+		return placeholder{}, p.errorf(tok, "invalid placeholder %s", tok)
+	}
+	p.seenPositional(tok)
+	return placeholder{index}, nil
+}
+
+// paramIndex returns the index of the named parameter token.
+func (p *sqlParser) paramIndex(tok token) int {
+	if p.params == nil {
+		p.params = &sqlParams{}
+	}
+	if p.params.firstNamed == nil {
+		p.params.firstNamed = &tok
+	}
+	return p.params.index(tok.text[1:])
+}
+
+// seenPositional records the positional placeholder token.
+func (p *sqlParser) seenPositional(tok token) {
+	if p.params == nil {
+		p.params = &sqlParams{}
+	}
+	if p.params.firstPositional == nil {
+		p.params.firstPositional = &tok
+	}
+}
+
+// parseLiteral parses a value, which is either:
+//   - a placeholder (e.g. :1, @2 or $3) or a named parameter (e.g. @name)
+//   - null
+//   - a boolean (true/false)
+//   - a number
+//   - a string (inside double quotes) that must be a valid JSON, e.g. "\"a string\"" or "{\"key\":\"value\"}"
+//   - an unquoted string, e.g. abc
+func (p *sqlParser) parseLiteral() (interface{}, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case tokPlaceholder, tokParam:
+		return p.parsePlaceholder()
+	case tokNumber:
+		p.next()
+		return p.parseJson(tok, tok.text)
+	case tokPunct:
+		if next := p.peekN(1); tok.isPunct("-") && next.typ == tokNumber && next.pos == tok.end {
+			p.next()
+			p.next()
+			return p.parseJson(tok, "-"+next.text)
+		}
+	case tokString:
+		p.next()
+		unquoted, err := strconv.Unquote(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid string literal %s", tok)
+		}
+		return p.parseJson(tok, unquoted)
+	case tokIdent:
+		p.next()
+		for p.adjacent() && (p.peek().typ == tokIdent || p.peek().typ == tokNumber || p.peek().isPunct(".", "/", ";", ":", "-")) {
+			p.next()
+		}
+		value := p.query[tok.pos:p.prevEnd()]
+		switch strings.ToLower(value) {
+		case "null":
+			return nil, nil
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return value, nil
+	}
+	return nil, p.errorf(tok, "expecting a value but found %s", tok)
+}
+
+func (p *sqlParser) parseJson(tok token, input string) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(input), &data); err != nil {
+		return nil, p.errorf(tok, "invalid JSON value %s", input)
+	}
+	return data, nil
+}
+
+// parseQuotedOrPlaceholder parses a single-quoted string or a placeholder.
+// Inside a single-quoted string, two consecutive single quotes represent one single quote.
+func (p *sqlParser) parseQuotedOrPlaceholder(what string) (interface{}, error) {
+	switch tok := p.peek(); tok.typ {
+	case tokPlaceholder, tokParam:
+		return p.parsePlaceholder()
+	case tokSqString:
+		p.next()
+		return strings.ReplaceAll(tok.text[1:len(tok.text)-1], "''", "'"), nil
+	}
+	return nil, p.errorf(p.peek(), "expecting a single-quoted string or a placeholder as %s but found %s", what, p.peek())
+}
+
+// rewritePlaceholders returns the query text from offset start to the end of token range [from, to),
+// with placeholders rewritten to named parameters @_<index>. Named parameters are kept as-is.
+func (p *sqlParser) rewritePlaceholders(start, from, to int) (text string, placeholders map[int]string, maxIndex int) {
+	placeholders = make(map[int]string)
+	sb := strings.Builder{}
+	last := start
+	for _, tok := range p.tokens[from:to] {
+		if tok.typ == tokParam {
+			index := p.paramIndex(tok)
+			placeholders[index] = tok.text
+			if index > maxIndex {
+				maxIndex = index
+			}
+			continue
+		}
+		if tok.typ != tokPlaceholder {
+			continue
+		}
+		p.seenPositional(tok)
+		index, _ := strconv.Atoi(tok.text[1:])
+		name := "@_" + strconv.Itoa(index)
+		sb.WriteString(p.query[last:tok.pos])
+		sb.WriteString(name)
+		last = tok.end
+		placeholders[index] = name
+		if index > maxIndex {
+			maxIndex = index
+		}
+	}
+	if to > from {
+		sb.WriteString(p.query[last:p.tokens[to-1].end])
+	}
+	return sb.String(), placeholders, maxIndex
+}
+
+/*----------------------------------------------------------------------*/
+
+// isOptValueToken checks if the token can be part of a WITH option value.
+func _isOptValueToken(tok token) bool {
+	switch tok.typ {
+	case tokIdent, tokNumber, tokString, tokSqString, tokPlaceholder, tokParam:
+		return true
+	}
+	return tok.isPunct("/", ".", "*", ",", ";", ":", "-")
+}
+
+// parseWith parses the WITH clause: WITH <key>[=<value>][[,] [WITH] <key>[=<value>]]*
+func (p *sqlParser) parseWith() ([]astWithOpt, error) {
+	if _, ok := p.acceptKeyword("WITH"); !ok {
+		return nil, nil
+	}
+	opts := make([]astWithOpt, 0)
+	for {
+		key, err := p.parseName("option name")
+		if err != nil {
+			return nil, err
+		}
+		opt := astWithOpt{key: strings.ToUpper(key)}
+		if p.acceptPunct("=") {
+			if opt.value, err = p.parseOptValue(opt.key); err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, opt)
+		sep := p.acceptPunct(",")
+		if _, ok := p.acceptKeyword("WITH"); ok {
+			sep = true
+		}
+		if !sep {
+			return opts, nil
+		}
+	}
+}
+
+// parseOptValue parses the value of a WITH option, which spans consecutive tokens not separated by whitespace, e.g. /a,/b or LWW:/ts.
+// A comma followed by whitespace or by "<name>=" separates options.
+func (p *sqlParser) parseOptValue(key string) (string, error) {
+	start := p.peek()
+	for tok := p.peek(); _isOptValueToken(tok) && (p.peek() == start || p.adjacent()); tok = p.peek() {
+		if next := p.peekN(1); tok.isPunct(",") && (next.pos != tok.end || next.typ == tokEOF || (next.typ == tokIdent && p.peekN(2).isPunct("="))) {
+			break
+		}
+		switch p.next(); tok.typ {
+		case tokParam:
+			p.paramIndex(tok)
+		case tokPlaceholder:
+			p.seenPositional(tok)
+		}
+	}
+	if p.peek() == start {
+		return "", p.errorf(start, "expecting value of WITH %s but found %s", key, start)
+	}
+	return p.query[start.pos:p.prevEnd()], nil
+}
+
+// parseReturning parses the RETURNING clause: RETURNING *|<field>[,<field>]*
+func (p *sqlParser) parseReturning() ([]string, error) {
+	if _, ok := p.acceptKeyword("RETURNING"); !ok {
+		return nil, nil
+	}
+	if p.acceptPunct("*") {
+		return []string{"*"}, nil
+	}
+	fields := make([]string, 0)
+	for {
+		field, err := p.parseName("field name")
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if !p.acceptPunct(",") {
+			return fields, nil
+		}
+	}
+}
+
+// parseWhere parses the WHERE clause, which spans up to the WITH/RETURNING clause or the end of the query.
+// The clause is either "<field>=<value> [AND <field>=<value>...]", or a Cosmos DB SQL predicate referring to documents via alias "c" (only if allowPredicate is true).
+// If allowPredicate is true, the former form must specify the document id, i.e. "id=<value> [AND <pk-field>=<value>...]".
+func (p *sqlParser) parseWhere(allowPredicate bool) (*astWhere, error) {
+	from, to := p.i, p.scanClause()
+	if from == to {
+		return nil, p.errorf(p.peek(), "expecting WHERE condition but found %s", p.peek())
+	}
+	where := &astWhere{text: p.query[p.tokens[from].pos:p.tokens[to-1].end]}
+	p.i = to
+
+	termsParser := p.sub(from, to)
+	terms, err := termsParser.parseWhereTerms()
+	if err == nil && (!allowPredicate || _hasIdTerm(terms)) {
+		where.terms = terms
+		return where, nil
+	}
+	if !allowPredicate {
+		return nil, err
+	}
+	errNeitherForm := p.errorf(p.tokens[from], `expecting either "id=<value> [AND <pk-field>=<value>...]" or a predicate referring to documents via alias c (e.g. "c.status='active'") in WHERE clause`)
+	if err == nil {
+		// "<field>=<value>" terms without id
+		return nil, errNeitherForm
+	}
+	// the predicate must follow Cosmos DB SQL grammar
+	sub := p.sub(from, to)
+	_, predicateErr := sub.parseExpr()
+	if tok := sub.peek(); predicateErr == nil && tok.typ != tokEOF {
+		predicateErr = sub.errorf(tok, "unexpected %s", tok)
+	}
+	if predicateErr != nil {
+		// report the error of the form that matches the clause further
+		if termsParser.i >= sub.i {
+			return nil, err
+		}
+		return nil, predicateErr
+	}
+	refersAlias := false
+	for i := from; i < to-1; i++ {
+		refersAlias = refersAlias || (p.tokens[i].isKeyword("c") && p.tokens[i+1].isPunct(".", "["))
+	}
+	if !refersAlias {
+		return nil, errNeitherForm
+	}
+	where.predicate, where.placeholders, where.maxIndex = sub.rewritePlaceholders(p.tokens[from].pos, 0, len(sub.tokens)-1)
+	return where, nil
+}
+
+func _hasIdTerm(terms []astWhereTerm) bool {
+	for _, term := range terms {
+		if strings.ToLower(term.field) == "id" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *sqlParser) parseWhereTerms() ([]astWhereTerm, error) {
+	terms := make([]astWhereTerm, 0)
+	for {
+		field, err := p.parseName("field name")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectPunct("="); err != nil {
+			return nil, err
+		}
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, astWhereTerm{field: field, value: value})
+		if p.peek().typ == tokEOF {
+			return terms, nil
+		}
+		if _, err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func (p *sqlParser) parseStatement() (astStmt, error) {
+	tok := p.peek()
+	switch {
+	case tok.isKeyword("CREATE"):
+		return p.parseCreate()
+	case tok.isKeyword("ALTER"):
+		return p.parseAlter()
+	case tok.isKeyword("DROP"):
+		return p.parseDrop()
+	case tok.isKeyword("LIST"):
+		return p.parseList()
+	case tok.isKeyword("DESCRIBE", "DESC"):
+		return p.parseDescribe()
+	case tok.isKeyword("SHOW"):
+		return p.parseShow()
+	case tok.isKeyword("INSERT", "UPSERT"):
+		return p.parseInsert()
+	case tok.isKeyword("SELECT"):
+		stmt, err := p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+	case tok.isKeyword("EXPLAIN"):
+		p.next()
+		if tok := p.peek(); !tok.isKeyword("SELECT") {
+			return nil, p.errorf(tok, "expecting SELECT but found %s", tok)
+		}
+		stmt, err := p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+		return &astExplain{query: stmt}, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+	case tok.isKeyword("UPDATE"):
+		return p.parseUpdate()
+	case tok.isKeyword("DELETE"):
+		return p.parseDelete()
+	}
+	return nil, p.errorf(tok, "unknown statement %s", tok)
+}
+
+// parseWithReturning parses the optional WITH and RETURNING clauses at the end of a statement.
+func (p *sqlParser) parseWithReturning(with *astWith, returning *astReturning) error {
+	var err error
+	if with.with, err = p.parseWith(); err != nil {
+		return err
+	}
+	if returning != nil {
+		returning.returning, err = p.parseReturning()
+	}
+	return err
+}
+
+func (p *sqlParser) parseCreate() (astStmt, error) {
+	p.next() // CREATE
+	orReplace := false
+	if _, ok := p.acceptKeyword("OR"); ok {
+		if _, err := p.expectKeyword("REPLACE"); err != nil {
+			return nil, err
+		}
+		orReplace = true
+	}
+	objTypes := []string{"DATABASE", "COLLECTION", "TABLE", "PROCEDURE", "FUNCTION", "TRIGGER", "INDEX", "INCLUDED", "EXCLUDED", "COMPOSITE", "SPATIAL"}
+	if orReplace {
+		objTypes = objTypes[3:6]
+	}
+	objType, err := p.expectKeyword(objTypes...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case objType.isKeyword("DATABASE"):
+		stmt := &astCreateDatabase{}
+		if stmt.ifNotExists, err = p.parseIfNotExists(); err != nil {
+			return nil, err
+		}
+		if stmt.db, err = p.parseName("database name"); err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, nil)
+	case objType.isKeyword("COLLECTION", "TABLE"):
+		stmt := &astCreateCollection{}
+		if stmt.ifNotExists, err = p.parseIfNotExists(); err != nil {
+			return nil, err
+		}
+		if stmt.db, stmt.coll, err = p.parseDbColl(); err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, nil)
+	case objType.isKeyword("INDEX", "INCLUDED", "EXCLUDED", "COMPOSITE", "SPATIAL"):
+		stmt := &astCreateIndex{}
+		if stmt.kind, err = p.parseIndexKind(objType); err != nil {
+			return nil, err
+		}
+		if stmt.ifNotExists, err = p.parseIfNotExists(); err != nil {
+			return nil, err
+		}
+		if err = p.parseIndexDef(&stmt.astIndexDef); err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, nil)
+	}
+	stmt := &astCreateScript{orReplace: orReplace, scriptType: _normalizeScriptType(objType.text)}
+	if stmt.db, stmt.coll, stmt.script, err = p.parseDbCollScript(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	if stmt.body, err = p.parseQuotedOrPlaceholder("script body"); err != nil {
+		return nil, err
+	}
+	return stmt, p.parseWithReturning(&stmt.astWith, nil)
+}
+
+func (p *sqlParser) parseAlter() (astStmt, error) {
+	p.next() // ALTER
+	objType, err := p.expectKeyword("DATABASE", "COLLECTION", "TABLE")
+	if err != nil {
+		return nil, err
+	}
+	if objType.isKeyword("DATABASE") {
+		stmt := &astAlterDatabase{}
+		if stmt.db, err = p.parseName("database name"); err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, nil)
+	}
+	stmt := &astAlterCollection{}
+	if stmt.db, stmt.coll, err = p.parseDbColl(); err != nil {
+		return nil, err
+	}
+	return stmt, p.parseWithReturning(&stmt.astWith, nil)
+}
+
+func (p *sqlParser) parseDrop() (astStmt, error) {
+	p.next() // DROP
+	objType, err := p.expectKeyword("DATABASE", "COLLECTION", "TABLE", "PROCEDURE", "FUNCTION", "TRIGGER",
+		"INDEX", "INCLUDED", "EXCLUDED", "COMPOSITE", "SPATIAL")
+	if err != nil {
+		return nil, err
+	}
+	indexKind := ""
+	if objType.isKeyword("INDEX", "INCLUDED", "EXCLUDED", "COMPOSITE", "SPATIAL") {
+		if indexKind, err = p.parseIndexKind(objType); err != nil {
+			return nil, err
+		}
+	}
+	ifExists, err := p.parseIfExists()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case objType.isKeyword("DATABASE"):
+		stmt := &astDropDatabase{ifExists: ifExists}
+		stmt.db, err = p.parseName("database name")
+		return stmt, err
+	case objType.isKeyword("COLLECTION", "TABLE"):
+		stmt := &astDropCollection{ifExists: ifExists}
+		stmt.db, stmt.coll, err = p.parseDbColl()
+		return stmt, err
+	case indexKind != "":
+		stmt := &astDropIndex{astIndexDef: astIndexDef{kind: indexKind}, ifExists: ifExists}
+		return stmt, p.parseIndexDef(&stmt.astIndexDef)
+	}
+	stmt := &astDropScript{scriptType: _normalizeScriptType(objType.text), ifExists: ifExists}
+	stmt.db, stmt.coll, stmt.script, err = p.parseDbCollScript()
+	return stmt, err
+}
+
+func (p *sqlParser) parseList() (astStmt, error) {
+	p.next() // LIST
+	objType, err := p.expectKeyword("DATABASES", "DATABASE", "COLLECTIONS", "COLLECTION", "TABLES", "TABLE",
+		"PROCEDURES", "PROCEDURE", "FUNCTIONS", "FUNCTION", "TRIGGERS", "TRIGGER")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case objType.isKeyword("DATABASES", "DATABASE"):
+		return &astListDatabases{}, nil
+	case objType.isKeyword("COLLECTIONS", "COLLECTION", "TABLES", "TABLE"):
+		stmt := &astListCollections{}
+		if _, ok := p.acceptKeyword("FROM"); ok {
+			stmt.db, err = p.parseName("database name")
+		}
+		return stmt, err
+	}
+	stmt := &astListScripts{scriptType: _normalizeScriptType(objType.text)}
+	if _, err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	stmt.db, stmt.coll, err = p.parseDbColl()
+	return stmt, err
+}
+
+// parseIndexKind parses the kind of index "[INCLUDED|EXCLUDED|COMPOSITE|SPATIAL] INDEX", the first token of which has been consumed.
+func (p *sqlParser) parseIndexKind(first token) (string, error) {
+	if first.isKeyword("INDEX") {
+		return indexKindIncluded, nil
+	}
+	if _, err := p.expectKeyword("INDEX"); err != nil {
+		return "", err
+	}
+	return strings.ToLower(first.text), nil
+}
+
+// parseIndexDef parses "ON [<db-name>.]<collection-name> (<path>[ ASC|DESC][,<path>[ ASC|DESC]]*)".
+func (p *sqlParser) parseIndexDef(def *astIndexDef) error {
+	if _, err := p.expectKeyword("ON"); err != nil {
+		return err
+	}
+	var err error
+	if def.db, def.coll, err = p.parseDbColl(); err != nil {
+		return err
+	}
+	if _, err := p.expectPunct("("); err != nil {
+		return err
+	}
+	for {
+		path, err := p.parseIndexPath()
+		if err != nil {
+			return err
+		}
+		def.paths = append(def.paths, path)
+		if def.kind == indexKindComposite {
+			order := "ascending"
+			if tok, ok := p.acceptKeyword("ASC", "DESC"); ok && tok.isKeyword("DESC") {
+				order = "descending"
+			}
+			def.orders = append(def.orders, order)
+		}
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	_, err = p.expectPunct(")")
+	return err
+}
+
+// parseIndexPath parses an index path, e.g. /name/?, /tags/[]/* or /"field name"/?, which spans consecutive tokens not separated by whitespace.
+func (p *sqlParser) parseIndexPath() (string, error) {
+	start := p.peek()
+	if !start.isPunct("/") {
+		return "", p.errorf(start, "expecting index path but found %s", start)
+	}
+	p.next()
+	for p.adjacent() && !p.peek().isPunct(",") && !p.peek().isPunct(")") {
+		p.next()
+	}
+	return p.query[start.pos:p.prevEnd()], nil
+}
+
+func (p *sqlParser) parseDescribe() (astStmt, error) {
+	p.next() // DESCRIBE
+	if next := p.peekN(1); p.peek().isKeyword("COLLECTION", "TABLE") && next.typ != tokEOF && !next.isPunct(".") {
+		// "COLLECTION|TABLE" is optional, and can also be the name of the collection or database
+		p.next()
+	}
+	stmt := &astDescribeCollection{}
+	var err error
+	stmt.db, stmt.coll, err = p.parseDbColl()
+	return stmt, err
+}
+
+func (p *sqlParser) parseShow() (astStmt, error) {
+	p.next() // SHOW
+	if _, err := p.expectKeyword("THROUGHPUT"); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword("FOR"); err != nil {
+		return nil, err
+	}
+	objType, err := p.expectKeyword("DATABASE", "COLLECTION", "TABLE")
+	if err != nil {
+		return nil, err
+	}
+	stmt := &astShowThroughput{}
+	if objType.isKeyword("DATABASE") {
+		stmt.db, err = p.parseName("database name")
+	} else {
+		stmt.db, stmt.coll, err = p.parseDbColl()
+	}
+	return stmt, err
+}
+
+func (p *sqlParser) parseInsert() (astStmt, error) {
+	isUpsert := p.next().isKeyword("UPSERT")
+	if _, err := p.expectKeyword("INTO"); err != nil {
+		return nil, err
+	}
+	db, coll, err := p.parseDbColl()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := p.peek(); {
+	case tok.isKeyword("VALUE"):
+		p.next()
+		stmt := &astInsertValue{isUpsert: isUpsert, db: db, coll: coll}
+		if stmt.value, err = p.parseQuotedOrPlaceholder("document"); err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+	case tok.isKeyword("SELECT"):
+		stmt := &astInsertSelect{isUpsert: isUpsert, db: db, coll: coll}
+		if stmt.source, err = p.parseSelect(); err != nil {
+			return nil, err
+		}
+		return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+	case !tok.isPunct("("):
+		return nil, p.errorf(tok, "expecting field list, VALUE or SELECT but found %s", tok)
+	}
+
+	stmt := &astInsert{isUpsert: isUpsert, db: db, coll: coll}
+	p.next() // (
+	fieldsStart := p.peek()
+	for {
+		field, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		stmt.fields = append(stmt.fields, field)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	stmt.fieldsText = p.query[fieldsStart.pos:p.prevEnd()]
+	if _, err := p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword("VALUES"); err != nil {
+		return nil, err
+	}
+	valuesStart := p.peek()
+	for {
+		row, err := p.parseTuple()
+		if err != nil {
+			return nil, err
+		}
+		stmt.rows = append(stmt.rows, row)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	stmt.valuesText = p.query[valuesStart.pos:p.prevEnd()]
+	return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+}
+
+// parseTuple parses a value-list enclosed in parentheses: (<value>[,<value>]*)
+func (p *sqlParser) parseTuple() ([]interface{}, error) {
+	if _, err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	row := make([]interface{}, 0)
+	for {
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, value)
+		if p.acceptPunct(")") {
+			return row, nil
+		}
+		if !p.acceptPunct(",") {
+			return nil, p.errorf(p.peek(), "expecting \",\" or \")\" but found %s", p.peek())
+		}
+	}
+}
+
+// parseSelect parses "SELECT [CROSS PARTITION] <cosmos-sql-query>", which ends at the WITH/RETURNING clause or the end of the query.
+func (p *sqlParser) parseSelect() (*astSelect, error) {
+	selectTok := p.next() // SELECT
+	stmt := &astSelect{}
+	prefixEnd := p.peek().pos
+	if p.peek().isKeyword("CROSS") && p.peekN(1).isKeyword("PARTITION") {
+		p.next()
+		p.next()
+		stmt.isCrossPartition = true
+	}
+	from := p.i
+	body, err := p.parseSelectBody()
+	if err != nil {
+		return nil, err
+	}
+	if body.from == nil {
+		return nil, p.errorf(p.peek(), "expecting FROM clause but found %s", p.peek())
+	}
+	if tok := p.peek(); tok.typ != tokEOF && !tok.isKeyword("WITH", "RETURNING") {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	stmt.body = body
+	stmt.coll, stmt.alias = body.collection()
+	var text string
+	text, stmt.placeholders, stmt.maxIndex = p.rewritePlaceholders(p.tokens[from].pos, from, p.i)
+	if !stmt.isCrossPartition {
+		prefixEnd = p.tokens[from].pos
+	}
+	stmt.query = p.query[selectTok.pos:prefixEnd] + text
+	return stmt, nil
+}
+
+func (p *sqlParser) parseUpdate() (astStmt, error) {
+	p.next() // UPDATE
+	stmt := &astUpdate{}
+	var err error
+	if stmt.db, stmt.coll, err = p.parseDbColl(); err != nil {
+		return nil, err
+	}
+	keyword, err := p.expectKeyword("SET", "REMOVE")
+	if err != nil {
+		return nil, err
+	}
+	isRemove := keyword.isKeyword("REMOVE")
+	for {
+		field, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		var value interface{} = removeValue{}
+		if !isRemove {
+			if _, err := p.expectPunct("="); err != nil {
+				return nil, err
+			}
+			if value, err = p.parseSetValue(field); err != nil {
+				return nil, err
+			}
+		}
+		stmt.fields = append(stmt.fields, field)
+		stmt.values = append(stmt.values, value)
+		if p.acceptPunct(",") {
+			continue
+		}
+		// SET/REMOVE keyword starts a new list of operations, unless it is a field name
+		if tok := p.peek(); tok.isKeyword("SET", "REMOVE") && !p.peekN(1).isPunct("=") {
+			isRemove = p.next().isKeyword("REMOVE")
+			continue
+		}
+		break
+	}
+	stmt.updateText = p.query[keyword.pos:p.prevEnd()]
+	if _, err := p.expectKeyword("WHERE"); err != nil {
+		return nil, err
+	}
+	if stmt.where, err = p.parseWhere(true); err != nil {
+		return nil, err
+	}
+	return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+}
+
+// parseSetValue parses the value part of "<field>=<value>" in the SET clause, which is either:
+//   - <field>+<number> or <field>-<number> (the number can be a placeholder)
+//   - ARRAY_APPEND(<field>, <value>)
+//   - a literal value (see parseLiteral)
+func (p *sqlParser) parseSetValue(field string) (interface{}, error) {
+	save := p.i
+	if p.acceptText(field) {
+		if op := p.peek(); op.isPunct("+", "-") {
+			p.next()
+			tok := p.peek()
+			value, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			switch value.(type) {
+			case float64, placeholder:
+			default:
+				return nil, p.errorf(tok, "invalid increment value for field %s: %s", field, tok)
+			}
+			return incrValue{value: value, negative: op.text == "-"}, nil
+		}
+		p.i = save
+	}
+
+	if p.peek().isKeyword("ARRAY_APPEND") && p.peekN(1).isPunct("(") {
+		p.next()
+		p.next()
+		tok := p.peek()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if path != field {
+			return nil, p.errorf(tok, "ARRAY_APPEND must refer to the updated field %s, got %s", field, path)
+		}
+		if _, err := p.expectPunct(","); err != nil {
+			return nil, err
+		}
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		return appendValue{value: value}, nil
+	}
+
+	return p.parseLiteral()
+}
+
+func (p *sqlParser) parseDelete() (astStmt, error) {
+	p.next() // DELETE
+	_, isDeleteAll := p.acceptKeyword("ALL")
+	if _, err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	db, coll, err := p.parseDbColl()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword("WHERE"); err != nil {
+		return nil, err
+	}
+	where, err := p.parseWhere(!isDeleteAll)
+	if err != nil {
+		return nil, err
+	}
+	if isDeleteAll {
+		stmt := &astDeleteAll{db: db, coll: coll, where: where}
+		return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+	}
+	stmt := &astDelete{db: db, coll: coll, where: where}
+	return stmt, p.parseWithReturning(&stmt.astWith, &stmt.astReturning)
+}
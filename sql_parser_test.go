@@ -0,0 +1,154 @@
+package gocosmos
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_tokenize(t *testing.T) {
+	testName := "Test_tokenize"
+	tokens, err := _tokenize("SELECT c.id, 'it''s' AS s FROM c\nWHERE c.a>=@1 AND c.b=@name AND c.c!=\"x\\\"y\" -- 1.5e3")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	expected := []struct {
+		typ  tokenType
+		text string
+	}{
+		{tokIdent, "SELECT"}, {tokIdent, "c"}, {tokPunct, "."}, {tokIdent, "id"}, {tokPunct, ","}, {tokSqString, "'it''s'"},
+		{tokIdent, "AS"}, {tokIdent, "s"}, {tokIdent, "FROM"}, {tokIdent, "c"},
+		{tokIdent, "WHERE"}, {tokIdent, "c"}, {tokPunct, "."}, {tokIdent, "a"}, {tokPunct, ">="}, {tokPlaceholder, "@1"},
+		{tokIdent, "AND"}, {tokIdent, "c"}, {tokPunct, "."}, {tokIdent, "b"}, {tokPunct, "="}, {tokParam, "@name"},
+		{tokIdent, "AND"}, {tokIdent, "c"}, {tokPunct, "."}, {tokIdent, "c"}, {tokPunct, "!="}, {tokString, `"x\"y"`},
+		{tokPunct, "-"}, {tokPunct, "-"}, {tokNumber, "1.5e3"}, {tokEOF, ""},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("%s failed: expected %d tokens but received %d", testName, len(expected), len(tokens))
+	}
+	for i, tok := range tokens {
+		if tok.typ != expected[i].typ || tok.text != expected[i].text {
+			t.Fatalf("%s failed: expected token #%d to be {%d %s} but received {%d %s}", testName, i, expected[i].typ, expected[i].text, tok.typ, tok.text)
+		}
+	}
+	if tokens[10].line != 2 || tokens[10].col != 1 {
+		t.Fatalf("%s failed: expected WHERE at line 2, column 1 but received line %d, column %d", testName, tokens[10].line, tokens[10].col)
+	}
+
+	for _, input := range []string{`SELECT 'unterminated`, `SELECT "unterminated`, "SELECT # FROM c"} {
+		if _, err := _tokenize(input); err == nil {
+			t.Fatalf("%s failed: tokenizing must fail for %q", testName, input)
+		}
+	}
+}
+
+func Test_parseSql_syntaxError(t *testing.T) {
+	testName := "Test_parseSql_syntaxError"
+	testData := []struct {
+		name         string
+		sql          string
+		line, column int
+	}{
+		{name: "unknown_statement", sql: `GRANT ALL ON db`, line: 1, column: 1},
+		{name: "empty_query", sql: ``, line: 1, column: 1},
+		{name: "missing_values", sql: `INSERT INTO db.table (a,b) VALUES (1,2`, line: 1, column: 39},
+		{name: "unexpected_token", sql: "UPDATE db.table\nSET a=1\nWHERE id=1 LIMIT 1", line: 3, column: 12},
+		{name: "unterminated_string", sql: "INSERT INTO db.table (a)\n  VALUES (\"abc)", line: 2, column: 11},
+		{name: "invalid_character", sql: "DELETE FROM db.table WHERE id=#1", line: 1, column: 31},
+		{name: "invalid_with_value", sql: "CREATE TABLE db.table WITH pk=", line: 1, column: 31},
+		{name: "invalid_placeholder", sql: "DELETE FROM db.table WHERE id=$0", line: 1, column: 31},
+		{name: "mixed_params", sql: "DELETE FROM db.table WHERE id=@id AND pk=$2", line: 1, column: 42},
+		{name: "explain_not_select", sql: "EXPLAIN UPDATE db.table SET a=1 WHERE id=1", line: 1, column: 9},
+		{name: "show_not_throughput", sql: "SHOW TABLES", line: 1, column: 6},
+		{name: "index_path_not_slash", sql: "CREATE INDEX ON db.table (name)", line: 1, column: 27},
+		{name: "where_no_id", sql: "UPDATE db.table SET a=1 WHERE pk=1 AND _etag=@1", line: 1, column: 31},
+		{name: "where_predicate_no_alias", sql: "DELETE FROM db.table WHERE status=1 AND age>18", line: 1, column: 28},
+		{name: "where_invalid_predicate", sql: "DELETE FROM db.table WHERE c.status=1 AND", line: 1, column: 42},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseQueryWithDefaultDb(nil, "", testCase.sql)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("%s failed: expected SyntaxError but received %#v", testName+"/"+testCase.name, err)
+			}
+			if syntaxErr.Line != testCase.line || syntaxErr.Column != testCase.column {
+				t.Fatalf("%s failed: expected error at line %d, column %d but received %s", testName+"/"+testCase.name, testCase.line, testCase.column, err)
+			}
+		})
+	}
+}
+
+func Test_parseSql_ast(t *testing.T) {
+	testName := "Test_parseSql_ast"
+	testData := []struct {
+		name     string
+		sql      string
+		expected astStmt
+	}{
+		{
+			name: "insert_parentheses_in_string",
+			sql:  `INSERT INTO db.table (a, b.c) VALUES ("\"(x))\"", -1.5), (:2, abc) RETURNING *`,
+			expected: &astInsert{astReturning: astReturning{returning: []string{"*"}}, db: "db", coll: "table", fields: []string{"a", "b.c"},
+				rows: [][]interface{}{{"(x))", -1.5}, {placeholder{2}, "abc"}}, fieldsText: "a, b.c", valuesText: `("\"(x))\"", -1.5), (:2, abc)`},
+		},
+		{
+			name: "update_predicate",
+			sql:  "UPDATE coll SET a=a+1 REMOVE b WHERE c.x > $2 AND c[\"y\"] = 'WITH' WITH PK=/pk, CONCURRENCY=2",
+			expected: &astUpdate{astWith: astWith{with: []astWithOpt{{key: "PK", value: "/pk"}, {key: "CONCURRENCY", value: "2"}}}, coll: "coll",
+				fields: []string{"a", "b"}, values: []interface{}{incrValue{value: 1.0}, removeValue{}}, updateText: "SET a=a+1 REMOVE b",
+				where: &astWhere{text: `c.x > $2 AND c["y"] = 'WITH'`, predicate: `c.x > @_2 AND c["y"] = 'WITH'`, placeholders: map[int]string{2: "@_2"}, maxIndex: 2}},
+		},
+		{
+			name: "select_cross_partition",
+			sql:  "SELECT CROSS PARTITION c.id FROM (SELECT * FROM users u) c WHERE c.a=@1 WITH db=mydb",
+			expected: &astSelect{astWith: astWith{with: []astWithOpt{{key: "DB", value: "mydb"}}}, isCrossPartition: true, coll: "users",
+				query: "SELECT c.id FROM (SELECT * FROM users u) c WHERE c.a=@_1", placeholders: map[int]string{1: "@_1"}, maxIndex: 1},
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			stmt, _, err := _parseSql(testCase.sql)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if selectStmt, ok := stmt.(*astSelect); ok {
+				selectStmt.body = nil // covered by Test_parseSelectQuery
+			}
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func Test_parseSql_withOpts(t *testing.T) {
+	testName := "Test_parseSql_withOpts"
+	testData := []struct {
+		name     string
+		sql      string
+		expected []astWithOpt
+	}{
+		{name: "create_database_with", sql: `CREATE DATABASE db WITH RU=400 WITH MAXRU=4000`, expected: []astWithOpt{{key: "RU", value: "400"}, {key: "MAXRU", value: "4000"}}},
+		{name: "create_database_comma", sql: `CREATE DATABASE db WITH RU=400, MAXRU=4000`, expected: []astWithOpt{{key: "RU", value: "400"}, {key: "MAXRU", value: "4000"}}},
+		{name: "alter_database_comma_with", sql: `ALTER DATABASE db WITH RU=400, WITH MAXRU=4000`, expected: []astWithOpt{{key: "RU", value: "400"}, {key: "MAXRU", value: "4000"}}},
+		{name: "create_collection_multi_value", sql: `CREATE COLLECTION db.coll WITH PK=/a,/b, RU=400 WITH UK=/x:/y,/z`, expected: []astWithOpt{{key: "PK", value: "/a,/b"}, {key: "RU", value: "400"}, {key: "UK", value: "/x:/y,/z"}}},
+		{name: "create_collection_comma_no_space", sql: `CREATE COLLECTION db.coll WITH PK=/a,RU=400`, expected: []astWithOpt{{key: "PK", value: "/a"}, {key: "RU", value: "400"}}},
+		{name: "alter_collection_comma", sql: `ALTER COLLECTION db.coll WITH RU=400,  TTL=60`, expected: []astWithOpt{{key: "RU", value: "400"}, {key: "TTL", value: "60"}}},
+		{name: "insert_flag_comma", sql: `INSERT INTO db.coll (a) VALUES (1) WITH singlePK, CONCURRENCY=3`, expected: []astWithOpt{{key: "SINGLEPK"}, {key: "CONCURRENCY", value: "3"}}},
+		{name: "upsert_comma_with", sql: `UPSERT INTO db.coll (a) VALUES (1) WITH PK=/a, WITH TTL=60`, expected: []astWithOpt{{key: "PK", value: "/a"}, {key: "TTL", value: "60"}}},
+		{name: "delete_comma", sql: `DELETE FROM db.coll WHERE id=@1 WITH singlePK, IF_MATCH=@2`, expected: []astWithOpt{{key: "SINGLEPK"}, {key: "IF_MATCH", value: "@2"}}},
+		{name: "select_comma", sql: `SELECT * FROM c WITH database=db, collection=coll WITH cross_partition=true`, expected: []astWithOpt{{key: "DATABASE", value: "db"}, {key: "COLLECTION", value: "coll"}, {key: "CROSS_PARTITION", value: "true"}}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			ast, _, err := _parseSql(testCase.sql)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if opts := ast.withOpts(); !reflect.DeepEqual(opts, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, opts)
+			}
+		})
+	}
+}
@@ -0,0 +1,728 @@
+package gocosmos
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// cosmosSqlKeywords are reserved keywords of Cosmos DB SQL grammar, which cannot be used as identifiers/aliases.
+	// WITH and RETURNING are not Cosmos DB SQL keywords, but they start the clauses following the query.
+	cosmosSqlKeywords = map[string]bool{
+		"SELECT": true, "DISTINCT": true, "TOP": true, "VALUE": true, "AS": true, "FROM": true, "JOIN": true, "IN": true,
+		"WHERE": true, "GROUP": true, "ORDER": true, "BY": true, "ASC": true, "DESC": true, "OFFSET": true, "LIMIT": true,
+		"AND": true, "OR": true, "NOT": true, "BETWEEN": true, "LIKE": true, "ESCAPE": true, "EXISTS": true, "ARRAY": true,
+		"UDF": true, "TRUE": true, "FALSE": true, "NULL": true, "UNDEFINED": true, "WITH": true, "RETURNING": true,
+	}
+
+	// binary operators, from the lowest to the highest precedence
+	logicalOps    = [][]string{{"??"}, {"OR"}, {"AND"}}
+	comparisonOps = []string{"=", "!=", "<>", "<", ">", "<=", ">="}
+	arithmeticOps = [][]string{{"|"}, {"^"}, {"&"}, {"<<", ">>", ">>>"}, {"+", "-", "||"}, {"*", "/", "%"}}
+)
+
+func _isReservedKeyword(tok token) bool {
+	return tok.typ == tokIdent && cosmosSqlKeywords[strings.ToUpper(tok.text)]
+}
+
+// collection returns the name of the collection the query reads from, and the alias referring to its documents.
+func (q *astSelectQuery) collection() (coll, alias string) {
+	if len(q.from) == 0 {
+		return "", ""
+	}
+	src := q.from[0]
+	root := src.container
+	for {
+		switch e := root.(type) {
+		case *astProperty:
+			root = e.target
+			continue
+		case *astIndex:
+			root = e.target
+			continue
+		case *astSubquery:
+			coll, _ = e.query.collection()
+			return coll, ""
+		case *astIdent:
+			coll = e.name
+		}
+		break
+	}
+	if src.iteration || root != src.container {
+		return coll, ""
+	}
+	if src.alias != "" {
+		return coll, src.alias
+	}
+	return coll, coll
+}
+
+// pkFilters returns the "<alias>.<path> = <value>" predicates joined by AND at the top level of the WHERE clause,
+// as a map of path (e.g. /address/city) to value (a literal value or a placeholder).
+// A path compared to different values is not included.
+func (q *astSelectQuery) pkFilters(alias string) map[string]interface{} {
+	if alias == "" || q.where == nil {
+		return nil
+	}
+	filters, conflicts := make(map[string]interface{}), make(map[string]bool)
+	var walk func(e astExpr)
+	walk = func(e astExpr) {
+		b, ok := e.(*astBinary)
+		if !ok {
+			return
+		}
+		switch b.op {
+		case "AND":
+			walk(b.left)
+			walk(b.right)
+		case "=":
+			for _, pair := range [][2]astExpr{{b.left, b.right}, {b.right, b.left}} {
+				path := _aliasPath(pair[0], alias)
+				value, ok := _exprValue(pair[1])
+				if path == "" || !ok {
+					continue
+				}
+				if existing, exist := filters[path]; exist && existing != value {
+					conflicts[path] = true
+				}
+				filters[path] = value
+				break
+			}
+		}
+	}
+	walk(q.where)
+	for path := range conflicts {
+		delete(filters, path)
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	return filters
+}
+
+// isPointRead checks if the query is "SELECT * FROM <collection> [[AS] <alias>] WHERE <alias>.<path>=<value> [AND <alias>.<path>=<value>]*",
+// i.e. it selects whole documents filtered only by equality predicates, each on a distinct path (see pkFilters).
+func (q *astSelectQuery) isPointRead(alias string) bool {
+	if alias == "" || q.distinct || q.top != nil || q.isValue || q.projections != nil || len(q.from) != 1 ||
+		q.groupBy != nil || q.orderBy != nil || q.offset != nil || q.where == nil {
+		return false
+	}
+	filters := q.pkFilters(alias)
+	return filters != nil && _countConjuncts(q.where) == len(filters)
+}
+
+// _countConjuncts returns the number of predicates joined by AND at the top level of the expression.
+func _countConjuncts(e astExpr) int {
+	if b, ok := e.(*astBinary); ok && b.op == "AND" {
+		return _countConjuncts(b.left) + _countConjuncts(b.right)
+	}
+	return 1
+}
+
+// _aliasPath returns the path (e.g. /address/city) of a property expression rooted at alias (e.g. c.address.city), empty string otherwise.
+func _aliasPath(e astExpr, alias string) string {
+	names := make([]string, 0)
+	for {
+		switch v := e.(type) {
+		case *astProperty:
+			names = append(names, v.name)
+			e = v.target
+			continue
+		case *astIdent:
+			if v.name != alias || len(names) == 0 {
+				return ""
+			}
+			sb := strings.Builder{}
+			for i := len(names) - 1; i >= 0; i-- {
+				sb.WriteString("/" + names[i])
+			}
+			return sb.String()
+		}
+		return ""
+	}
+}
+
+// _exprValue returns the value of a literal, placeholder or named parameter expression.
+func _exprValue(e astExpr) (interface{}, bool) {
+	switch v := e.(type) {
+	case *astLiteral:
+		if _, ok := v.value.(astUndefined); !ok {
+			return v.value, true
+		}
+	case *astPlaceholder:
+		return placeholder{v.index}, true
+	case *astParam:
+		return placeholder{v.index}, true
+	}
+	return nil, false
+}
+
+/*----------------------------------------------------------------------*/
+
+// parseSelectQuery parses a query following Cosmos DB SQL grammar, starting at the SELECT keyword.
+func (p *sqlParser) parseSelectQuery() (*astSelectQuery, error) {
+	if _, err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	return p.parseSelectBody()
+}
+
+// parseSelectBody parses a query following Cosmos DB SQL grammar, after the SELECT keyword.
+func (p *sqlParser) parseSelectBody() (*astSelectQuery, error) {
+	q := &astSelectQuery{}
+	var err error
+	_, q.distinct = p.acceptKeyword("DISTINCT")
+	if _, ok := p.acceptKeyword("TOP"); ok {
+		if q.top, err = p.parseCount("TOP"); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.parseProjections(q); err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.acceptKeyword("FROM"); ok {
+		for {
+			src, err := p.parseFromSource()
+			if err != nil {
+				return nil, err
+			}
+			q.from = append(q.from, src)
+			if _, ok := p.acceptKeyword("JOIN"); !ok {
+				break
+			}
+		}
+	}
+	if _, ok := p.acceptKeyword("WHERE"); ok {
+		if q.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("GROUP"); ok {
+		if _, err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			q.groupBy = append(q.groupBy, expr)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	}
+	if _, ok := p.acceptKeyword("ORDER"); ok {
+		if _, err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := astOrderItem{expr: expr}
+			if tok, ok := p.acceptKeyword("ASC", "DESC"); ok {
+				item.desc = tok.isKeyword("DESC")
+			}
+			q.orderBy = append(q.orderBy, item)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	}
+	if _, ok := p.acceptKeyword("OFFSET"); ok {
+		if q.offset, err = p.parseCount("OFFSET"); err != nil {
+			return nil, err
+		}
+		if _, err := p.expectKeyword("LIMIT"); err != nil {
+			return nil, err
+		}
+		if q.limit, err = p.parseCount("LIMIT"); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// parseCount parses the argument of TOP, OFFSET or LIMIT, which is a non-negative integer or a parameter.
+func (p *sqlParser) parseCount(clause string) (astExpr, error) {
+	switch tok := p.peek(); tok.typ {
+	case tokNumber:
+		if n, err := strconv.Atoi(tok.text); err == nil && n >= 0 {
+			p.next()
+			return &astLiteral{value: float64(n)}, nil
+		}
+	case tokPlaceholder:
+		ph, err := p.parsePlaceholder()
+		return &astPlaceholder{index: ph.index}, err
+	case tokParam:
+		p.next()
+		return &astParam{name: tok.text[1:], index: p.paramIndex(tok)}, nil
+	}
+	return nil, p.errorf(p.peek(), "expecting non-negative integer for %s but found %s", clause, p.peek())
+}
+
+// parseProjections parses "*", "VALUE <expr>" or "<expr> [[AS] <alias>][, <expr> [[AS] <alias>]]*".
+func (p *sqlParser) parseProjections(q *astSelectQuery) error {
+	if p.acceptPunct("*") {
+		return nil
+	}
+	if _, ok := p.acceptKeyword("VALUE"); ok {
+		expr, err := p.parseExpr()
+		q.isValue, q.projections = true, []astProjection{{expr: expr}}
+		return err
+	}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		proj := astProjection{expr: expr}
+		if p.peek().isKeyword("AS") && (p.peekN(1).typ == tokString || p.peekN(1).typ == tokSqString) {
+			// Cosmos DB also accepts a quoted projection alias, e.g. SELECT c.name AS 'Name'
+			p.next()
+			proj.alias = p.next().text
+		} else if proj.alias, err = p.parseAlias(); err != nil {
+			return err
+		}
+		q.projections = append(q.projections, proj)
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+// parseAlias parses the optional "[AS] <alias>".
+func (p *sqlParser) parseAlias() (string, error) {
+	_, hasAs := p.acceptKeyword("AS")
+	if tok := p.peek(); tok.typ == tokIdent && !_isReservedKeyword(tok) {
+		return p.next().text, nil
+	}
+	if hasAs {
+		return "", p.errorf(p.peek(), "expecting alias but found %s", p.peek())
+	}
+	return "", nil
+}
+
+// parseFromSource parses "<container> [[AS] <alias>]" or "<alias> IN <container>".
+func (p *sqlParser) parseFromSource() (astFromSource, error) {
+	var src astFromSource
+	var err error
+	if tok := p.peek(); tok.typ == tokIdent && !_isReservedKeyword(tok) && p.peekN(1).isKeyword("IN") {
+		src.alias, src.iteration = tok.text, true
+		p.next()
+		p.next()
+		src.container, err = p.parseContainer()
+		return src, err
+	}
+	if src.container, err = p.parseContainer(); err != nil {
+		return src, err
+	}
+	src.alias, err = p.parseAlias()
+	return src, err
+}
+
+// parseContainer parses "(<sub-query>)", or "<name>" optionally followed by property accesses (e.g. c.children or c["tags"]).
+func (p *sqlParser) parseContainer() (astExpr, error) {
+	if p.peek().isPunct("(") && p.peekN(1).isKeyword("SELECT") {
+		p.next()
+		return p.parseSubqueryRest("")
+	}
+	if tok := p.peek(); _isReservedKeyword(tok) {
+		return nil, p.errorf(tok, "expecting collection name but found %s", tok)
+	}
+	name, err := p.parseName("collection name")
+	if err != nil {
+		return nil, err
+	}
+	return p.parsePostfix(&astIdent{name: name})
+}
+
+// parseSubqueryRest parses "<sub-query>)", the opening parenthesis having been consumed.
+func (p *sqlParser) parseSubqueryRest(kind string) (astExpr, error) {
+	q, err := p.parseSelectQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return &astSubquery{kind: kind, query: q}, nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// parseExpr parses a scalar expression of Cosmos DB SQL grammar.
+func (p *sqlParser) parseExpr() (astExpr, error) {
+	cond, err := p.parseLogical(0)
+	if err != nil || !p.acceptPunct("?") {
+		return cond, err
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectColon(); err != nil {
+		return nil, err
+	}
+	els, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &astTernary{cond: cond, then: then, els: els}, nil
+}
+
+func _isOp(tok token, ops []string) bool {
+	return tok.isPunct(ops...) || tok.isKeyword(ops...)
+}
+
+// parseLogical parses operators ??, OR and AND.
+func (p *sqlParser) parseLogical(level int) (astExpr, error) {
+	if level == len(logicalOps) {
+		return p.parseNot()
+	}
+	left, err := p.parseLogical(level + 1)
+	for err == nil && _isOp(p.peek(), logicalOps[level]) {
+		op := strings.ToUpper(p.next().text)
+		var right astExpr
+		if right, err = p.parseLogical(level + 1); err == nil {
+			left = &astBinary{op: op, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *sqlParser) parseNot() (astExpr, error) {
+	if _, ok := p.acceptKeyword("NOT"); ok {
+		operand, err := p.parseNot()
+		return &astUnary{op: "NOT", operand: operand}, err
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses comparison operators, [NOT] BETWEEN, [NOT] IN and [NOT] LIKE.
+func (p *sqlParser) parseComparison() (astExpr, error) {
+	left, err := p.parseArithmetic(0)
+	for err == nil {
+		tok := p.peek()
+		if tok.isPunct(comparisonOps...) {
+			p.next()
+			var right astExpr
+			if right, err = p.parseArithmetic(0); err == nil {
+				left = &astBinary{op: tok.text, left: left, right: right}
+			}
+			continue
+		}
+		not := false
+		if tok.isKeyword("NOT") && p.peekN(1).isKeyword("BETWEEN", "IN", "LIKE") {
+			p.next()
+			not, tok = true, p.peek()
+		}
+		switch {
+		case tok.isKeyword("BETWEEN"):
+			p.next()
+			between := &astBetween{not: not, expr: left}
+			if between.low, err = p.parseArithmetic(0); err != nil {
+				return nil, err
+			}
+			if _, err = p.expectKeyword("AND"); err != nil {
+				return nil, err
+			}
+			between.high, err = p.parseArithmetic(0)
+			left = between
+		case tok.isKeyword("IN"):
+			p.next()
+			in := &astIn{not: not, expr: left}
+			if in.list, err = p.parseExprList("(", ")"); err == nil && len(in.list) == 0 {
+				err = p.errorf(tok, "IN list must not be empty")
+			}
+			left = in
+		case tok.isKeyword("LIKE"):
+			p.next()
+			like := &astLike{not: not, expr: left}
+			if like.pattern, err = p.parseArithmetic(0); err == nil {
+				if _, ok := p.acceptKeyword("ESCAPE"); ok {
+					like.escape, err = p.parseArithmetic(0)
+				}
+			}
+			left = like
+		default:
+			return left, nil
+		}
+	}
+	return left, err
+}
+
+// parseArithmetic parses bitwise, shift, additive, string concatenation and multiplicative operators.
+func (p *sqlParser) parseArithmetic(level int) (astExpr, error) {
+	if level == len(arithmeticOps) {
+		return p.parseUnary()
+	}
+	left, err := p.parseArithmetic(level + 1)
+	for err == nil && p.peek().isPunct(arithmeticOps[level]...) {
+		op := p.next().text
+		var right astExpr
+		if right, err = p.parseArithmetic(level + 1); err == nil {
+			left = &astBinary{op: op, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *sqlParser) parseUnary() (astExpr, error) {
+	if tok := p.peek(); tok.isPunct("-", "+", "~") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if lit, ok := operand.(*astLiteral); ok && tok.text == "-" {
+			if v, ok := lit.value.(float64); ok {
+				return &astLiteral{value: -v}, nil
+			}
+		}
+		return &astUnary{op: tok.text, operand: operand}, nil
+	}
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parsePostfix(primary)
+}
+
+// parsePostfix parses property accesses and indexers following an expression, e.g. .name, ["name"] or [0].
+func (p *sqlParser) parsePostfix(expr astExpr) (astExpr, error) {
+	for {
+		switch {
+		case p.acceptPunct("."):
+			tok := p.peek()
+			if tok.typ != tokIdent {
+				return nil, p.errorf(tok, "expecting property name but found %s", tok)
+			}
+			p.next()
+			expr = &astProperty{target: expr, name: tok.text}
+		case p.acceptPunct("["):
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expectPunct("]"); err != nil {
+				return nil, err
+			}
+			if lit, ok := index.(*astLiteral); ok {
+				if name, ok := lit.value.(string); ok {
+					expr = &astProperty{target: expr, name: name}
+					continue
+				}
+			}
+			expr = &astIndex{target: expr, index: index}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+// parseExprList parses a list of expressions enclosed by open and close, e.g. (1, 2, 3).
+func (p *sqlParser) parseExprList(open, close string) ([]astExpr, error) {
+	if _, err := p.expectPunct(open); err != nil {
+		return nil, err
+	}
+	list := make([]astExpr, 0)
+	if p.acceptPunct(close) {
+		return list, nil
+	}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, expr)
+		if p.acceptPunct(close) {
+			return list, nil
+		}
+		if !p.acceptPunct(",") {
+			return nil, p.errorf(p.peek(), "expecting \",\" or %q but found %s", close, p.peek())
+		}
+	}
+}
+
+func (p *sqlParser) parsePrimary() (astExpr, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case tokNumber:
+		p.next()
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %s", tok)
+		}
+		return &astLiteral{value: v}, nil
+	case tokString, tokSqString:
+		p.next()
+		v, ok := _unescapeSqlString(tok.text)
+		if !ok {
+			return nil, p.errorf(tok, "invalid string literal %s", tok)
+		}
+		return &astLiteral{value: v}, nil
+	case tokPlaceholder:
+		ph, err := p.parsePlaceholder()
+		return &astPlaceholder{index: ph.index}, err
+	case tokParam:
+		p.next()
+		return &astParam{name: tok.text[1:], index: p.paramIndex(tok)}, nil
+	case tokPunct:
+		switch {
+		case tok.isPunct("("):
+			p.next()
+			if p.peek().isKeyword("SELECT") {
+				return p.parseSubqueryRest("")
+			}
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.expectPunct(")")
+			return expr, err
+		case tok.isPunct("["):
+			items, err := p.parseExprList("[", "]")
+			return &astArray{items: items}, err
+		case tok.isPunct("{"):
+			return p.parseObject()
+		}
+	case tokIdent:
+		switch strings.ToUpper(tok.text) {
+		case "TRUE", "FALSE":
+			p.next()
+			return &astLiteral{value: strings.EqualFold(tok.text, "TRUE")}, nil
+		case "NULL":
+			p.next()
+			return &astLiteral{value: nil}, nil
+		case "UNDEFINED":
+			p.next()
+			return &astLiteral{value: astUndefined{}}, nil
+		case "EXISTS", "ARRAY":
+			if p.peekN(1).isPunct("(") && p.peekN(2).isKeyword("SELECT") {
+				p.next()
+				p.next()
+				return p.parseSubqueryRest(strings.ToUpper(tok.text))
+			}
+		case "UDF":
+			if p.peekN(1).isPunct(".") && p.peekN(2).typ == tokIdent {
+				p.next()
+				p.next()
+				name := p.next()
+				args, err := p.parseExprList("(", ")")
+				return &astCall{name: "udf." + name.text, args: args}, err
+			}
+		}
+		if _isReservedKeyword(tok) {
+			break
+		}
+		p.next()
+		if p.peek().isPunct("(") {
+			args, err := p.parseExprList("(", ")")
+			return &astCall{name: tok.text, args: args}, err
+		}
+		return &astIdent{name: tok.text}, nil
+	}
+	return nil, p.errorf(tok, "expecting expression but found %s", tok)
+}
+
+// parseObject parses an object literal, e.g. {"name": c.name, age: 1}.
+func (p *sqlParser) parseObject() (astExpr, error) {
+	p.next() // {
+	obj := &astObject{}
+	if p.acceptPunct("}") {
+		return obj, nil
+	}
+	for {
+		tok := p.next()
+		key, ok := tok.text, tok.typ == tokIdent
+		if tok.typ == tokString || tok.typ == tokSqString {
+			key, ok = _unescapeSqlString(tok.text)
+		}
+		if !ok {
+			return nil, p.errorf(tok, "expecting property name but found %s", tok)
+		}
+		if err := p.expectColon(); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		obj.keys, obj.values = append(obj.keys, key), append(obj.values, value)
+		if p.acceptPunct("}") {
+			return obj, nil
+		}
+		if !p.acceptPunct(",") {
+			return nil, p.errorf(p.peek(), "expecting \",\" or \"}\" but found %s", p.peek())
+		}
+	}
+}
+
+// expectColon expects ":". The lexer treats ":" followed by digits as a placeholder, which is split here
+// if found in the place of a colon, e.g. {"a":1} or c.a ? 1 :2.
+func (p *sqlParser) expectColon() error {
+	if tok := p.peek(); tok.typ == tokPlaceholder && tok.text[0] == ':' {
+		l := &lexer{input: p.query, pos: tok.pos + 1}
+		end := tok.pos + 1 + l.scanNumber()
+		colon := token{typ: tokPunct, text: ":", pos: tok.pos, end: tok.pos + 1, line: tok.line, col: tok.col}
+		number := token{typ: tokNumber, text: p.query[tok.pos+1 : end], pos: tok.pos + 1, end: end, line: tok.line, col: tok.col + 1}
+		next := p.i + 1
+		for p.tokens[next].typ != tokEOF && p.tokens[next].end <= end {
+			next++
+		}
+		p.tokens = append(p.tokens[:p.i], append([]token{colon, number}, p.tokens[next:]...)...)
+	}
+	_, err := p.expectPunct(":")
+	return err
+}
+
+// _unescapeSqlString returns the value of a (single or double-quoted) string literal.
+func _unescapeSqlString(text string) (string, bool) {
+	quote, body := text[0], text[1:len(text)-1]
+	sb := strings.Builder{}
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == quote && quote == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			sb.WriteByte('\'')
+			i++
+		case c != '\\':
+			sb.WriteByte(c)
+		case i+1 >= len(body):
+			return "", false
+		default:
+			i++
+			switch body[i] {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if i+4 >= len(body) {
+					return "", false
+				}
+				r, err := strconv.ParseUint(body[i+1:i+5], 16, 32)
+				if err != nil {
+					return "", false
+				}
+				var buf [utf8.UTFMax]byte
+				sb.Write(buf[:utf8.EncodeRune(buf[:], rune(r))])
+				i += 4
+			default:
+				sb.WriteByte(body[i])
+			}
+		}
+	}
+	return sb.String(), true
+}
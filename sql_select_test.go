@@ -0,0 +1,264 @@
+package gocosmos
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func _parseSelectQueryForTest(query string) (*astSelectQuery, error) {
+	tokens, err := _tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{query: query, tokens: tokens}
+	q, err := p.parseSelectQuery()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	return q, nil
+}
+
+func Test_parseSelectQuery(t *testing.T) {
+	testName := "Test_parseSelectQuery"
+	c := &astIdent{name: "c"}
+	testData := []struct {
+		name     string
+		sql      string
+		expected *astSelectQuery
+	}{
+		{
+			name:     "select_star",
+			sql:      "SELECT * FROM c",
+			expected: &astSelectQuery{from: []astFromSource{{container: c}}},
+		},
+		{
+			name: "distinct_top_value",
+			sql:  "SELECT DISTINCT TOP 5 VALUE c.name FROM root AS c",
+			expected: &astSelectQuery{distinct: true, top: &astLiteral{value: 5.0}, isValue: true,
+				projections: []astProjection{{expr: &astProperty{target: c, name: "name"}}},
+				from:        []astFromSource{{container: &astIdent{name: "root"}, alias: "c"}}},
+		},
+		{
+			name: "join_where_order_offset",
+			sql:  `SELECT c.id, t AS tag FROM c JOIN t IN c["tags"] WHERE c.a >= -1 AND NOT (t = 'x' OR c.b BETWEEN 1 AND :2) ORDER BY c.id DESC, t OFFSET @3 LIMIT 10`,
+			expected: &astSelectQuery{
+				projections: []astProjection{{expr: &astProperty{target: c, name: "id"}}, {expr: &astIdent{name: "t"}, alias: "tag"}},
+				from:        []astFromSource{{container: c}, {container: &astProperty{target: c, name: "tags"}, alias: "t", iteration: true}},
+				where: &astBinary{op: "AND",
+					left: &astBinary{op: ">=", left: &astProperty{target: c, name: "a"}, right: &astLiteral{value: -1.0}},
+					right: &astUnary{op: "NOT", operand: &astBinary{op: "OR",
+						left:  &astBinary{op: "=", left: &astIdent{name: "t"}, right: &astLiteral{value: "x"}},
+						right: &astBetween{expr: &astProperty{target: c, name: "b"}, low: &astLiteral{value: 1.0}, high: &astPlaceholder{index: 2}}}}},
+				orderBy: []astOrderItem{{expr: &astProperty{target: c, name: "id"}, desc: true}, {expr: &astIdent{name: "t"}}},
+				offset:  &astPlaceholder{index: 3}, limit: &astLiteral{value: 10.0},
+			},
+		},
+		{
+			name: "group_by_functions",
+			sql:  "SELECT c.cat, COUNT(1) AS n, udf.score(c) FROM c WHERE ARRAY_CONTAINS(@tags, c.tag) GROUP BY c.cat",
+			expected: &astSelectQuery{
+				projections: []astProjection{{expr: &astProperty{target: c, name: "cat"}},
+					{expr: &astCall{name: "COUNT", args: []astExpr{&astLiteral{value: 1.0}}}, alias: "n"},
+					{expr: &astCall{name: "udf.score", args: []astExpr{c}}}},
+				from:    []astFromSource{{container: c}},
+				where:   &astCall{name: "ARRAY_CONTAINS", args: []astExpr{&astParam{name: "tags", index: 1}, &astProperty{target: c, name: "tag"}}},
+				groupBy: []astExpr{&astProperty{target: c, name: "cat"}},
+			},
+		},
+		{
+			name: "object_array_ternary",
+			sql:  `SELECT VALUE {"a": :1, "b": [c.x, null], "c": c.y > 0 ? "pos" : "neg"} FROM c`,
+			expected: &astSelectQuery{isValue: true,
+				projections: []astProjection{{expr: &astObject{keys: []string{"a", "b", "c"}, values: []astExpr{
+					&astPlaceholder{index: 1},
+					&astArray{items: []astExpr{&astProperty{target: c, name: "x"}, &astLiteral{value: nil}}},
+					&astTernary{cond: &astBinary{op: ">", left: &astProperty{target: c, name: "y"}, right: &astLiteral{value: 0.0}},
+						then: &astLiteral{value: "pos"}, els: &astLiteral{value: "neg"}},
+				}}}},
+				from: []astFromSource{{container: c}},
+			},
+		},
+		{
+			name: "subqueries",
+			sql:  "SELECT VALUE COUNT(1) FROM (SELECT * FROM c) AS s WHERE EXISTS (SELECT VALUE t FROM t IN s.tags WHERE t IN ('a', 'b'))",
+			expected: &astSelectQuery{isValue: true,
+				projections: []astProjection{{expr: &astCall{name: "COUNT", args: []astExpr{&astLiteral{value: 1.0}}}}},
+				from:        []astFromSource{{container: &astSubquery{query: &astSelectQuery{from: []astFromSource{{container: c}}}}, alias: "s"}},
+				where: &astSubquery{kind: "EXISTS", query: &astSelectQuery{isValue: true,
+					projections: []astProjection{{expr: &astIdent{name: "t"}}},
+					from:        []astFromSource{{container: &astProperty{target: &astIdent{name: "s"}, name: "tags"}, alias: "t", iteration: true}},
+					where:       &astIn{expr: &astIdent{name: "t"}, list: []astExpr{&astLiteral{value: "a"}, &astLiteral{value: "b"}}}}},
+			},
+		},
+		{
+			name: "quoted_alias",
+			sql:  "SELECT c.cat AS 'Category' FROM c",
+			expected: &astSelectQuery{projections: []astProjection{{expr: &astProperty{target: c, name: "cat"}, alias: "'Category'"}},
+				from: []astFromSource{{container: c}}},
+		},
+		{
+			name: "no_from",
+			sql:  "SELECT VALUE 1 + 2 * 3",
+			expected: &astSelectQuery{isValue: true, projections: []astProjection{{expr: &astBinary{op: "+", left: &astLiteral{value: 1.0},
+				right: &astBinary{op: "*", left: &astLiteral{value: 2.0}, right: &astLiteral{value: 3.0}}}}}},
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			q, err := _parseSelectQueryForTest(testCase.sql)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if !reflect.DeepEqual(q, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, q)
+			}
+		})
+	}
+}
+
+func Test_parseSelectQuery_syntaxError(t *testing.T) {
+	testName := "Test_parseSelectQuery_syntaxError"
+	testData := []struct {
+		name         string
+		sql          string
+		line, column int
+	}{
+		{name: "missing_projection", sql: "SELECT FROM c", line: 1, column: 8},
+		{name: "reserved_collection", sql: "SELECT * FROM WHERE", line: 1, column: 15},
+		{name: "dangling_operator", sql: "SELECT * FROM c\nWHERE c.a =", line: 2, column: 12},
+		{name: "unbalanced_parentheses", sql: "SELECT * FROM c WHERE (c.a = 1", line: 1, column: 31},
+		{name: "offset_without_limit", sql: "SELECT * FROM c OFFSET 1", line: 1, column: 25},
+		{name: "negative_top", sql: "SELECT TOP -1 * FROM c", line: 1, column: 12},
+		{name: "missing_by", sql: "SELECT * FROM c ORDER c.id", line: 1, column: 23},
+		{name: "limit_without_offset", sql: "SELECT * FROM c LIMIT 1", line: 1, column: 17},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := _parseSelectQueryForTest(testCase.sql)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("%s failed: expected SyntaxError but received %#v", testName+"/"+testCase.name, err)
+			}
+			if syntaxErr.Line != testCase.line || syntaxErr.Column != testCase.column {
+				t.Fatalf("%s failed: expected error at line %d, column %d but received %s", testName+"/"+testCase.name, testCase.line, testCase.column, syntaxErr)
+			}
+		})
+	}
+}
+
+func Test_astSelectQuery_collection(t *testing.T) {
+	testName := "Test_astSelectQuery_collection"
+	testData := []struct {
+		sql, coll, alias string
+	}{
+		{sql: "SELECT * FROM users", coll: "users", alias: "users"},
+		{sql: "SELECT * FROM users u", coll: "users", alias: "u"},
+		{sql: "SELECT * FROM my-users AS u JOIN t IN u.tags", coll: "my-users", alias: "u"},
+		{sql: "SELECT * FROM users.children c", coll: "users", alias: ""},
+		{sql: "SELECT * FROM t IN users.tags", coll: "users", alias: ""},
+		{sql: "SELECT * FROM (SELECT * FROM users u) AS x", coll: "users", alias: ""},
+		{sql: "SELECT VALUE 1", coll: "", alias: ""},
+	}
+	for _, testCase := range testData {
+		q, err := _parseSelectQueryForTest(testCase.sql)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if coll, alias := q.collection(); coll != testCase.coll || alias != testCase.alias {
+			t.Fatalf("%s failed for %q: expected {%s %s} but received {%s %s}", testName, testCase.sql, testCase.coll, testCase.alias, coll, alias)
+		}
+	}
+}
+
+func Test_astSelectQuery_pkFilters(t *testing.T) {
+	testName := "Test_astSelectQuery_pkFilters"
+	testData := []struct {
+		sql      string
+		expected map[string]interface{}
+	}{
+		{sql: "SELECT * FROM c WHERE c.id='1' AND c.user.name=:2", expected: map[string]interface{}{"/id": "1", "/user/name": placeholder{2}}},
+		{sql: `SELECT * FROM c WHERE 3=c["grade"] AND c.a>1`, expected: map[string]interface{}{"/grade": 3.0}},
+		{sql: "SELECT * FROM c WHERE c.id='1' AND c.id='2'", expected: nil},
+		{sql: "SELECT * FROM c WHERE c.id='1' OR c.a=1", expected: nil},
+		{sql: "SELECT * FROM c WHERE x.id='1'", expected: nil},
+		{sql: "SELECT * FROM c WHERE c.a=undefined", expected: nil},
+		{sql: "SELECT * FROM c", expected: nil},
+	}
+	for _, testCase := range testData {
+		q, err := _parseSelectQueryForTest(testCase.sql)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if filters := q.pkFilters("c"); !reflect.DeepEqual(filters, testCase.expected) {
+			t.Fatalf("%s failed for %q: expected %#v but received %#v", testName, testCase.sql, testCase.expected, filters)
+		}
+	}
+}
+
+func Test_astSelectQuery_isPointRead(t *testing.T) {
+	testName := "Test_astSelectQuery_isPointRead"
+	testData := []struct {
+		sql      string
+		expected bool
+	}{
+		{sql: "SELECT * FROM c WHERE c.id=@1 AND c.pk=@2", expected: true},
+		{sql: "SELECT * FROM users u WHERE 'p' = u.app.pk AND (u.id = :1)", expected: true},
+		{sql: "SELECT * FROM c WHERE c.id=@1", expected: true},
+		{sql: "SELECT * FROM c WHERE c.id=@1 AND c.pk=@2 AND c.a>1", expected: false},
+		{sql: "SELECT * FROM c WHERE c.id=@1 AND c.id=@2", expected: false},
+		{sql: "SELECT * FROM c WHERE c.id=@1 OR c.pk=@2", expected: false},
+		{sql: "SELECT c.id FROM c WHERE c.id=@1 AND c.pk=@2", expected: false},
+		{sql: "SELECT TOP 1 * FROM c WHERE c.id=@1 AND c.pk=@2", expected: false},
+		{sql: "SELECT * FROM c JOIN t IN c.tags WHERE c.id=@1 AND c.pk=@2", expected: false},
+		{sql: "SELECT * FROM c WHERE c.id=@1 AND c.pk=@2 ORDER BY c.id", expected: false},
+		{sql: "SELECT * FROM c", expected: false},
+	}
+	for _, testCase := range testData {
+		q, err := _parseSelectQueryForTest(testCase.sql)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		_, alias := q.collection()
+		if pointRead := q.isPointRead(alias); pointRead != testCase.expected {
+			t.Fatalf("%s failed for %q: expected %v but received %v", testName, testCase.sql, testCase.expected, pointRead)
+		}
+	}
+}
+
+func Test_parseSql_selectPlaceholdersInString(t *testing.T) {
+	testName := "Test_parseSql_selectPlaceholdersInString"
+	stmt, _, err := _parseSql(`SELECT * FROM c WHERE c.a='$1' AND c.b=$2 AND c.c="@3"`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	selectStmt := stmt.(*astSelect)
+	expected := `SELECT * FROM c WHERE c.a='$1' AND c.b=@_2 AND c.c="@3"`
+	if selectStmt.query != expected || selectStmt.maxIndex != 2 || !reflect.DeepEqual(selectStmt.placeholders, map[int]string{2: "@_2"}) {
+		t.Fatalf("%s failed: received query %q, placeholders %#v, max index %d", testName, selectStmt.query, selectStmt.placeholders, selectStmt.maxIndex)
+	}
+
+	// named parameters are kept as-is, the ones inside string literals are not parameters
+	stmt, names, err := _parseSql(`SELECT * FROM c WHERE c.a='@x' AND c.b=@id AND c.c="$3" AND c.d=@name`)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName+"/named", err)
+	}
+	selectStmt = stmt.(*astSelect)
+	expected = `SELECT * FROM c WHERE c.a='@x' AND c.b=@id AND c.c="$3" AND c.d=@name`
+	if selectStmt.query != expected || selectStmt.maxIndex != 2 || !reflect.DeepEqual(selectStmt.placeholders, map[int]string{1: "@id", 2: "@name"}) {
+		t.Fatalf("%s failed: received query %q, placeholders %#v, max index %d", testName+"/named", selectStmt.query, selectStmt.placeholders, selectStmt.maxIndex)
+	}
+	if !reflect.DeepEqual(names, []string{"id", "name"}) {
+		t.Fatalf("%s failed: received names %#v", testName+"/named", names)
+	}
+
+	// named and positional parameters must not be mixed
+	_, _, err = _parseSql(`SELECT * FROM c WHERE c.a='$1' AND c.b=$2 AND c.c="@3" AND c.d=@name`)
+	if err == nil || !strings.Contains(err.Error(), "named and positional parameters must not be mixed") {
+		t.Fatalf("%s failed: expected mixed parameters error but received %#v", testName+"/mixed", err)
+	}
+}
@@ -5,33 +5,10 @@ import (
 	"fmt"
 	"io"
 	"reflect"
-	"regexp"
 	"sort"
 	"strings"
-)
-
-const (
-	field       = `([\w\-]+)`
-	ifNotExists = `(\s+IF\s+NOT\s+EXISTS)?`
-	ifExists    = `(\s+IF\s+EXISTS)?`
-	with        = `(\s+WITH\s+.*)?`
-)
 
-var (
-	reCreateDb = regexp.MustCompile(`(?is)^CREATE\s+DATABASE` + ifNotExists + `\s+` + field + with + `$`)
-	reAlterDb  = regexp.MustCompile(`(?is)^ALTER\s+DATABASE` + `\s+` + field + with + `$`)
-	reDropDb   = regexp.MustCompile(`(?is)^DROP\s+DATABASE` + ifExists + `\s+` + field + `$`)
-	reListDbs  = regexp.MustCompile(`(?is)^LIST\s+DATABASES?$`)
-
-	reCreateColl = regexp.MustCompile(`(?is)^CREATE\s+(COLLECTION|TABLE)` + ifNotExists + `\s+(` + field + `\.)?` + field + with + `$`)
-	reAlterColl  = regexp.MustCompile(`(?is)^ALTER\s+(COLLECTION|TABLE)` + `\s+(` + field + `\.)?` + field + with + `$`)
-	reDropColl   = regexp.MustCompile(`(?is)^DROP\s+(COLLECTION|TABLE)` + ifExists + `\s+(` + field + `\.)?` + field + `$`)
-	reListColls  = regexp.MustCompile(`(?is)^LIST\s+(COLLECTIONS?|TABLES?)(\s+FROM\s+` + field + `)?$`)
-
-	reInsert = regexp.MustCompile(`(?is)^(INSERT|UPSERT)\s+INTO\s+(` + field + `\.)?` + field + `\s*\(([^)]*?)\)\s*VALUES\s*\(([^)]*?)\)` + with + `$`)
-	reSelect = regexp.MustCompile(`(?is)^SELECT\s+(CROSS\s+PARTITION\s+)?.*?\s+FROM\s+` + field + `.*?` + with + `$`)
-	reUpdate = regexp.MustCompile(`(?is)^UPDATE\s+(` + field + `\.)?` + field + `\s+SET\s+(.*)\s+WHERE\s+(.*?)` + with + `$`)
-	reDelete = regexp.MustCompile(`(?is)^DELETE\s+FROM\s+(` + field + `\.)?` + field + `\s+WHERE\s+(.*?)` + with + `$`)
+	"github.com/btnguyen2k/consu/g18"
 )
 
 // parseQueryWithDefaultDb parses the given query and returns a Stmt.
@@ -39,168 +16,274 @@ var (
 // @Available since v1.0.0
 func parseQueryWithDefaultDb(c *Conn, defaultDb, query string) (driver.Stmt, error) {
 	query = strings.TrimSpace(query)
-	if re := reCreateDb; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	node, paramNames, err := _parseSql(query)
+	if err != nil {
+		return nil, err
+	}
+	dbOrDefault := func(db string) string {
+		if db == "" {
+			return defaultDb
+		}
+		return db
+	}
+
+	switch n := node.(type) {
+	case *astCreateDatabase:
 		stmt := &StmtCreateDatabase{
 			Stmt:        &Stmt{query: query, conn: c, numInputs: 0},
-			dbName:      strings.TrimSpace(groups[0][2]),
-			ifNotExists: strings.TrimSpace(groups[0][1]) != "",
+			dbName:      n.db,
+			ifNotExists: n.ifNotExists,
 		}
-		if err := stmt.parse(strings.TrimSpace(groups[0][3])); err != nil {
+		if err := stmt.parse(n.withOpts()); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reAlterDb; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astAlterDatabase:
 		stmt := &StmtAlterDatabase{
 			Stmt:   &Stmt{query: query, conn: c, numInputs: 0},
-			dbName: strings.TrimSpace(groups[0][1]),
+			dbName: n.db,
 		}
-		if err := stmt.parse(strings.TrimSpace(groups[0][2])); err != nil {
+		if err := stmt.parse(n.withOpts()); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reDropDb; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astDropDatabase:
 		stmt := &StmtDropDatabase{
 			Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
-			dbName:   strings.TrimSpace(groups[0][2]),
-			ifExists: strings.TrimSpace(groups[0][1]) != "",
+			dbName:   n.db,
+			ifExists: n.ifExists,
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reListDbs; re.MatchString(query) {
+	case *astListDatabases:
 		stmt := &StmtListDatabases{
 			Stmt: &Stmt{query: query, conn: c, numInputs: 0},
 		}
 		return stmt, stmt.validate()
-	}
 
-	if re := reCreateColl; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astCreateCollection:
 		stmt := &StmtCreateCollection{
 			Stmt:        &Stmt{query: query, conn: c, numInputs: 0},
-			ifNotExists: strings.TrimSpace(groups[0][2]) != "",
-			dbName:      strings.TrimSpace(groups[0][4]),
-			collName:    strings.TrimSpace(groups[0][5]),
-		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+			ifNotExists: n.ifNotExists,
+			dbName:      dbOrDefault(n.db),
+			collName:    n.coll,
 		}
-		if err := stmt.parse(strings.TrimSpace(groups[0][6])); err != nil {
+		if err := stmt.parse(n.withOpts()); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reAlterColl; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astAlterCollection:
 		stmt := &StmtAlterCollection{
 			Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
-			dbName:   strings.TrimSpace(groups[0][3]),
-			collName: strings.TrimSpace(groups[0][4]),
-		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+			dbName:   dbOrDefault(n.db),
+			collName: n.coll,
 		}
-		if err := stmt.parse(strings.TrimSpace(groups[0][5])); err != nil {
+		if err := stmt.parse(n.withOpts()); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reDropColl; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astDropCollection:
 		stmt := &StmtDropCollection{
 			Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
-			dbName:   strings.TrimSpace(groups[0][4]),
-			collName: strings.TrimSpace(groups[0][5]),
-			ifExists: strings.TrimSpace(groups[0][2]) != "",
-		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+			dbName:   dbOrDefault(n.db),
+			collName: n.coll,
+			ifExists: n.ifExists,
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reListColls; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astListCollections:
 		stmt := &StmtListCollections{
 			Stmt:   &Stmt{query: query, conn: c, numInputs: 0},
-			dbName: strings.TrimSpace(groups[0][3]),
+			dbName: dbOrDefault(n.db),
+		}
+		return stmt, stmt.validate()
+
+	case *astDescribeCollection:
+		stmt := &StmtDescribeCollection{
+			Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
+			dbName:   dbOrDefault(n.db),
+			collName: n.coll,
 		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+		return stmt, stmt.validate()
+	case *astShowThroughput:
+		stmt := &StmtShowThroughput{
+			Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
+			dbName:   dbOrDefault(n.db),
+			collName: n.coll,
 		}
 		return stmt, stmt.validate()
-	}
 
-	if re := reInsert; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astCreateIndex:
+		stmt := &StmtCreateIndex{
+			Stmt:        &Stmt{query: query, conn: c, numInputs: 0},
+			indexKind:   n.kind,
+			dbName:      dbOrDefault(n.db),
+			collName:    n.coll,
+			paths:       n.paths,
+			orders:      n.orders,
+			ifNotExists: n.ifNotExists,
+		}
+		if err := stmt.parse(n.withOpts()); err != nil {
+			return nil, err
+		}
+		return stmt, stmt.validate()
+	case *astDropIndex:
+		stmt := &StmtDropIndex{
+			Stmt:      &Stmt{query: query, conn: c, numInputs: 0},
+			indexKind: n.kind,
+			dbName:    dbOrDefault(n.db),
+			collName:  n.coll,
+			paths:     n.paths,
+			orders:    n.orders,
+			ifExists:  n.ifExists,
+		}
+		return stmt, stmt.validate()
+
+	case *astCreateScript:
+		stmt := &StmtCreateScript{
+			Stmt:       &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+			orReplace:  n.orReplace,
+			scriptType: n.scriptType,
+			dbName:     dbOrDefault(n.db),
+			collName:   n.coll,
+			scriptName: n.script,
+		}
+		if err := stmt.parse(n.body, n.withOpts()); err != nil {
+			return nil, err
+		}
+		return stmt, stmt.validate()
+	case *astDropScript:
+		stmt := &StmtDropScript{
+			Stmt:       &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+			scriptType: n.scriptType,
+			ifExists:   n.ifExists,
+			dbName:     dbOrDefault(n.db),
+			collName:   n.coll,
+			scriptName: n.script,
+		}
+		return stmt, stmt.validate()
+	case *astListScripts:
+		stmt := &StmtListScripts{
+			Stmt:       &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+			scriptType: n.scriptType,
+			dbName:     dbOrDefault(n.db),
+			collName:   n.coll,
+		}
+		return stmt, stmt.validate()
+
+	case *astInsertValue:
+		stmt := &StmtInsertValue{
+			StmtCRUD: &StmtCRUD{
+				Stmt:      &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+				dbName:    dbOrDefault(n.db),
+				collName:  n.coll,
+				returning: n.returning,
+			},
+			isUpsert: n.isUpsert,
+		}
+		if err := stmt.parse(n); err != nil {
+			return nil, err
+		}
+		return stmt, stmt.validate()
+	case *astInsertSelect:
+		if n.returning != nil {
+			return nil, fmt.Errorf("RETURNING is not supported for this statement: %s", query)
+		}
+		stmt := &StmtInsertSelect{
+			StmtCRUD: &StmtCRUD{
+				Stmt:     &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+				dbName:   dbOrDefault(n.db),
+				collName: n.coll,
+			},
+			isUpsert: n.isUpsert,
+			source:   _buildSelect(c, dbOrDefault(n.db), n.source, paramNames),
+		}
+		if err := stmt.parse(n.withOpts()); err != nil {
+			return nil, err
+		}
+		return stmt, stmt.validate()
+	case *astInsert:
 		stmt := &StmtInsert{
 			StmtCRUD: &StmtCRUD{
-				Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
-				dbName:   strings.TrimSpace(groups[0][3]),
-				collName: strings.TrimSpace(groups[0][4]),
+				Stmt:      &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+				dbName:    dbOrDefault(n.db),
+				collName:  n.coll,
+				returning: n.returning,
 			},
-			isUpsert:  strings.ToUpper(strings.TrimSpace(groups[0][1])) == "UPSERT",
-			fieldsStr: strings.TrimSpace(groups[0][5]),
-			valuesStr: strings.TrimSpace(groups[0][6]),
+			isUpsert:  n.isUpsert,
+			fieldsStr: n.fieldsText,
+			valuesStr: n.valuesText,
 		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+		if err := stmt.parse(n); err != nil {
+			return nil, err
+		}
+		return stmt, stmt.validate()
+	case *astSelect:
+		if n.returning != nil {
+			return nil, fmt.Errorf("RETURNING is not supported for this statement: %s", query)
 		}
-		if err := stmt.parse(groups[0][7]); err != nil {
+		stmt := _buildSelect(c, defaultDb, n, paramNames)
+		stmt.query = query
+		if err := stmt.parse(n.withOpts()); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reSelect; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
-		stmt := &StmtSelect{
-			Stmt:             &Stmt{query: query, conn: c, numInputs: 0},
-			isCrossPartition: strings.TrimSpace(groups[0][1]) != "",
-			collName:         strings.TrimSpace(groups[0][2]),
-			dbName:           defaultDb,
-			selectQuery:      strings.ReplaceAll(strings.ReplaceAll(query, groups[0][1], ""), groups[0][3], ""),
-		}
-		if err := stmt.parse(groups[0][3]); err != nil {
+	case *astExplain:
+		if n.query.returning != nil {
+			return nil, fmt.Errorf("RETURNING is not supported for this statement: %s", query)
+		}
+		source := _buildSelect(c, defaultDb, n.query, paramNames)
+		if err := source.parse(n.withOpts()); err != nil {
 			return nil, err
 		}
+		stmt := &StmtExplain{
+			Stmt:   &Stmt{query: query, conn: c, numInputs: source.numInputs, paramNames: paramNames},
+			source: source,
+		}
 		return stmt, stmt.validate()
-	}
-	if re := reUpdate; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astUpdate:
 		stmt := &StmtUpdate{
 			StmtCRUD: &StmtCRUD{
-				Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
-				dbName:   strings.TrimSpace(groups[0][2]),
-				collName: strings.TrimSpace(groups[0][3]),
+				Stmt:      &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+				dbName:    dbOrDefault(n.db),
+				collName:  n.coll,
+				returning: n.returning,
 			},
-			updateStr: strings.TrimSpace(groups[0][4]),
-			whereStr:  strings.TrimSpace(groups[0][5]),
-		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+			updateStr: n.updateText,
+			whereStr:  n.where.text,
 		}
-		if err := stmt.parse(groups[0][6]); err != nil {
+		if err := stmt.parse(n); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
-	}
-	if re := reDelete; re.MatchString(query) {
-		groups := re.FindAllStringSubmatch(query, -1)
+	case *astDelete:
 		stmt := &StmtDelete{
 			StmtCRUD: &StmtCRUD{
-				Stmt:     &Stmt{query: query, conn: c, numInputs: 0},
-				dbName:   strings.TrimSpace(groups[0][2]),
-				collName: strings.TrimSpace(groups[0][3]),
+				Stmt:      &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+				dbName:    dbOrDefault(n.db),
+				collName:  n.coll,
+				returning: n.returning,
 			},
-			whereStr: strings.TrimSpace(groups[0][4]),
+			whereStr: n.where.text,
 		}
-		if stmt.dbName == "" {
-			stmt.dbName = defaultDb
+		if err := stmt.parse(n); err != nil {
+			return nil, err
+		}
+		return stmt, stmt.validate()
+	case *astDeleteAll:
+		if n.returning != nil {
+			return nil, fmt.Errorf("RETURNING is not supported for this statement: %s", query)
 		}
-		if err := stmt.parse(groups[0][5]); err != nil {
+		if len(n.with) > 0 {
+			return nil, fmt.Errorf("WITH is not supported for this statement: %s", query)
+		}
+		stmt := &StmtDeleteAll{
+			StmtCRUD: &StmtCRUD{
+				Stmt:     &Stmt{query: query, conn: c, numInputs: 0, paramNames: paramNames},
+				dbName:   dbOrDefault(n.db),
+				collName: n.coll,
+			},
+			whereStr: n.where.text,
+		}
+		if err := stmt.parse(n.where); err != nil {
 			return nil, err
 		}
 		return stmt, stmt.validate()
@@ -209,19 +292,37 @@ func parseQueryWithDefaultDb(c *Conn, defaultDb, query string) (driver.Stmt, err
 	return nil, fmt.Errorf("invalid query: %s", query)
 }
 
+// _buildSelect builds a StmtSelect from the parsed SELECT query, WITH options are not applied yet.
+//
+// @Available since v1.2.0
+func _buildSelect(c *Conn, dbName string, n *astSelect, paramNames []string) *StmtSelect {
+	return &StmtSelect{
+		Stmt:             &Stmt{query: n.query, conn: c, numInputs: n.maxIndex, paramNames: paramNames},
+		isCrossPartition: n.isCrossPartition,
+		dbName:           dbName,
+		collName:         n.coll,
+		selectQuery:      n.query,
+		placeholders:     n.placeholders,
+		alias:            n.alias,
+		pkFilters:        n.body.pkFilters(n.alias),
+		pointRead:        n.body.isPointRead(n.alias),
+	}
+}
+
 // Stmt is Azure Cosmos DB abstract implementation of driver.Stmt.
 type Stmt struct {
-	query     string // the SQL query
-	conn      *Conn  // the connection that this prepared statement is bound to
-	numInputs int    // number of placeholder parameters, INCLUDING PK values!
-	withOpts  map[string]string
+	query      string   // the SQL query
+	conn       *Conn    // the connection that this prepared statement is bound to
+	numInputs  int      // number of placeholder parameters, INCLUDING PK values!
+	paramNames []string // (since v1.2.0) names of the named parameters, the i-th name is bound to placeholder index i+1
+	withOpts   map[string]string
 }
 
 // String implements interface fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *Stmt) String() string {
-	return fmt.Sprintf(`Stmt{query: %q, num_inputs: %d, with_opts: %v}`, s.query, s.numInputs, s.withOpts)
+	return fmt.Sprintf(`Stmt{query: %q, num_inputs: %d, param_names: %v, with_opts: %v}`, s.query, s.numInputs, s.paramNames, s.withOpts)
 }
 
 func (s *Stmt) onlyOneWithOption(errmsg string, optKeys ...string) error {
@@ -236,23 +337,63 @@ func (s *Stmt) onlyOneWithOption(errmsg string, optKeys ...string) error {
 	return nil
 }
 
-var reWithOpts = regexp.MustCompile(`(?is)^(\s+|\s*,\s+|\s+,\s*)WITH\s+` + field + `(\s*=\s*([\w/\.\*,;:'"-]+))?`)
-
-// parseWithOpts parses "WITH..." clause and store result in withOpts map.
+// parseWithOpts stores the options of the "WITH..." clause in withOpts map.
 // This function returns no error. Sub-implementations may override this behavior.
-func (s *Stmt) parseWithOpts(withOptsStr string) error {
-	withOptsStr = " " + withOptsStr
+//
+// Since v1.2.0, options can be separated by commas without repeating the WITH keyword, e.g. "WITH k1=v1, k2=v2".
+func (s *Stmt) parseWithOpts(opts []astWithOpt) error {
 	s.withOpts = make(map[string]string)
-	for {
-		matches := reWithOpts.FindStringSubmatch(withOptsStr)
-		if matches == nil {
+	for _, opt := range opts {
+		s.withOpts[opt.key] = opt.value
+	}
+	return nil
+}
+
+// bindArgs arranges the supplied arguments in placeholder order.
+//
+// If the statement uses named parameters, each of them is bound to the argument of the same name (e.g. sql.Named("name", value) for @name).
+// Named arguments the statement does not use are rejected, so that a misspelled name is not silently taken as another value;
+// values not referred to by the statement (e.g. partition key values) must be supplied as positional arguments after the
+// named ones, and follow the named parameters in the order they are supplied.
+// Otherwise, arguments are bound by position and must not be named.
+//
+// @Available since v1.2.0
+func (s *Stmt) bindArgs(args []driver.NamedValue) ([]driver.NamedValue, error) {
+	if len(s.paramNames) == 0 {
+		for _, arg := range args {
+			if arg.Name != "" {
+				return nil, fmt.Errorf("named and positional parameters must not be mixed, the statement does not use named parameter @%s", arg.Name)
+			}
+		}
+		return args, nil
+	}
+	byName := make(map[string]driver.NamedValue, len(args))
+	numNamed := 0
+	for _, arg := range args {
+		if arg.Name == "" {
 			break
 		}
-		k := strings.TrimSpace(strings.ToUpper(matches[2]))
-		s.withOpts[k] = strings.TrimSuffix(strings.TrimSpace(matches[4]), ",")
-		withOptsStr = withOptsStr[len(matches[0]):]
+		if g18.FindInSlice(arg.Name, s.paramNames) < 0 {
+			return nil, fmt.Errorf("unknown named parameter @%s, the statement does not use it", arg.Name)
+		}
+		byName[arg.Name] = arg
+		numNamed++
 	}
-	return nil
+	trailing := args[numNamed:]
+	for _, arg := range trailing {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("named and positional parameters must not be mixed, named value @%s follows positional argument #%d", arg.Name, trailing[0].Ordinal)
+		}
+	}
+	result := make([]driver.NamedValue, 0, len(args))
+	for _, name := range s.paramNames {
+		arg, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for named parameter @%s", name)
+		}
+		result = append(result, arg)
+	}
+	return append(result, trailing...), nil
 }
 
 // Close implements driver.Stmt/Close.
@@ -318,6 +459,7 @@ type ResultNoResultSet struct {
 	affectedRows        int64
 	supportLastInsertId bool
 	lastInsertId        string // holds the "_rid" if the operation returns it
+	unknownAffectedRows bool   // (since v1.2.0) true if the number of affected rows is not known, e.g. the operation is carried out by the server in the background
 }
 
 // LastInsertId implements driver.Result/LastInsertId.
@@ -332,7 +474,12 @@ func (r *ResultNoResultSet) LastInsertId() (int64, error) {
 }
 
 // RowsAffected implements driver.Result/RowsAffected.
+//
+// (since v1.2.0) ErrOperationNotSupported is returned if the number of affected rows is not known.
 func (r *ResultNoResultSet) RowsAffected() (int64, error) {
+	if r.err == nil && r.unknownAffectedRows {
+		return 0, ErrOperationNotSupported
+	}
 	return r.affectedRows, r.err
 }
 
@@ -3,6 +3,7 @@ package gocosmos
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -18,6 +19,9 @@ import (
 //	<WITH PK=partitionKey>
 //	[[,] WITH RU|MAXRU=ru]
 //	[[,] WITH UK=/path1:/path2,/path3;/path4]
+//	[[,] WITH CONFLICT_POLICY=LWW[:/path]|CUSTOM[:sproc-name]]
+//	[[,] WITH INDEXING='<indexing-policy-json>']
+//	[[,] WITH TTL=<seconds>|-1|OFF]
 //
 // - ru: an integer specifying CosmosDB's collection throughput expressed in RU/s. Supply either RU or MAXRU, not both!
 //
@@ -26,26 +30,40 @@ import (
 // - If "IF NOT EXISTS" is specified, Exec will silently swallow the error "409 Conflict".
 //
 // - Use UK to define unique keys. Each unique key consists a list of paths separated by comma (,). Unique keys are separated by colons (:) or semi-colons (;).
+//
+// - (since v1.2.0) Use CONFLICT_POLICY to define the conflict resolution policy for multi-region writes accounts:
+// LWW (last-writer-wins, conflicts are resolved based on the value at /path, default path is /_ts) or
+// CUSTOM (conflicts are resolved by the stored procedure sproc-name; if omitted, conflicts are written to the conflicts feed).
+//
+// - (since v1.2.0) Use INDEXING to supply the indexing policy as a JSON document enclosed in single quotes,
+// for example: WITH INDEXING='{"indexingMode":"consistent","includedPaths":[{"path":"/*"}],"excludedPaths":[{"path":"/content/*"}]}'.
+// Indexes can be added to/removed from an existing collection with CREATE INDEX/DROP INDEX statements.
+//
+// - (since v1.2.0) Use TTL to specify the default time-to-live of documents in seconds; -1 means documents do not expire
+// unless they have their own "ttl" field; OFF (default) turns TTL off.
 type StmtCreateCollection struct {
 	*Stmt
-	dbName      string
-	collName    string // collection name
-	ifNotExists bool
-	ru, maxru   int
-	pk          string     // partition key
-	uk          [][]string // unique keys
+	dbName         string
+	collName       string // collection name
+	ifNotExists    bool
+	ru, maxru      int
+	pk             string                 // partition key
+	uk             [][]string             // unique keys
+	conflictPolicy map[string]interface{} // conflict resolution policy
+	indexingPolicy map[string]interface{} // (since v1.2.0) indexing policy
+	defaultTtl     *int                   // (since v1.2.0) default time-to-live of documents, nil means TTL is off
 }
 
 // String implements fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtCreateCollection) String() string {
-	return fmt.Sprintf(`StmtCreateCollection{Stmt: %s, db: %q, collection: %q, if_not_exists: %t, ru: %d, maxru: %d, pk: %q, uk: %v}`,
-		s.Stmt, s.dbName, s.collName, s.ifNotExists, s.ru, s.maxru, s.pk, s.uk)
+	return fmt.Sprintf(`StmtCreateCollection{Stmt: %s, db: %q, collection: %q, if_not_exists: %t, ru: %d, maxru: %d, pk: %q, uk: %v, conflict_policy: %v, indexing_policy: %v, ttl: %s}`,
+		s.Stmt, s.dbName, s.collName, s.ifNotExists, s.ru, s.maxru, s.pk, s.uk, s.conflictPolicy, s.indexingPolicy, _ttlToString(s.defaultTtl))
 }
 
-func (s *StmtCreateCollection) parse(withOptsStr string) error {
-	if err := s.Stmt.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtCreateCollection) parse(opts []astWithOpt) error {
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
 		return err
 	}
 
@@ -74,6 +92,24 @@ func (s *StmtCreateCollection) parse(withOptsStr string) error {
 				paths := regexp.MustCompile(`[,\s]+`).Split(token, -1)
 				s.uk = append(s.uk, paths)
 			}
+		case "CONFLICT_POLICY":
+			policy, err := s.parseConflictPolicy(v)
+			if err != nil {
+				return err
+			}
+			s.conflictPolicy = policy
+		case "INDEXING":
+			policy, err := s.parseIndexingPolicy(v)
+			if err != nil {
+				return err
+			}
+			s.indexingPolicy = policy
+		case "TTL":
+			ttl, err := _parseTtlOpt(v, true)
+			if err != nil {
+				return err
+			}
+			s.defaultTtl = ttl
 		default:
 			return fmt.Errorf("invalid query, parsing error at WITH %s=%s", k, v)
 		}
@@ -82,6 +118,44 @@ func (s *StmtCreateCollection) parse(withOptsStr string) error {
 	return nil
 }
 
+func (s *StmtCreateCollection) parseConflictPolicy(v string) (map[string]interface{}, error) {
+	tokens := strings.SplitN(v, ":", 2)
+	arg := ""
+	if len(tokens) > 1 {
+		arg = strings.TrimSpace(tokens[1])
+	}
+	switch strings.ToUpper(strings.TrimSpace(tokens[0])) {
+	case "LWW":
+		if arg == "" {
+			arg = "/_ts"
+		}
+		if !strings.HasPrefix(arg, "/") {
+			return nil, fmt.Errorf("invalid CONFLICT_POLICY value: %s", v)
+		}
+		return map[string]interface{}{"mode": "LastWriterWins", "conflictResolutionPath": arg}, nil
+	case "CUSTOM":
+		policy := map[string]interface{}{"mode": "Custom"}
+		if arg != "" {
+			policy["conflictResolutionProcedure"] = "dbs/" + s.dbName + "/colls/" + s.collName + "/sprocs/" + arg
+		}
+		return policy, nil
+	}
+	return nil, fmt.Errorf("invalid CONFLICT_POLICY value: %s", v)
+}
+
+func (s *StmtCreateCollection) parseIndexingPolicy(v string) (map[string]interface{}, error) {
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return nil, fmt.Errorf("invalid INDEXING value, expecting a single-quoted JSON string: %s", v)
+	}
+	// inside a single-quoted string, two consecutive single quotes represent one single quote
+	js := strings.ReplaceAll(v[1:len(v)-1], "''", "'")
+	var policy map[string]interface{}
+	if err := json.Unmarshal([]byte(js), &policy); err != nil || policy == nil {
+		return nil, fmt.Errorf("invalid INDEXING value: %s", v)
+	}
+	return policy, nil
+}
+
 func (s *StmtCreateCollection) validate() error {
 	if s.pk == "" {
 		return fmt.Errorf("missing PartitionKey value")
@@ -133,6 +207,9 @@ func (s *StmtCreateCollection) ExecContext(_ context.Context, args []driver.Name
 		}
 		spec.UniqueKeyPolicy = map[string]interface{}{"uniqueKeys": uniqueKeys}
 	}
+	spec.ConflictResolutionPolicy = s.conflictPolicy
+	spec.IndexingPolicy = s.indexingPolicy
+	spec.DefaultTtl = s.defaultTtl
 
 	// TODO: pass ctx to REST API client
 	restResult := s.conn.restClient.CreateCollection(spec)
@@ -150,28 +227,35 @@ func (s *StmtCreateCollection) ExecContext(_ context.Context, args []driver.Name
 //
 // Syntax:
 //
-//	ALTER COLLECTION|TABLE [<db-name>.]<collection-name> WITH RU|MAXRU=<ru>
+//	ALTER COLLECTION|TABLE [<db-name>.]<collection-name> [WITH RU|MAXRU=<ru>] [[,] WITH TTL=<seconds>|-1|OFF]
 //
 // - ru: an integer specifying CosmosDB's collection throughput expressed in RU/s. Supply either RU or MAXRU, not both!
 //
+// - (since v1.2.0) TTL changes the default time-to-live of documents, see StmtCreateCollection. Other settings of the collection are retained.
+//
+// - (since v1.2.0) If both throughput and TTL are specified, they are changed by two separate requests, throughput first.
+// The statement is not atomic: if changing the TTL fails, the new throughput remains in effect.
+//
 // Available since v0.1.1
 type StmtAlterCollection struct {
 	*Stmt
 	dbName    string
 	collName  string // collection name
 	ru, maxru int
+	hasTtl    bool // (since v1.2.0) true if the default time-to-live is to be changed
+	ttl       *int // (since v1.2.0) new default time-to-live of documents, nil means TTL is off
 }
 
 // String implements fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtAlterCollection) String() string {
-	return fmt.Sprintf(`StmtAlterCollection{Stmt: %s, db: %q, collection: %q, ru: %d, maxru: %d}`,
-		s.Stmt, s.dbName, s.collName, s.ru, s.maxru)
+	return fmt.Sprintf(`StmtAlterCollection{Stmt: %s, db: %q, collection: %q, ru: %d, maxru: %d, has_ttl: %t, ttl: %s}`,
+		s.Stmt, s.dbName, s.collName, s.ru, s.maxru, s.hasTtl, _ttlToString(s.ttl))
 }
 
-func (s *StmtAlterCollection) parse(withOptsStr string) error {
-	if err := s.Stmt.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtAlterCollection) parse(opts []astWithOpt) error {
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
 		return err
 	}
 
@@ -189,6 +273,12 @@ func (s *StmtAlterCollection) parse(withOptsStr string) error {
 				return fmt.Errorf("invalid MAXRU value: %s", v)
 			}
 			s.maxru = int(maxru)
+		case "TTL":
+			ttl, err := _parseTtlOpt(v, true)
+			if err != nil {
+				return err
+			}
+			s.hasTtl, s.ttl = true, ttl
 		default:
 			return fmt.Errorf("invalid query, parsing error at WITH %s=%s", k, v)
 		}
@@ -198,9 +288,12 @@ func (s *StmtAlterCollection) parse(withOptsStr string) error {
 }
 
 func (s *StmtAlterCollection) validate() error {
-	if (s.ru <= 0 && s.maxru <= 0) || (s.ru > 0 && s.maxru > 0) {
+	if s.ru > 0 && s.maxru > 0 {
 		return errors.New("only one of RU or MAXRU should be specified")
 	}
+	if s.ru <= 0 && s.maxru <= 0 && !s.hasTtl {
+		return errors.New("one of RU, MAXRU or TTL must be specified")
+	}
 	if s.dbName == "" || s.collName == "" {
 		return errors.New("database/collection is missing")
 	}
@@ -238,8 +331,27 @@ func (s *StmtAlterCollection) ExecContext(_ context.Context, args []driver.Named
 	}
 
 	// TODO: pass ctx to REST API client
-	restResult := s.conn.restClient.ReplaceOfferForResource(getResult.Rid, s.ru, s.maxru)
-	result := buildResultNoResultSet(&restResult.RestResponse, true, restResult.Rid, 0)
+	var result *ResultNoResultSet
+	if s.ru > 0 || s.maxru > 0 {
+		// the throughput is changed first: it is the change more likely to be rejected (e.g. the collection has no
+		// dedicated throughput), in which case the collection is left untouched
+		restResult := s.conn.restClient.ReplaceOfferForResource(getResult.Rid, s.ru, s.maxru)
+		result = buildResultNoResultSet(&restResult.RestResponse, true, restResult.Rid, 0)
+		if result.err != nil {
+			return result, result.err
+		}
+	}
+	if s.hasTtl {
+		// replacing the collection resets settings not supplied, hence the partition key, indexing policy and conflict
+		// resolution policy are retained
+		spec := CollectionSpec{DbName: s.dbName, CollName: s.collName, PartitionKeyInfo: getResult.PartitionKey,
+			IndexingPolicy: getResult.IndexingPolicy, ConflictResolutionPolicy: getResult.ConflictResolutionPolicy, DefaultTtl: s.ttl}
+		replaceResult := s.conn.restClient.ReplaceCollection(spec)
+		ttlResult := buildResultNoResultSet(&replaceResult.RestResponse, true, replaceResult.Rid, 0)
+		if result == nil || ttlResult.err != nil {
+			result = ttlResult
+		}
+	}
 	return result, result.err
 }
 
@@ -369,3 +481,217 @@ func (s *StmtListCollections) QueryContext(_ context.Context, args []driver.Name
 	}
 	return result, result.err
 }
+
+/*----------------------------------------------------------------------*/
+
+// _fetchThroughput fetches the throughput settings of a database (collInfo is nil) or a collection.
+// A collection without dedicated throughput reports the throughput shared from its database (shared is true).
+// A resource without provisioned throughput reports an empty offer.
+//
+// @Available since v1.2.0
+func _fetchThroughput(client *RestClient, dbName string, collInfo *CollInfo) (offer OfferInfo, shared bool, err error) {
+	if collInfo != nil {
+		getOffer := client.GetOfferForResource(collInfo.Rid)
+		if err = getOffer.Error(); err == nil || getOffer.StatusCode != 404 {
+			return getOffer.OfferInfo, false, normalizeError(getOffer.StatusCode, 0, err)
+		}
+		shared = true
+	}
+	getDb := client.GetDatabase(dbName)
+	if err = getDb.Error(); err != nil {
+		return OfferInfo{}, false, normalizeError(getDb.StatusCode, 0, err)
+	}
+	getOffer := client.GetOfferForResource(getDb.Rid)
+	if err = getOffer.Error(); err != nil {
+		if getOffer.StatusCode == 404 {
+			// neither the collection nor the database has provisioned throughput (e.g. serverless account)
+			return OfferInfo{}, false, nil
+		}
+		return OfferInfo{}, false, normalizeError(getOffer.StatusCode, 0, err)
+	}
+	return getOffer.OfferInfo, shared, nil
+}
+
+// _fetchCollInfo fetches a collection's info and the number of its partition key ranges.
+//
+// @Available since v1.2.0
+func _fetchCollInfo(client *RestClient, dbName, collName string) (*CollInfo, int, error) {
+	getColl := client.GetCollection(dbName, collName)
+	if err := getColl.Error(); err != nil {
+		return nil, 0, normalizeError(getColl.StatusCode, 0, err)
+	}
+	pkranges := client.GetPkranges(dbName, collName)
+	if err := pkranges.Error(); err != nil {
+		return nil, 0, normalizeError(pkranges.StatusCode, 0, err)
+	}
+	return &getColl.CollInfo, len(pkranges.Pkranges), nil
+}
+
+// StmtDescribeCollection implements "DESCRIBE" statement.
+//
+// Syntax:
+//
+//	DESCRIBE|DESC [COLLECTION|TABLE] [<db-name>.]<collection-name>
+//
+// The result set has exactly one row. Columns holding the collection's properties are named as in the REST API, like
+// LIST COLLECTIONS: id, partitionKey, indexingPolicy, uniqueKeyPolicy, conflictResolutionPolicy, geospatialConfig,
+// defaultTtl (nil if TTL is off), _rid, _ts, _self and _etag. Columns computed by the driver are named as in
+// SHOW THROUGHPUT: ru, maxru (0 if autopilot is not enabled), shared_throughput (true if the throughput is shared from
+// the database) and num_pkranges.
+//
+// @Available since v1.2.0
+type StmtDescribeCollection struct {
+	*Stmt
+	dbName   string
+	collName string
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtDescribeCollection) String() string {
+	return fmt.Sprintf(`StmtDescribeCollection{Stmt: %s, db: %q, collection: %q}`, s.Stmt, s.dbName, s.collName)
+}
+
+func (s *StmtDescribeCollection) validate() error {
+	if s.dbName == "" || s.collName == "" {
+		return errors.New("database/collection is missing")
+	}
+	return nil
+}
+
+// Exec implements driver.Stmt/Exec.
+// This function is not implemented, use Query instead.
+func (s *StmtDescribeCollection) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, ErrExecNotSupported
+}
+
+// Query implements driver.Stmt/Query.
+func (s *StmtDescribeCollection) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+func (s *StmtDescribeCollection) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected 0 input value, got %d", len(args))
+	}
+
+	// TODO: pass ctx to REST API client
+	collInfo, numPkranges, err := _fetchCollInfo(s.conn.restClient, s.dbName, s.collName)
+	if err != nil {
+		return nil, err
+	}
+	offer, shared, err := _fetchThroughput(s.conn.restClient, s.dbName, collInfo)
+	if err != nil {
+		return nil, err
+	}
+	row := DocInfo{
+		"id":                       collInfo.Id,
+		"partitionKey":             collInfo.PartitionKey,
+		"indexingPolicy":           collInfo.IndexingPolicy,
+		"uniqueKeyPolicy":          collInfo.UniqueKeyPolicy,
+		"conflictResolutionPolicy": collInfo.ConflictResolutionPolicy,
+		"geospatialConfig":         collInfo.GeospatialConfig,
+		"defaultTtl":               nil,
+		"_rid":                     collInfo.Rid,
+		"_ts":                      collInfo.Ts,
+		"_self":                    collInfo.Self,
+		"_etag":                    collInfo.Etag,
+		"ru":                       offer.OfferThroughput(),
+		"maxru":                    offer.AutopilotMaxThroughput(),
+		"shared_throughput":        shared,
+		"num_pkranges":             numPkranges,
+	}
+	if collInfo.DefaultTtl != nil {
+		row["defaultTtl"] = *collInfo.DefaultTtl
+	}
+	result := &ResultResultSet{
+		columnList: []string{"id", "partitionKey", "indexingPolicy", "uniqueKeyPolicy", "conflictResolutionPolicy", "geospatialConfig",
+			"defaultTtl", "_rid", "_ts", "_self", "_etag", "ru", "maxru", "shared_throughput", "num_pkranges"},
+		rows:  []DocInfo{row},
+		count: 1,
+	}
+	return result, nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtShowThroughput implements "SHOW THROUGHPUT" statement.
+//
+// Syntax:
+//
+//	SHOW THROUGHPUT FOR DATABASE <db-name>
+//	SHOW THROUGHPUT FOR COLLECTION|TABLE [<db-name>.]<collection-name>
+//
+// The result set has exactly one row, with the following columns:
+//   - id: name of the database or collection.
+//   - type: "database" or "collection".
+//   - ru: the provisioned throughput in RU/s, 0 if no throughput is provisioned.
+//   - maxru: the maximum throughput of autopilot (autoscale) setting, 0 if autopilot is not enabled.
+//   - autopilot: true if autopilot is enabled.
+//   - shared_throughput: true if the collection has no dedicated throughput and shares the database's.
+//   - max_ru_ever_provisioned: the maximum throughput ever provisioned.
+//   - num_pkranges: number of partition key ranges of the collection (nil for a database).
+//
+// @Available since v1.2.0
+type StmtShowThroughput struct {
+	*Stmt
+	dbName   string
+	collName string // empty if showing throughput of the database
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtShowThroughput) String() string {
+	return fmt.Sprintf(`StmtShowThroughput{Stmt: %s, db: %q, collection: %q}`, s.Stmt, s.dbName, s.collName)
+}
+
+func (s *StmtShowThroughput) validate() error {
+	if s.dbName == "" {
+		return errors.New("database is missing")
+	}
+	return nil
+}
+
+// Exec implements driver.Stmt/Exec.
+// This function is not implemented, use Query instead.
+func (s *StmtShowThroughput) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, ErrExecNotSupported
+}
+
+// Query implements driver.Stmt/Query.
+func (s *StmtShowThroughput) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+func (s *StmtShowThroughput) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected 0 input value, got %d", len(args))
+	}
+
+	// TODO: pass ctx to REST API client
+	row := DocInfo{"id": s.dbName, "type": "database", "num_pkranges": nil}
+	var collInfo *CollInfo
+	if s.collName != "" {
+		var numPkranges int
+		var err error
+		if collInfo, numPkranges, err = _fetchCollInfo(s.conn.restClient, s.dbName, s.collName); err != nil {
+			return nil, err
+		}
+		row["id"], row["type"], row["num_pkranges"] = collInfo.Id, "collection", numPkranges
+	}
+	offer, shared, err := _fetchThroughput(s.conn.restClient, s.dbName, collInfo)
+	if err != nil {
+		return nil, err
+	}
+	row["ru"] = offer.OfferThroughput()
+	row["maxru"] = offer.AutopilotMaxThroughput()
+	row["autopilot"] = offer.IsAutopilot()
+	row["shared_throughput"] = shared
+	row["max_ru_ever_provisioned"] = offer.MaxThroughputEverProvisioned()
+	result := &ResultResultSet{
+		columnList: []string{"id", "type", "ru", "maxru", "autopilot", "shared_throughput", "max_ru_ever_provisioned", "num_pkranges"},
+		rows:       []DocInfo{row},
+		count:      1,
+	}
+	return result, nil
+}
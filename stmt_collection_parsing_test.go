@@ -5,6 +5,10 @@ import (
 	"testing"
 )
 
+func _ttl(v int) *int {
+	return &v
+}
+
 func TestStmtCreateCollection_parse(t *testing.T) {
 	testName := "TestStmtCreateCollection_parse"
 	testData := []struct {
@@ -25,12 +29,28 @@ func TestStmtCreateCollection_parse(t *testing.T) {
 		{name: "error_no_collection", sql: "CREATE TABLE db WITH Pk=/id", mustError: true},
 		{name: "error_if_not_exist", sql: "CREATE TABLE IF NOT EXIST db.table WITH Pk=/id", mustError: true},
 		{name: "error_invalid_with", sql: "CREATE TABLE db.table WITH Pk=/id, WITH a=1", mustError: true},
+		{name: "error_invalid_conflict_policy", sql: "CREATE TABLE db.table WITH Pk=/id WITH CONFLICT_POLICY=FIRST_WINS", mustError: true},
+		{name: "error_invalid_conflict_policy_path", sql: "CREATE TABLE db.table WITH Pk=/id WITH CONFLICT_POLICY=LWW:version", mustError: true},
+		{name: "error_invalid_indexing_not_quoted", sql: "CREATE TABLE db.table WITH Pk=/id WITH INDEXING=consistent", mustError: true},
+		{name: "error_invalid_ttl", sql: "CREATE TABLE db.table WITH Pk=/id WITH TTL=0", mustError: true},
+		{name: "error_invalid_ttl_negative", sql: "CREATE TABLE db.table WITH Pk=/id WITH TTL=-2", mustError: true},
+		{name: "error_invalid_indexing_json", sql: "CREATE TABLE db.table WITH Pk=/id WITH INDEXING='{\"indexingMode\":'", mustError: true},
 
 		{name: "basic", sql: "CREATE COLLECTION db1.table1 WITH pk=/id", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id"}},
 		{name: "table_with_ru", sql: "create\ntable\rdb-2.table_2 WITH\tPK=/email WITH\r\nru=100", expected: &StmtCreateCollection{dbName: "db-2", collName: "table_2", pk: "/email", ru: 100}},
 		{name: "if_not_exists_large_pk_with_maxru", sql: "CREATE collection\nIF\rNOT\t\nEXISTS\n\tdb_3.table-3 with largePK=/id WITH\t\rmaxru=100", expected: &StmtCreateCollection{dbName: "db_3", collName: "table-3", ifNotExists: true, pk: "/id", maxru: 100}},
 		{name: "table_if_not_exists_large_pk_with_uk", sql: "create TABLE if not exists db-0_1.table_0-1 WITH LARGEpk=/a/b/c with uk=/a:/b,/c/d;/e/f/g", expected: &StmtCreateCollection{dbName: "db-0_1", collName: "table_0-1", ifNotExists: true, pk: "/a/b/c", uk: [][]string{{"/a"}, {"/b", "/c/d"}, {"/e/f/g"}}}},
 		{name: "subpartitions", sql: "CREATE COLLECTION db1.table1 WITH pK=/TenantId,/UserId,/SessionId", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/TenantId,/UserId,/SessionId"}},
+		{name: "comma_separated_opts", sql: "CREATE COLLECTION db1.table1 WITH pk=/id, ru=400, WITH uk=/a:/b", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", ru: 400, uk: [][]string{{"/a"}, {"/b"}}}},
+		{name: "subpartitions_comma_separated_opts", sql: "CREATE COLLECTION db1.table1 WITH pk=/TenantId,/UserId, maxru=4000", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/TenantId,/UserId", maxru: 4000}},
+		{name: "conflict_policy_lww", sql: "CREATE COLLECTION db1.table1 WITH pk=/id WITH conflict_policy=lww", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", conflictPolicy: map[string]interface{}{"mode": "LastWriterWins", "conflictResolutionPath": "/_ts"}}},
+		{name: "conflict_policy_lww_path", sql: "CREATE COLLECTION db1.table1 WITH pk=/id, CONFLICT_POLICY=LWW:/version", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", conflictPolicy: map[string]interface{}{"mode": "LastWriterWins", "conflictResolutionPath": "/version"}}},
+		{name: "conflict_policy_custom", sql: "CREATE COLLECTION db1.table1 WITH pk=/id WITH CONFLICT_POLICY=Custom", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", conflictPolicy: map[string]interface{}{"mode": "Custom"}}},
+		{name: "conflict_policy_custom_sproc", sql: "CREATE COLLECTION db1.table1 WITH pk=/id WITH CONFLICT_POLICY=CUSTOM:resolver", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", conflictPolicy: map[string]interface{}{"mode": "Custom", "conflictResolutionProcedure": "dbs/db1/colls/table1/sprocs/resolver"}}},
+		{name: "ttl", sql: "CREATE COLLECTION db1.table1 WITH pk=/id WITH TTL=3600", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", defaultTtl: _ttl(3600)}},
+		{name: "ttl_no_expiry", sql: "CREATE COLLECTION db1.table1 WITH pk=/id, ttl=-1", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", defaultTtl: _ttl(-1)}},
+		{name: "ttl_off", sql: "CREATE COLLECTION db1.table1 WITH pk=/id WITH TTL=off", expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id"}},
+		{name: "indexing_policy", sql: `CREATE COLLECTION db1.table1 WITH pk=/id WITH INDEXING='{"indexingMode":"consistent","excludedPaths":[{"path":"/content/*"}]}'`, expected: &StmtCreateCollection{dbName: "db1", collName: "table1", pk: "/id", indexingPolicy: map[string]interface{}{"indexingMode": "consistent", "excludedPaths": []interface{}{map[string]interface{}{"path": "/content/*"}}}}},
 	}
 	for _, testCase := range testData {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -113,9 +133,12 @@ func TestStmtAlterCollection_parse(t *testing.T) {
 		{name: "error_invalid_ru", sql: "alter TABLE db.coll WITH ru=-1", mustError: true},
 		{name: "error_invalid_maxru", sql: "alter TABLE db.coll WITH maxru=-1", mustError: true},
 		{name: "error_invalid_with", sql: "alter TABLE db.coll WITH ru=400, WITH a=1", mustError: true},
+		{name: "error_invalid_ttl", sql: "alter TABLE db.coll WITH ttl=abc", mustError: true},
 
 		{name: "basic", sql: "ALTER collection db1.table1 WITH ru=400", expected: &StmtAlterCollection{dbName: "db1", collName: "table1", ru: 400}},
 		{name: "table", sql: "alter\nTABLE\rdb-2.table_2 WITH\tmaxru=40000", expected: &StmtAlterCollection{dbName: "db-2", collName: "table_2", maxru: 40000}},
+		{name: "ttl", sql: "ALTER COLLECTION db1.table1 WITH TTL=86400", expected: &StmtAlterCollection{dbName: "db1", collName: "table1", hasTtl: true, ttl: _ttl(86400)}},
+		{name: "ttl_off_with_ru", sql: "ALTER COLLECTION db1.table1 WITH ru=400, TTL=OFF", expected: &StmtAlterCollection{dbName: "db1", collName: "table1", ru: 400, hasTtl: true}},
 	}
 	for _, testCase := range testData {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -350,3 +373,91 @@ func TestStmtListCollections_parse_defaultDb(t *testing.T) {
 		})
 	}
 }
+
+func TestStmtDescribeCollection_parse(t *testing.T) {
+	testName := "TestStmtDescribeCollection_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtDescribeCollection
+		mustError bool
+	}{
+		{name: "error_no_collection", sql: "DESCRIBE", mustError: true},
+		{name: "error_no_db", sql: "DESCRIBE COLLECTION table", mustError: true},
+		{name: "error_trailing_tokens", sql: "DESCRIBE db.table WITH pk=/id", mustError: true},
+
+		{name: "basic", sql: "DESCRIBE db1.table1", expected: &StmtDescribeCollection{dbName: "db1", collName: "table1"}},
+		{name: "collection", sql: "describe \n\tCOLLECTION\t db-2.table-2", expected: &StmtDescribeCollection{dbName: "db-2", collName: "table-2"}},
+		{name: "desc_table", sql: "DESC table db_3.table_3", expected: &StmtDescribeCollection{dbName: "db_3", collName: "table_3"}},
+		{name: "collection_named_table", sql: "DESCRIBE db.table", expected: &StmtDescribeCollection{dbName: "db", collName: "table"}},
+		{name: "default_db", db: "mydb", sql: "DESCRIBE table", expected: &StmtDescribeCollection{dbName: "mydb", collName: "table"}},
+		{name: "default_db_collection", db: "mydb", sql: "DESCRIBE COLLECTION table", expected: &StmtDescribeCollection{dbName: "mydb", collName: "table"}},
+		{name: "db_in_query", db: "mydb", sql: "DESCRIBE db.table", expected: &StmtDescribeCollection{dbName: "db", collName: "table"}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			stmt, ok := s.(*StmtDescribeCollection)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtDescribeCollection but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = nil
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestStmtShowThroughput_parse(t *testing.T) {
+	testName := "TestStmtShowThroughput_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtShowThroughput
+		mustError bool
+	}{
+		{name: "error_no_for", sql: "SHOW THROUGHPUT DATABASE db", mustError: true},
+		{name: "error_invalid_object", sql: "SHOW THROUGHPUT FOR PROCEDURE db.table.sp", mustError: true},
+		{name: "error_no_db", sql: "SHOW THROUGHPUT FOR COLLECTION table", mustError: true},
+		{name: "error_db_qualified", sql: "SHOW THROUGHPUT FOR DATABASE db.table", mustError: true},
+
+		{name: "database", sql: "SHOW THROUGHPUT FOR DATABASE db-1", expected: &StmtShowThroughput{dbName: "db-1"}},
+		{name: "collection", sql: "show throughput\n\tfor COLLECTION db_2.table_2", expected: &StmtShowThroughput{dbName: "db_2", collName: "table_2"}},
+		{name: "table", sql: "SHOW THROUGHPUT FOR TABLE db3.table3", expected: &StmtShowThroughput{dbName: "db3", collName: "table3"}},
+		{name: "default_db", db: "mydb", sql: "SHOW THROUGHPUT FOR COLLECTION table", expected: &StmtShowThroughput{dbName: "mydb", collName: "table"}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			stmt, ok := s.(*StmtShowThroughput)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtShowThroughput but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = nil
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
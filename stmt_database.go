@@ -32,8 +32,8 @@ func (s *StmtCreateDatabase) String() string {
 		s.Stmt, s.dbName, s.ifNotExists, s.ru, s.maxru)
 }
 
-func (s *StmtCreateDatabase) parse(withOptsStr string) error {
-	if err := s.Stmt.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtCreateDatabase) parse(opts []astWithOpt) error {
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
 		return err
 	}
 
@@ -120,8 +120,8 @@ func (s *StmtAlterDatabase) String() string {
 		s.Stmt, s.dbName, s.ru, s.maxru)
 }
 
-func (s *StmtAlterDatabase) parse(withOptsStr string) error {
-	if err := s.Stmt.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtAlterDatabase) parse(opts []astWithOpt) error {
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
 		return err
 	}
 
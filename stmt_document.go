@@ -2,154 +2,27 @@ package gocosmos
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/btnguyen2k/consu/reddo"
 	"os"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/btnguyen2k/consu/g18"
 )
 
-var (
-	reValNull          = regexp.MustCompile(`(?i)(null)\s*?`)
-	reValNumber        = regexp.MustCompile(`([\d\.xe+-]+)\s*?`)
-	reValBoolean       = regexp.MustCompile(`(?i)(true|false)\s*?`)
-	reValString        = regexp.MustCompile(`(?i)("(\\"|[^"])*?")\s*?`)
-	reValPlaceholder   = regexp.MustCompile(`(?i)[$@:](\d+)\s*?`)
-	reValStringNoQuote = regexp.MustCompile(`(?i)([a-z0-9_./;:\\-]+)`)
-
-	reFieldEqual = regexp.MustCompile(field + `\s*=(.*?)`)
-)
-
 type placeholder struct {
 	index int
 }
 
-func _buildLeftover(input string, reSep *regexp.Regexp) string {
-	if sep := reSep.FindString(input); sep != "" {
-		input = input[len(sep):]
-	}
-	return input
-}
-
-func _parseValue(input string, separator rune) (value interface{}, leftOver string, err error) {
-	reSep := regexp.MustCompile(`^\s*\` + string(separator) + `\s*`)
-
-	if loc := reValPlaceholder.FindStringIndex(input); loc != nil && loc[0] == 0 {
-		token := strings.TrimSpace(input[loc[0]+1 : loc[1]])
-		index, err := strconv.Atoi(token)
-		return placeholder{index}, _buildLeftover(input[loc[1]:], reSep), err
-	}
-
-	if loc := reValNull.FindStringIndex(input); loc != nil && loc[0] == 0 {
-		return nil, _buildLeftover(input[loc[1]:], reSep), nil
-	}
-
-	if loc := reValNumber.FindStringIndex(input); loc != nil && loc[0] == 0 {
-		token := strings.TrimSpace(input[loc[0]:loc[1]])
-		var data interface{}
-		err := json.Unmarshal([]byte(token), &data)
-		if err != nil {
-			err = errors.New("(number) cannot parse query, invalid token at: " + token)
-		}
-		return data, _buildLeftover(input[loc[1]:], reSep), err
-	}
-
-	if loc := reValBoolean.FindStringIndex(input); loc != nil && loc[0] == 0 {
-		token := strings.TrimSpace(input[loc[0]:loc[1]])
-		var data bool
-		err := json.Unmarshal([]byte(token), &data)
-		if err != nil {
-			err = errors.New("(bool) cannot parse query, invalid token at: " + token)
-		}
-		return data, _buildLeftover(input[loc[1]:], reSep), err
-	}
-
-	if loc := reValString.FindStringIndex(input); loc != nil && loc[0] == 0 {
-		var data interface{}
-		token, err := strconv.Unquote(strings.TrimSpace(input[loc[0]:loc[1]]))
-		if err == nil {
-			err = json.Unmarshal([]byte(token), &data)
-			if err != nil {
-				err = errors.New("(unmarshal) cannot parse query, invalid token at: " + token)
-			}
-		} else {
-			err = errors.New("(unquote) cannot parse query, invalid token at: " + token)
-		}
-		return data, _buildLeftover(input[loc[1]:], reSep), err
-	}
-	if loc := reValStringNoQuote.FindStringIndex(input); loc != nil && loc[0] == 0 {
-		data := strings.TrimSpace(input[loc[0]:loc[1]])
-		return data, _buildLeftover(input[loc[1]:], reSep), err
-	}
-
-	return nil, input, errors.New("cannot parse query, invalid token at: " + input)
-}
-
-func _parseFieldValueAnd(input string) (field string, value interface{}, leftOver string, err error) {
-	reSep := regexp.MustCompile(`(?i)^\s*and\s+`)
-
-	//must star with <field>\s*=
-	if loc := reFieldEqual.FindStringSubmatchIndex(input); loc != nil && loc[0] == 0 {
-		field = input[loc[2]:loc[3]]
-		input = strings.TrimSpace(input[loc[1]:])
-
-		if loc := reValPlaceholder.FindStringIndex(input); loc != nil && loc[0] == 0 {
-			token := strings.TrimSpace(input[loc[0]+1 : loc[1]])
-			index, err := strconv.Atoi(token)
-			return field, placeholder{index}, _buildLeftover(input[loc[1]:], reSep), err
-		}
-
-		if loc := reValNull.FindStringIndex(input); loc != nil && loc[0] == 0 {
-			return field, nil, _buildLeftover(input[loc[1]:], reSep), nil
-		}
-
-		if loc := reValNumber.FindStringIndex(input); loc != nil && loc[0] == 0 {
-			token := strings.TrimSpace(input[loc[0]:loc[1]])
-			var data interface{}
-			err := json.Unmarshal([]byte(token), &data)
-			if err != nil {
-				err = errors.New("(number) cannot parse query, invalid token at: " + token)
-			}
-			return field, data, _buildLeftover(input[loc[1]:], reSep), err
-		}
-
-		if loc := reValBoolean.FindStringIndex(input); loc != nil && loc[0] == 0 {
-			token := strings.TrimSpace(input[loc[0]:loc[1]])
-			var data bool
-			err := json.Unmarshal([]byte(token), &data)
-			if err != nil {
-				err = errors.New("(bool) cannot parse query, invalid token at: " + token)
-			}
-			return field, data, _buildLeftover(input[loc[1]:], reSep), err
-		}
-
-		if loc := reValString.FindStringIndex(input); loc != nil && loc[0] == 0 {
-			var data interface{}
-			token, err := strconv.Unquote(strings.TrimSpace(input[loc[0]:loc[1]]))
-			if err == nil {
-				err = json.Unmarshal([]byte(token), &data)
-				if err != nil {
-					err = errors.New("(unmarshal) cannot parse query, invalid token at: " + token)
-				}
-			} else {
-				err = errors.New("(unquote) cannot parse query, invalid token at: " + token)
-			}
-			return field, data, _buildLeftover(input[loc[1]:], reSep), err
-		}
-		if loc := reValStringNoQuote.FindStringIndex(input); loc != nil && loc[0] == 0 {
-			data := strings.TrimSpace(input[loc[0]:loc[1]])
-			return field, data, _buildLeftover(input[loc[1]:], reSep), err
-		}
-	}
-	return "", nil, input, errors.New("cannot parse query, invalid token at: " + input)
-}
-
 // StmtCRUD is abstract implementation of "INSERT|UPSERT|UPDATE|DELETE|SELECT" operations.
 //
 // @Available since v0.3.0
@@ -160,15 +33,60 @@ type StmtCRUD struct {
 	isSinglePathPk bool
 	withPk         string
 	pkPaths        []string
-	numPkPaths     int // number of PK paths
+	numPkPaths     int      // number of PK paths
+	returning      []string // (since v1.2.0) fields listed in the RETURNING clause, nil if the clause is not specified
 }
 
 // String implements interface fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtCRUD) String() string {
-	return fmt.Sprintf(`StmtCRUD{Stmt: %s, db: %q, collection: %q, is_single_pk: %v, with_pk: %q, pk_paths: %v, num_pk_paths: %d}`,
-		s.Stmt, s.dbName, s.collName, s.isSinglePathPk, s.withPk, s.pkPaths, s.numPkPaths)
+	return fmt.Sprintf(`StmtCRUD{Stmt: %s, db: %q, collection: %q, is_single_pk: %v, with_pk: %q, pk_paths: %v, num_pk_paths: %d, returning: %v}`,
+		s.Stmt, s.dbName, s.collName, s.isSinglePathPk, s.withPk, s.pkPaths, s.numPkPaths, s.returning)
+}
+
+// setReturning sets the fields listed in the RETURNING clause.
+//
+// @Available since v1.2.0
+func (s *StmtCRUD) setReturning(fields []string) {
+	s.returning = fields
+}
+
+// buildReturningResult builds the result set of the RETURNING clause from the written documents.
+// Documents are returned as-is (system attributes included), nil documents (not written) are skipped.
+//
+// @Available since v1.2.0
+func (s *StmtCRUD) buildReturningResult(docs []DocInfo) *ResultResultSet {
+	rows := make([]DocInfo, 0, len(docs))
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if len(s.returning) == 1 && s.returning[0] == "*" {
+			rows = append(rows, doc)
+			continue
+		}
+		row := make(DocInfo, len(s.returning))
+		for _, field := range s.returning {
+			row[field] = doc[field]
+		}
+		rows = append(rows, row)
+	}
+	return (&ResultResultSet{rows: rows, columnList: make([]string, 0)}).init()
+}
+
+// queryReturning executes the statement and returns the written documents as a result set.
+//
+// @Available since v1.2.0
+func (s *StmtCRUD) queryReturning(execute func([]driver.NamedValue) (driver.Result, []DocInfo, error), args []driver.NamedValue) (driver.Rows, error) {
+	if s.returning == nil {
+		return nil, ErrQueryNotSupported
+	}
+	_, docs, err := execute(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildReturningResult(docs), nil
 }
 
 func (s *StmtCRUD) fetchPkInfo() error {
@@ -184,8 +102,8 @@ func (s *StmtCRUD) fetchPkInfo() error {
 	return normalizeError(getCollResult.StatusCode, 0, getCollResult.Error())
 }
 
-func (s *StmtCRUD) parseWithOpts(withOptsStr string) error {
-	if err := s.Stmt.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtCRUD) parseWithOpts(opts []astWithOpt) error {
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
 		return err
 	}
 
@@ -226,15 +144,397 @@ func (s *StmtCRUD) parseWithOpts(withOptsStr string) error {
 	return nil
 }
 
+var (
+	// defaultConcurrency is the default number of documents to be processed in parallel by multi-document operations.
+	// The bound keeps request rate moderate, so that a single statement does not exhaust the provisioned throughput.
+	defaultConcurrency = 4
+
+	// insertSelectPageSize is the number of documents fetched per page by "INSERT...SELECT" statements.
+	insertSelectPageSize = 100
+)
+
+// _parsePositiveIntOpt parses the value of a WITH option that must be a positive integer.
+//
+// @Available since v1.2.0
+func _parsePositiveIntOpt(k, v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s value: %s", k, v)
+	}
+	return n, nil
+}
+
+// _parseTtlOpt parses the value of WITH TTL option: a positive number of seconds, or -1 (never expire).
+// If allowOff is true, OFF is also accepted and nil is returned.
+//
+// @Available since v1.2.0
+func _parseTtlOpt(v string, allowOff bool) (*int, error) {
+	if allowOff && strings.EqualFold(v, "OFF") {
+		return nil, nil
+	}
+	ttl, err := strconv.Atoi(v)
+	if err != nil || (ttl <= 0 && ttl != -1) {
+		return nil, fmt.Errorf("invalid TTL value: %s", v)
+	}
+	return &ttl, nil
+}
+
+// _ttlToString returns the string representation of a time-to-live setting, OFF if nil.
+func _ttlToString(ttl *int) string {
+	if ttl == nil {
+		return "OFF"
+	}
+	return strconv.Itoa(*ttl)
+}
+
+// _parseIfMatchOpt parses the value of WITH IF_MATCH option, which must be a placeholder, a named parameter or a JSON string.
+//
+// @Available since v1.2.0
+func _parseIfMatchOpt(v string, paramNames []string) (interface{}, error) {
+	value, err := _parseLiteral(v, paramNames)
+	if err == nil {
+		switch value.(type) {
+		case placeholder, string:
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid IF_MATCH value: %s", v)
+}
+
+// _resolveEtag returns the expected etag supplied via "_etag=<value>" or WITH IF_MATCH, empty string if not supplied.
+//
+// @Available since v1.2.0
+func _resolveEtag(etag interface{}, args []driver.NamedValue) string {
+	if p, ok := etag.(placeholder); ok {
+		etag = args[p.index-1].Value
+	}
+	result, _ := reddo.ToString(etag)
+	return result
+}
+
+// _buildQueryParams builds the query parameters for the placeholders found in a predicate.
+//
+// @Available since v1.2.0
+func _buildQueryParams(placeholders map[int]string, args []driver.NamedValue) ([]interface{}, error) {
+	params := make([]interface{}, 0, len(placeholders))
+	for index, name := range placeholders {
+		if index <= 0 || index > len(args) {
+			return nil, fmt.Errorf("there is no value for placeholder #%d", index)
+		}
+		params = append(params, map[string]interface{}{"name": name, "value": args[index-1].Value})
+	}
+	return params, nil
+}
+
+// _extractPkValues extracts the partition key values of a document.
+// Missing partition key values are represented by an empty object (i.e. "undefined" partition key value).
+//
+// @Available since v1.2.0
+func _extractPkValues(doc DocInfo, pkPaths []string) []interface{} {
+	pkValues := make([]interface{}, len(pkPaths))
+	for i, pkPath := range pkPaths {
+		var value interface{} = map[string]interface{}(doc)
+		for _, part := range strings.Split(strings.Trim(pkPath, "/"), "/") {
+			m, ok := value.(map[string]interface{})
+			if ok {
+				value, ok = m[part]
+			}
+			if !ok {
+				value = map[string]interface{}{}
+				break
+			}
+		}
+		pkValues[i] = value
+	}
+	return pkValues
+}
+
+// _idPkProjection builds the projection of a query that fetches only id, _etag and partition key values (aliased as _pk<index>) of documents.
+//
+// @Available since v1.2.0
+func _idPkProjection(pkPaths []string) string {
+	projection := "c.id, c._etag"
+	for i, pkPath := range pkPaths {
+		projection += fmt.Sprintf(`, c["%s"] AS _pk%d`, strings.Join(strings.Split(strings.Trim(pkPath, "/"), "/"), `"]["`), i)
+	}
+	return projection
+}
+
+// _projectedPkValues extracts partition key values from a document fetched with the projection built by _idPkProjection.
+//
+// @Available since v1.2.0
+func _projectedPkValues(doc DocInfo, numPkPaths int) []interface{} {
+	pkValues := make([]interface{}, numPkPaths)
+	for i := range pkValues {
+		// missing partition key value is represented by an empty object
+		pkValue, ok := doc[fmt.Sprintf("_pk%d", i)]
+		if !ok {
+			pkValue = map[string]interface{}{}
+		}
+		pkValues[i] = pkValue
+	}
+	return pkValues
+}
+
+// requeryDoc re-checks whether a document still matches the WHERE predicate of a multi-document statement, e.g. after
+// the document has been modified since being queried. It returns the current version of the document (with the given
+// projection), or nil if the document has been deleted or no longer matches the predicate.
+//
+// @Available since v1.2.0
+func (s *StmtCRUD) requeryDoc(projection, predicate string, params []interface{}, id string, pkValues []interface{}) (DocInfo, error) {
+	conds := "c.id = @__id"
+	queryParams := append(make([]interface{}, 0, len(params)+1+len(pkValues)), params...)
+	queryParams = append(queryParams, map[string]interface{}{"name": "@__id", "value": id})
+	for i, pkPath := range s.pkPaths {
+		field := fmt.Sprintf(`c["%s"]`, strings.Join(strings.Split(strings.Trim(pkPath, "/"), "/"), `"]["`))
+		if m, ok := pkValues[i].(map[string]interface{}); ok && len(m) == 0 {
+			// missing partition key value, see _extractPkValues
+			conds += " AND NOT IS_DEFINED(" + field + ")"
+			continue
+		}
+		name := fmt.Sprintf("@__pk%d", i)
+		conds += " AND " + field + " = " + name
+		queryParams = append(queryParams, map[string]interface{}{"name": name, "value": pkValues[i]})
+	}
+	queryResult := s.conn.restClient.QueryDocumentsCrossPartition(QueryReq{
+		DbName:                s.dbName,
+		CollName:              s.collName,
+		Query:                 "SELECT " + projection + " FROM c WHERE " + conds + " AND (" + predicate + ")",
+		Params:                queryParams,
+		CrossPartitionEnabled: true,
+	})
+	if err := queryResult.Error(); err != nil {
+		return nil, normalizeError(queryResult.StatusCode, 0, err)
+	}
+	if docs := queryResult.Documents.AsDocInfoSlice(); len(docs) > 0 {
+		return docs[0], nil
+	}
+	return nil, nil
+}
+
+// _execConcurrently executes fn for each item in range [0, n) using at most concurrency goroutines.
+// It returns the total number of affected rows and the first error encountered (remaining items are skipped once an error occurred).
+//
+// @Available since v1.2.0
+func _execConcurrently(n, concurrency int, fn func(i int) (int64, error)) (int64, error) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	var (
+		mutex        sync.Mutex
+		wg           sync.WaitGroup
+		affectedRows int64
+		firstErr     error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		mutex.Lock()
+		failed := firstErr != nil
+		mutex.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			rows, err := fn(i)
+			mutex.Lock()
+			defer mutex.Unlock()
+			affectedRows += rows
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(i)
+	}
+	wg.Wait()
+	return affectedRows, firstErr
+}
+
+// _partialWriteError reports the partial outcome of a multi-row write that failed with err: database/sql discards the
+// driver.Result returned along with an error, hence the caller can not get the number of written rows via RowsAffected.
+// notWritten lists the (1-based) numbers of the rows that were not written, either failed or not attempted; more is true
+// if there are further rows, following the listed ones, that were not attempted either.
+//
+// @Available since v1.2.0
+func _partialWriteError(err error, written int64, notWritten []int, more bool) error {
+	// consecutive row numbers are collapsed into ranges, e.g. "2, 5-7"
+	rows := make([]string, 0, len(notWritten))
+	for i := 0; i < len(notWritten); {
+		j := i
+		for j+1 < len(notWritten) && notWritten[j+1] == notWritten[j]+1 {
+			j++
+		}
+		if j > i {
+			rows = append(rows, fmt.Sprintf("%d-%d", notWritten[i], notWritten[j]))
+		} else {
+			rows = append(rows, strconv.Itoa(notWritten[i]))
+		}
+		i = j + 1
+	}
+	if more {
+		rows = append(rows, "and all following rows")
+	}
+	return fmt.Errorf("%w (%d row(s) written, not written: row %s)", err, written, strings.Join(rows, ", "))
+}
+
+const fieldPath = `[\w\-]+(?:\.[\w\-]+|\[\d+\])*`
+
+var (
+	// reFieldPath matches a (possibly nested) field path, e.g. "name", "address.city" or "tags[0]".
+	reFieldPath = regexp.MustCompile(`^` + fieldPath + `$`)
+
+	// reFieldPathSegment matches a segment of a field path: either an object key or an array index.
+	reFieldPathSegment = regexp.MustCompile(`([\w\-]+)|\[(\d+)\]`)
+)
+
+// _parseFieldPath splits a field path into segments: object keys are returned as strings, array indices as ints.
+//
+// @Available since v1.2.0
+func _parseFieldPath(path string) ([]interface{}, error) {
+	if !reFieldPath.MatchString(path) {
+		return nil, fmt.Errorf("invalid field path: %s", path)
+	}
+	matches := reFieldPathSegment.FindAllStringSubmatch(path, -1)
+	segments := make([]interface{}, len(matches))
+	for i, match := range matches {
+		if match[2] != "" {
+			segments[i], _ = strconv.Atoi(match[2])
+		} else {
+			segments[i] = match[1]
+		}
+	}
+	return segments, nil
+}
+
+// _fieldPathToJsonPointer converts a field path to JSON pointer notation, e.g. "tags[0].name" to "/tags/0/name".
+//
+// @Available since v1.2.0
+func _fieldPathToJsonPointer(path string) string {
+	segments, _ := _parseFieldPath(path)
+	result := ""
+	for _, segment := range segments {
+		result += fmt.Sprintf("/%v", segment)
+	}
+	return result
+}
+
+// _setFieldPath sets value at the field path of the document, creating intermediate objects and arrays as needed.
+// An array index can either refer to an existing element or be equal to the array's length (i.e. appending a new element).
+//
+// @Available since v1.2.0
+func _setFieldPath(doc map[string]interface{}, path string, value interface{}) error {
+	return _modifyFieldPath(doc, path, func(interface{}) (interface{}, error) { return value, nil })
+}
+
+// _modifyFieldPath replaces the value at the field path of the document with the one computed by fn from the current value
+// (nil if the field does not exist), creating intermediate objects and arrays as needed.
+//
+// @Available since v1.2.0
+func _modifyFieldPath(doc map[string]interface{}, path string, fn func(current interface{}) (interface{}, error)) error {
+	segments, err := _parseFieldPath(path)
+	if err != nil {
+		return err
+	}
+	if _, err = _modifyPathSegments(doc, segments, fn); err != nil {
+		return fmt.Errorf("cannot set field %s: %w", path, err)
+	}
+	return nil
+}
+
+// _removeFieldPath removes the field at the field path of the document.
+//
+// @Available since v1.2.0
+func _removeFieldPath(doc map[string]interface{}, path string) error {
+	segments, err := _parseFieldPath(path)
+	if err != nil {
+		return err
+	}
+	removeFn := func(parent interface{}) (interface{}, error) {
+		switch segment := segments[len(segments)-1].(type) {
+		case int:
+			arr, ok := parent.([]interface{})
+			if !ok || segment >= len(arr) {
+				return nil, fmt.Errorf("array index [%d] out of range", segment)
+			}
+			return append(arr[:segment:segment], arr[segment+1:]...), nil
+		default:
+			obj, ok := parent.(map[string]interface{})
+			if _, exists := obj[segment.(string)]; !ok || !exists {
+				return nil, errors.New("field does not exist")
+			}
+			delete(obj, segment.(string))
+			return obj, nil
+		}
+	}
+	if _, err = _modifyPathSegments(doc, segments[:len(segments)-1], removeFn); err != nil {
+		return fmt.Errorf("cannot remove field %s: %w", path, err)
+	}
+	return nil
+}
+
+func _modifyPathSegments(node interface{}, segments []interface{}, fn func(current interface{}) (interface{}, error)) (interface{}, error) {
+	if len(segments) == 0 {
+		return fn(node)
+	}
+	switch segment := segments[0].(type) {
+	case int:
+		arr, ok := node.([]interface{})
+		if !ok && node != nil {
+			return nil, fmt.Errorf("not an array at index [%d]", segment)
+		}
+		if segment > len(arr) {
+			return nil, fmt.Errorf("array index [%d] out of range", segment)
+		}
+		if segment == len(arr) {
+			arr = append(arr, nil)
+		}
+		child, err := _modifyPathSegments(arr[segment], segments[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		arr[segment] = child
+		return arr, nil
+	default:
+		key := segment.(string)
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			if node != nil {
+				return nil, fmt.Errorf("not an object at key %q", key)
+			}
+			obj = make(map[string]interface{})
+		}
+		child, err := _modifyPathSegments(obj[key], segments[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = child
+		return obj, nil
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
 // StmtInsert implements "INSERT" operation.
 //
 // Syntax:
 //
 //	INSERT|UPSERT INTO <db-name>.<collection-name>
 //	(<field-list>)
-//	VALUES (<value-list>)
+//	VALUES (<value-list>)[, (<value-list>)]*
 //	[WITH PK=/pk-path]
+//	[WITH CONCURRENCY=<n>]
+//	[WITH TTL=<seconds>|-1]
 //
+//	- (since v1.2.0) WITH TTL sets the "ttl" field (time-to-live in seconds, -1 means never expire) of the inserted documents.
+//	  TTL must be enabled on the collection for documents to expire, see StmtCreateCollection.
+//	- (since v1.2.0) multiple rows can be inserted in one statement, placeholders are numbered across all rows,
+//	  e.g. VALUES (:1, :2), (:3, :4). Rows are sent in parallel, WITH CONCURRENCY=<n> specifies how many (default 4).
+//	  A multi-row INSERT is not atomic, not even when all rows share the same partition key: each row is a separate write,
+//	  hence the statement can partially succeed. If a row fails, the returned error names the failing row, the number of
+//	  rows inserted and the rows not inserted (database/sql discards RowsAffected when Exec returns an error).
+//	- (since v1.2.0) a field can be a nested path, e.g. address.city or tags[0]; nested objects and arrays are created as needed.
 //	- values are comma separated.
 //	- a value is either:
 //	  - a placeholder (e.g. :1, @2 or $3)
@@ -256,53 +556,84 @@ func (s *StmtCRUD) parseWithOpts(withOptsStr string) error {
 // See https://docs.microsoft.com/en-us/azure/cosmos-db/account-databases-containers-items#properties-of-an-item.
 type StmtInsert struct {
 	*StmtCRUD
-	isUpsert  bool
-	fieldsStr string
-	valuesStr string
-	fields    []string
-	values    []interface{}
+	isUpsert    bool
+	fieldsStr   string
+	valuesStr   string
+	fields      []string
+	values      []interface{} // (since v1.2.0) values of all rows, row i is values[i*len(fields):(i+1)*len(fields)]
+	concurrency int           // (since v1.2.0) number of rows to be inserted in parallel
+	ttl         *int          // (since v1.2.0) time-to-live of inserted documents, nil means the collection's default
 }
 
 // String implements interface fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtInsert) String() string {
-	return fmt.Sprintf(`StmtInsert{StmtCRUD: %s, upsert: %v, field_str: %q, value_str: %q, fields: %v, values: %v}`,
-		s.StmtCRUD, s.isUpsert, s.fieldsStr, s.valuesStr, s.fields, s.values)
+	return fmt.Sprintf(`StmtInsert{StmtCRUD: %s, upsert: %v, field_str: %q, value_str: %q, fields: %v, values: %v, concurrency: %d, ttl: %s}`,
+		s.StmtCRUD, s.isUpsert, s.fieldsStr, s.valuesStr, s.fields, s.values, s.concurrency, _ttlToString(s.ttl))
 }
 
-func (s *StmtInsert) parse(withOptsStr string) error {
-	if err := s.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtInsert) parse(node *astInsert) error {
+	if err := s.parseWithOpts(node.withOpts()); err != nil {
 		return err
 	}
 
-	for k := range s.withOpts {
-		if k != "SINGLE_PK" && k != "SINGLEPK" && k != "PK" {
+	for k, v := range s.withOpts {
+		switch k {
+		case "SINGLE_PK", "SINGLEPK", "PK":
+		case "CONCURRENCY":
+			concurrency, err := _parsePositiveIntOpt(k, v)
+			if err != nil {
+				return err
+			}
+			s.concurrency = concurrency
+		case "TTL":
+			ttl, err := _parseTtlOpt(v, false)
+			if err != nil {
+				return err
+			}
+			s.ttl = ttl
+		default:
 			return fmt.Errorf("invalid query, parsing error at WITH %s", k)
 		}
 	}
 
-	s.fields = regexp.MustCompile(`[,\s]+`).Split(s.fieldsStr, -1)
+	s.fields = node.fields
 	s.values = make([]interface{}, 0)
-	for temp := strings.TrimSpace(s.valuesStr); temp != ""; temp = strings.TrimSpace(temp) {
-		value, leftOver, err := _parseValue(temp, ',')
-		if err == nil {
+	for i, row := range node.rows {
+		for _, value := range row {
 			s.values = append(s.values, value)
-			temp = leftOver
 			switch v := value.(type) {
 			case placeholder:
 				s.numInputs = g18.Max(s.numInputs, v.index)
 			}
-			continue
 		}
-		return err
+		if len(row) != len(s.fields) {
+			if len(node.rows) == 1 {
+				return fmt.Errorf("number of fields (%d) does not match number of values (%d)", len(s.fields), len(row))
+			}
+			return fmt.Errorf("row %d: number of fields (%d) does not match number of values (%d)", i+1, len(s.fields), len(row))
+		}
+	}
+	if len(node.rows) > 1 && s.isSinglePathPk {
+		return errors.New("WITH SINGLE_PK is not supported when inserting multiple rows, use WITH PK instead")
+	}
+	if len(node.rows) < 2 && s.concurrency > 0 {
+		return errors.New("WITH CONCURRENCY is only supported when inserting multiple rows")
 	}
 
 	return nil
 }
 
+// numRows returns the number of rows to be inserted.
+//
+// @Available since v1.2.0
+func (s *StmtInsert) numRows() int {
+	return len(s.values) / len(s.fields)
+}
+
 func (s *StmtInsert) validate() error {
-	if len(s.fields) != len(s.values) {
+	if len(s.values) == 0 || len(s.values)%len(s.fields) != 0 {
 		return fmt.Errorf("number of fields (%d) does not match number of values (%d)", len(s.fields), len(s.values))
 	}
 	if s.dbName == "" || s.collName == "" {
@@ -325,31 +656,88 @@ func (s *StmtInsert) Exec(args []driver.Value) (driver.Result, error) {
 func (s *StmtInsert) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
 	// TODO: pass ctx to REST API client
 
+	result, _, err := s.execute(args)
+	return result, err
+}
+
+// execute inserts the rows and returns the inserted documents.
+func (s *StmtInsert) execute(args []driver.NamedValue) (driver.Result, []DocInfo, error) {
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
 	if err := s.fetchPkInfo(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	pkValues := make([]driver.NamedValue, s.numPkPaths)
+	numRows := s.numRows()
+	if numRows > 1 {
+		if len(args) != s.numInputs {
+			return nil, nil, fmt.Errorf("expected %d input values, got %d", s.numInputs, len(args))
+		}
+		numFields := len(s.fields)
+		docs, inserted := make([]DocInfo, numRows), make([]bool, numRows)
+		affectedRows, err := _execConcurrently(numRows, s.concurrency, func(i int) (int64, error) {
+			row := s.values[i*numFields : (i+1)*numFields]
+			pkValues, err := s.pkValuesFromRow(row)
+			if err == nil {
+				_, docs[i], err = s.insertRow(row, pkValues, args)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("row %d: %w", i+1, err)
+			}
+			inserted[i] = true
+			return 1, nil
+		})
+		if err != nil {
+			notInserted := make([]int, 0, numRows-int(affectedRows))
+			for i := range inserted {
+				if !inserted[i] {
+					notInserted = append(notInserted, i+1)
+				}
+			}
+			err = _partialWriteError(err, affectedRows, notInserted, false)
+		}
+		return &ResultNoResultSet{err: err, affectedRows: affectedRows}, docs, err
+	}
+
+	var pkValues []driver.NamedValue
 	if n := len(args); n == s.numInputs+s.numPkPaths {
 		_, _ = fmt.Fprintf(os.Stderr, "[WARN] supplying PK value at the end of parameter list is deprecated, please use WITH PK\n")
+		pkValues = make([]driver.NamedValue, s.numPkPaths)
 		copy(pkValues, args[s.numInputs:])
 		args = args[:s.numInputs]
 	} else if n == s.numInputs {
-		fieldValMap := make(map[string]interface{})
-		for i, field := range s.fields {
-			fieldValMap[field] = s.values[i]
-		}
-		for i, pkPath := range s.pkPaths {
-			v, ok := fieldValMap[pkPath[1:]]
-			if !ok {
-				return nil, fmt.Errorf("missing value for PK %s", pkPath)
-			}
-			pkValues[i] = driver.NamedValue{Name: pkPath[1:], Value: v}
+		var err error
+		if pkValues, err = s.pkValuesFromRow(s.values); err != nil {
+			return nil, nil, err
 		}
 	} else {
-		return nil, fmt.Errorf("expected %d or %d input values, got %d", s.numInputs, s.numInputs+s.numPkPaths, n)
+		return nil, nil, fmt.Errorf("expected %d or %d input values, got %d", s.numInputs, s.numInputs+s.numPkPaths, n)
+	}
+	result, doc, err := s.insertRow(s.values, pkValues, args)
+	return result, []DocInfo{doc}, err
+}
+
+// pkValuesFromRow extracts partition key values from a row.
+func (s *StmtInsert) pkValuesFromRow(row []interface{}) ([]driver.NamedValue, error) {
+	fieldValMap := make(map[string]interface{})
+	for i, field := range s.fields {
+		fieldValMap[_fieldPathToJsonPointer(field)] = row[i]
 	}
+	pkValues := make([]driver.NamedValue, s.numPkPaths)
+	for i, pkPath := range s.pkPaths {
+		v, ok := fieldValMap[pkPath]
+		if !ok {
+			return nil, fmt.Errorf("missing value for PK %s", pkPath)
+		}
+		pkValues[i] = driver.NamedValue{Name: pkPath[1:], Value: v}
+	}
+	return pkValues, nil
+}
 
+// insertRow inserts (or upserts) one row to the collection.
+func (s *StmtInsert) insertRow(row []interface{}, pkValues []driver.NamedValue, args []driver.NamedValue) (*ResultNoResultSet, DocInfo, error) {
 	spec := DocumentSpec{
 		DbName:             s.dbName,
 		CollName:           s.collName,
@@ -366,100 +754,559 @@ func (s *StmtInsert) ExecContext(_ context.Context, args []driver.NamedValue) (d
 		}
 	}
 	for i, field := range s.fields {
-		switch v := s.values[i].(type) {
+		value := row[i]
+		switch v := row[i].(type) {
 		case placeholder:
-			spec.DocumentData[field] = args[v.index-1].Value
-		default:
-			spec.DocumentData[field] = s.values[i]
+			value = args[v.index-1].Value
+		}
+		if err := _setFieldPath(spec.DocumentData, field, value); err != nil {
+			return nil, nil, err
 		}
 	}
+	if s.ttl != nil {
+		spec.DocumentData["ttl"] = *s.ttl
+	}
 	restResult := s.conn.restClient.CreateDocument(spec)
 	rid := ""
 	if restResult.DocInfo != nil {
 		rid, _ = restResult.DocInfo["_rid"].(string)
 	}
 	result := buildResultNoResultSet(&restResult.RestResponse, true, rid, 0)
-	return result, result.err
+	return result, restResult.DocInfo, result.err
 }
 
 // Query implements driver.Stmt/Query.
-// This function is not implemented, use Exec instead.
-func (s *StmtInsert) Query(_ []driver.Value) (driver.Rows, error) {
-	return nil, ErrQueryNotSupported
+// This function is only available if the statement has a RETURNING clause, use Exec otherwise.
+func (s *StmtInsert) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+// This function is only available if the statement has a RETURNING clause, use ExecContext otherwise.
+//
+// @Available since v1.2.0
+func (s *StmtInsert) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.queryReturning(s.execute, args)
 }
 
 /*----------------------------------------------------------------------*/
 
-// StmtDelete implements "DELETE" operation.
+// StmtInsertValue implements "INSERT ... VALUE" operation, which inserts a whole JSON document.
 //
 // Syntax:
 //
-//	DELETE FROM <db-name>.<collection-name>
-//	WHERE id=<id-value>
-//	[AND pk1-path=<pk1-value> [AND pk2-path=<pk2-value> ...]]
+//	INSERT|UPSERT INTO <db-name>.<collection-name>
+//	VALUE <placeholder>|'<json-document>'
+//	[WITH PK=/pk-path]
+//	[WITH TTL=<seconds>|-1]
 //
-//	- DELETE removes only one document specified by 'id'.
-//	- The clause WHERE id=<id-value> is mandatory, and 'id' is a keyword, _not_ a field name.
+//	- The document is either supplied via a placeholder (e.g. @1), or as a single-quoted JSON string (single quotes
+//	  inside the string must be escaped by doubling them).
+//	- The placeholder's value can be a map[string]interface{}, a struct (marshalled to JSON), or a JSON object in form of
+//	  json.RawMessage, []byte or string.
+//	- Partition key values are extracted from the document, nested (e.g. /address/city) and hierarchical partition keys are supported.
+//	  Using WITH PK is highly recommended to save one round-trip to server to fetch the collection's partition key info.
+//	- WITH TTL sets the "ttl" field of the document, overriding the one supplied in the document (if any).
+//
+// @Available since v1.2.0
+type StmtInsertValue struct {
+	*StmtCRUD
+	isUpsert bool
+	value    interface{} // either a placeholder or the parsed document
+	ttl      *int        // time-to-live of the inserted document, nil means the document's own or the collection's default
+}
+
+// String implements interface fmt.Stringer/String.
+func (s *StmtInsertValue) String() string {
+	return fmt.Sprintf(`StmtInsertValue{StmtCRUD: %s, upsert: %v, value: %v, ttl: %s}`, s.StmtCRUD, s.isUpsert, s.value, _ttlToString(s.ttl))
+}
+
+func (s *StmtInsertValue) parse(node *astInsertValue) error {
+	if err := s.parseWithOpts(node.withOpts()); err != nil {
+		return err
+	}
+	for k, v := range s.withOpts {
+		switch k {
+		case "PK":
+		case "TTL":
+			ttl, err := _parseTtlOpt(v, false)
+			if err != nil {
+				return err
+			}
+			s.ttl = ttl
+		default:
+			return fmt.Errorf("invalid query, parsing error at WITH %s", k)
+		}
+	}
+
+	var err error
+	switch v := node.value.(type) {
+	case placeholder:
+		s.value = v
+		s.numInputs = v.index
+	default:
+		if s.value, err = _toDocument(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StmtInsertValue) validate() error {
+	if s.dbName == "" || s.collName == "" {
+		return errors.New("database/collection is missing")
+	}
+	return nil
+}
+
+// _toDocument converts the input to a JSON document.
+//
+// @Available since v1.2.0
+func _toDocument(input interface{}) (map[string]interface{}, error) {
+	var js []byte
+	switch v := input.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case DocInfo:
+		return v, nil
+	case json.RawMessage:
+		js = v
+	case []byte:
+		js = v
+	case string:
+		js = []byte(v)
+	default:
+		var err error
+		if js, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("cannot convert %T to JSON document: %w", input, err)
+		}
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(js, &doc); err != nil || doc == nil {
+		return nil, fmt.Errorf("value is not a JSON object: %s", js)
+	}
+	return doc, nil
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtInsertValue) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtInsertValue) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	// TODO: pass ctx to REST API client
+
+	result, _, err := s.execute(args)
+	return result, err
+}
+
+// execute inserts the document and returns the inserted document.
+func (s *StmtInsertValue) execute(args []driver.NamedValue) (driver.Result, []DocInfo, error) {
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(args) != s.numInputs {
+		return nil, nil, fmt.Errorf("expected %d input values, got %d", s.numInputs, len(args))
+	}
+	if err := s.fetchPkInfo(); err != nil {
+		return nil, nil, err
+	}
+
+	var doc map[string]interface{}
+	switch v := s.value.(type) {
+	case placeholder:
+		var err error
+		if doc, err = _toDocument(args[v.index-1].Value); err != nil {
+			return nil, nil, err
+		}
+	default:
+		doc = s.value.(map[string]interface{})
+	}
+	// always clone the document: the one parsed from the query is reused across executions, and the one supplied
+	// via placeholder belongs to the caller, neither must be modified (e.g. by WITH TTL or by the auto-generated id)
+	clone := make(map[string]interface{}, len(doc)+1)
+	for k, val := range doc {
+		clone[k] = val
+	}
+	doc = clone
+	if s.ttl != nil {
+		doc["ttl"] = *s.ttl
+	}
+
+	spec := DocumentSpec{
+		DbName:             s.dbName,
+		CollName:           s.collName,
+		IsUpsert:           s.isUpsert,
+		PartitionKeyValues: _extractPkValues(doc, s.pkPaths),
+		DocumentData:       doc,
+	}
+	restResult := s.conn.restClient.CreateDocument(spec)
+	rid := ""
+	if restResult.DocInfo != nil {
+		rid, _ = restResult.DocInfo["_rid"].(string)
+	}
+	result := buildResultNoResultSet(&restResult.RestResponse, true, rid, 0)
+	return result, []DocInfo{restResult.DocInfo}, result.err
+}
+
+// Query implements driver.Stmt/Query.
+// This function is only available if the statement has a RETURNING clause, use Exec otherwise.
+func (s *StmtInsertValue) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+// This function is only available if the statement has a RETURNING clause, use ExecContext otherwise.
+func (s *StmtInsertValue) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.queryReturning(s.execute, args)
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtInsertSelect implements "INSERT ... SELECT" operation, which copies documents returned by a query to a collection.
+//
+// Syntax:
+//
+//	INSERT|UPSERT INTO <db-name>.<collection-name>
+//	SELECT ... FROM <collection/table-name> ...
+//	[WITH database|db=<source-db-name>]
+//	[WITH collection|table=<source-collection-name>]
+//	[WITH PK=/pk-path]
+//	[WITH CONCURRENCY=<n>]
+//	[WITH TTL=<seconds>|-1]
+//
+//	- The SELECT query follows the same syntax as StmtSelect and is always executed across partitions.
+//	  Source database defaults to the target database; source collection is extracted from the "FROM" clause if not specified.
+//	- Each document returned by the query must be a JSON object containing field "id". System fields (_rid, _etag, etc.) are removed
+//	  before the document is written to the target collection.
+//	- Target partition key values are extracted from the selected fields, WITH PK specifies the target collection's partition key
+//	  to save one round-trip to server.
+//	- Query results are fetched page by page, each page is written before the next one is fetched (except for DISTINCT,
+//	  GROUP BY, TOP and OFFSET...LIMIT queries whose results are fetched in one go).
+//	- Documents are written in parallel, WITH CONCURRENCY=<n> specifies how many (default 4).
+//	- WITH TTL sets the "ttl" field (time-to-live in seconds, -1 means never expire) of the written documents.
+//	- Documents are not written in a transaction: if a row fails, the returned error names the failing row, the number of
+//	  rows written and the rows not written (database/sql discards RowsAffected when Exec returns an error).
+//
+// @Available since v1.2.0
+type StmtInsertSelect struct {
+	*StmtCRUD
+	isUpsert    bool
+	source      *StmtSelect
+	concurrency int
+	ttl         *int // time-to-live of written documents, nil means the source document's own or the collection's default
+}
+
+// String implements interface fmt.Stringer/String.
+func (s *StmtInsertSelect) String() string {
+	return fmt.Sprintf(`StmtInsertSelect{StmtCRUD: %s, upsert: %v, source: %s, source_query: %q, concurrency: %d, ttl: %s}`,
+		s.StmtCRUD, s.isUpsert, s.source, s.source.selectQuery, s.concurrency, _ttlToString(s.ttl))
+}
+
+func (s *StmtInsertSelect) parse(opts []astWithOpt) error {
+	if err := s.parseWithOpts(opts); err != nil {
+		return err
+	}
+
+	s.source.withOpts = make(map[string]string)
+	for k, v := range s.withOpts {
+		switch k {
+		case "SINGLE_PK", "SINGLEPK":
+			return errors.New("WITH SINGLE_PK is not supported by INSERT ... SELECT, use WITH PK instead")
+		case "PK":
+		case "CONCURRENCY":
+			concurrency, err := _parsePositiveIntOpt(k, v)
+			if err != nil {
+				return err
+			}
+			s.concurrency = concurrency
+		case "TTL":
+			ttl, err := _parseTtlOpt(v, false)
+			if err != nil {
+				return err
+			}
+			s.ttl = ttl
+		default:
+			// other options are applied to the source query
+			s.source.withOpts[k] = v
+		}
+	}
+	if err := s.source._parse(); err != nil {
+		return err
+	}
+	s.source.isCrossPartition = true
+	s.numInputs = s.source.numInputs
+	return nil
+}
+
+func (s *StmtInsertSelect) validate() error {
+	if s.dbName == "" || s.collName == "" {
+		return errors.New("database/collection is missing")
+	}
+	if err := s.source.validate(); err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	return nil
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtInsertSelect) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtInsertSelect) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	// TODO: pass ctx to REST API client
+
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fetchPkInfo(); err != nil {
+		return nil, err
+	}
+	params, err := _buildQueryParams(s.source.placeholders, args)
+	if err != nil {
+		return nil, err
+	}
+	query := QueryReq{
+		DbName:                s.source.dbName,
+		CollName:              s.source.collName,
+		Query:                 s.source.selectQuery,
+		Params:                params,
+		CrossPartitionEnabled: true,
+	}
+	// documents are fetched and written page by page, so that the whole result set is not kept in memory;
+	// results of DISTINCT, GROUP BY, aggregate and OFFSET...LIMIT queries can not be paged reliably (each page would
+	// hold partial results of a partition key range), they are fetched in one go
+	queryPlan := s.conn.restClient.QueryPlan(query)
+	if err := queryPlan.Error(); err != nil {
+		return nil, normalizeError(queryPlan.StatusCode, 0, err)
+	}
+	paged := !queryPlan.IsDistinctQuery() && !queryPlan.IsGroupByQuery() && len(queryPlan.QueryInfo.Aggregates) == 0 &&
+		queryPlan.QueryInfo.Limit <= 0 && queryPlan.QueryInfo.Top <= 0
+	var pkranges *RespGetPkranges
+	if paged && queryPlan.needsMerge() {
+		// the query plan and partition key ranges are fetched once, not for each page
+		if pkranges = s.conn.restClient.GetPkranges(query.DbName, query.CollName); pkranges.Error() != nil {
+			return nil, normalizeError(pkranges.StatusCode, 0, pkranges.Error())
+		}
+	}
+	var affectedRows int64
+	for numRows := 0; ; {
+		var queryResult *RespQueryDocs
+		if paged {
+			query.MaxItemCount = insertSelectPageSize
+			queryResult = s.conn.restClient.queryDocumentsWithPlan(query, queryPlan, pkranges)
+		} else {
+			queryResult = s.conn.restClient.QueryDocumentsCrossPartition(query)
+		}
+		if err := queryResult.Error(); err != nil {
+			err = normalizeError(queryResult.StatusCode, 0, err)
+			return &ResultNoResultSet{err: err, affectedRows: affectedRows}, err
+		}
+		docs := queryResult.Documents.AsDocInfoSlice()
+		if docs == nil && len(queryResult.Documents) > 0 {
+			err := errors.New("SELECT query must return JSON objects")
+			return &ResultNoResultSet{err: err, affectedRows: affectedRows}, err
+		}
+		written := make([]bool, len(docs))
+		pageRows, err := _execConcurrently(len(docs), s.concurrency, func(i int) (int64, error) {
+			spec := DocumentSpec{
+				DbName:             s.dbName,
+				CollName:           s.collName,
+				IsUpsert:           s.isUpsert,
+				PartitionKeyValues: _extractPkValues(docs[i], s.pkPaths),
+				DocumentData:       docs[i].RemoveSystemAttrs(),
+			}
+			if s.ttl != nil {
+				spec.DocumentData["ttl"] = *s.ttl
+			}
+			createDocResult := s.conn.restClient.CreateDocument(spec)
+			if err := createDocResult.Error(); err != nil {
+				return 0, fmt.Errorf("row %d: %w", numRows+i+1, normalizeError(createDocResult.StatusCode, 0, err))
+			}
+			written[i] = true
+			return 1, nil
+		})
+		affectedRows += pageRows
+		if err != nil {
+			notWritten := make([]int, 0, len(docs)-int(pageRows))
+			for i := range written {
+				if !written[i] {
+					notWritten = append(notWritten, numRows+i+1)
+				}
+			}
+			err = _partialWriteError(err, affectedRows, notWritten, paged && queryResult.ContinuationToken != "")
+		}
+		numRows += len(docs)
+		if err != nil || !paged || queryResult.ContinuationToken == "" {
+			return &ResultNoResultSet{err: err, affectedRows: affectedRows}, err
+		}
+		query.ContinuationToken = queryResult.ContinuationToken
+	}
+}
+
+// Query implements driver.Stmt/Query.
+// This function is not implemented, use Exec instead.
+func (s *StmtInsertSelect) Query(_ []driver.Value) (driver.Rows, error) {
+	return nil, ErrQueryNotSupported
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtDelete implements "DELETE" operation.
+//
+// Syntax:
+//
+//	DELETE FROM <db-name>.<collection-name>
+//	WHERE id=<id-value>
+//	[AND pk1-path=<pk1-value> [AND pk2-path=<pk2-value> ...]]
+//
+//	or (since v1.2.0)
+//
+//	DELETE FROM <db-name>.<collection-name>
+//	WHERE <cosmos-sql-predicate>
+//	[WITH PK=/pk-path]
+//	[WITH CONCURRENCY=<n>]
+//	[WITH MAX_ROWS=<n>]
+//
+//	- The first form removes only one document specified by 'id'.
+//	- The clause WHERE id=<id-value> is mandatory, and 'id' is a keyword, _not_ a field name.
 //	- <id-value> and <pk-value> must be a placeholder (e.g. :1, @2 or $3), or JSON value.
 //	- Supplying pk-paths and pk-values is highly recommended to save one round-trip to server to fetch the collection's partition key info.
 //	- If collection's PK has more than one path (i.e. sub-partition is used), the partition paths must be specified in the same order as in the collection (.e.g. AND field1=value1 AND field2=value2...).
+//	- (since v1.2.0) The second form removes all documents matching the predicate, which follows Cosmos DB SQL grammar
+//	  and refers to documents via alias 'c'. The id and partition key values of matching documents are fetched via
+//	  a cross-partition query, and then documents are deleted one by one, each deletion is conditioned on the document's _etag
+//	  returned by the query. If a document is modified after being queried, the predicate is re-checked against its current
+//	  version: documents that no longer match (or have been deleted) are skipped, others are deleted (up to 5 attempts, then
+//	  the statement fails with ErrPreconditionFailure). RowsAffected returns the number of documents actually deleted.
+//	- WITH CONCURRENCY=<n> specifies how many documents are deleted in parallel (default 4).
+//	- WITH MAX_ROWS=<n> is a safety cap: if more than <n> documents match the predicate, the statement fails without deleting anything.
+//	- (since v1.2.0) the first form accepts the expected etag of the document, either as "AND _etag=<etag-value>" or "WITH IF_MATCH=<etag-value>".
+//	  If the document's current etag does not match, the statement fails with ErrPreconditionFailure.
 //
 // See StmtInsert for details on <id-value> and <pk-value>.
 type StmtDelete struct {
 	*StmtCRUD
-	whereStr string
-	id       interface{}
-	pkValues []interface{}
+	whereStr          string
+	id                interface{}
+	pkValues          []interface{}
+	etag              interface{}    // (since v1.2.0) expected etag of the document, sent as If-Match header
+	whereQuery        string         // (multi-document mode) Cosmos DB SQL predicate, placeholders rewritten to named parameters
+	wherePlaceholders map[int]string // (multi-document mode) placeholder index -> parameter name
+	concurrency       int            // (multi-document mode) number of documents to be deleted in parallel
+	maxRows           int            // (multi-document mode) max number of documents allowed to be deleted, 0 means no limit
 }
 
 // String implements interface fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtDelete) String() string {
-	return fmt.Sprintf(`StmtDelete{StmtCRUD: %s, where_clause: %q, id: %v, pk_values: %v}`,
-		s.StmtCRUD, s.whereStr, s.id, s.pkValues)
+	return fmt.Sprintf(`StmtDelete{StmtCRUD: %s, where_clause: %q, id: %v, pk_values: %v, etag: %v, where_query: %q, concurrency: %d, max_rows: %d}`,
+		s.StmtCRUD, s.whereStr, s.id, s.pkValues, s.etag, s.whereQuery, s.concurrency, s.maxRows)
 }
 
-func (s *StmtDelete) parse(withOptsStr string) error {
-	if err := s.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtDelete) parse(node *astDelete) error {
+	if err := s.parseWithOpts(node.withOpts()); err != nil {
 		return err
 	}
 
-	for k := range s.withOpts {
-		if k != "SINGLE_PK" && k != "SINGLEPK" {
+	var ifMatch interface{}
+	for k, v := range s.withOpts {
+		switch k {
+		case "SINGLE_PK", "SINGLEPK", "PK":
+		case "IF_MATCH":
+			var err error
+			if ifMatch, err = _parseIfMatchOpt(v, s.paramNames); err != nil {
+				return err
+			}
+		case "CONCURRENCY":
+			concurrency, err := _parsePositiveIntOpt(k, v)
+			if err != nil {
+				return err
+			}
+			s.concurrency = concurrency
+		case "MAX_ROWS", "MAXROWS":
+			maxRows, err := _parsePositiveIntOpt(k, v)
+			if err != nil {
+				return err
+			}
+			s.maxRows = maxRows
+		default:
 			return fmt.Errorf("invalid query, parsing error at WITH %s", k)
 		}
 	}
 
+	if node.where.terms == nil {
+		// the WHERE clause is a Cosmos DB SQL predicate: multi-document delete
+		if s.isSinglePathPk {
+			return errors.New("WITH SINGLE_PK is not supported when WHERE clause is a predicate, use WITH PK instead")
+		}
+		if ifMatch != nil {
+			return errors.New("WITH IF_MATCH is not supported when WHERE clause is a predicate")
+		}
+		s.whereQuery, s.wherePlaceholders, s.numInputs = node.where.predicate, node.where.placeholders, node.where.maxIndex
+		s.pkPaths, s.numPkPaths = nil, 0
+		if s.withPk != "" {
+			s.pkPaths = strings.Split(s.withPk, ",")
+			s.numPkPaths = len(s.pkPaths)
+		}
+		return nil
+	}
+	if s.withPk != "" || s.concurrency > 0 || s.maxRows > 0 {
+		return errors.New("WITH PK/CONCURRENCY/MAX_ROWS is only supported when WHERE clause is a predicate")
+	}
+	s._parseWhereIdPk(node.where.terms)
+	return s.setIfMatch(ifMatch)
+}
+
+// setIfMatch sets the expected etag supplied via WITH IF_MATCH.
+//
+// @Available since v1.2.0
+func (s *StmtDelete) setIfMatch(ifMatch interface{}) error {
+	if ifMatch == nil {
+		return nil
+	}
+	if s.etag != nil {
+		return errors.New("expected etag is specified more than once, use either _etag=<value> or WITH IF_MATCH")
+	}
+	s.etag = ifMatch
+	if p, ok := ifMatch.(placeholder); ok {
+		s.numInputs = g18.Max(s.numInputs, p.index)
+	}
+	return nil
+}
+
+// _parseWhereIdPk extracts id, partition key and etag values from the "WHERE id=<id-value> [AND pk-path=<pk-value>...]" clause.
+func (s *StmtDelete) _parseWhereIdPk(terms []astWhereTerm) {
+	s.numInputs = 0
 	s.pkPaths = make([]string, 0)
 	s.pkValues = make([]interface{}, 0)
-	for temp := strings.TrimSpace(s.whereStr); temp != ""; temp = strings.TrimSpace(temp) {
-		pkPath, pkValue, leftOver, err := _parseFieldValueAnd(temp)
-		if err == nil {
-			if strings.ToLower(pkPath) == "id" {
-				s.id = pkValue
-			} else {
-				s.pkPaths = append(s.pkPaths, "/"+strings.TrimLeft(pkPath, "/"))
-				s.pkValues = append(s.pkValues, pkValue)
-			}
-			temp = leftOver
-			switch v := pkValue.(type) {
-			case placeholder:
-				s.numInputs = g18.Max(s.numInputs, v.index)
-			}
-			continue
+	for _, term := range terms {
+		if strings.ToLower(term.field) == "id" {
+			s.id = term.value
+		} else if strings.ToLower(term.field) == "_etag" {
+			s.etag = term.value
+		} else {
+			s.pkPaths = append(s.pkPaths, "/"+strings.TrimLeft(term.field, "/"))
+			s.pkValues = append(s.pkValues, term.value)
+		}
+		switch v := term.value.(type) {
+		case placeholder:
+			s.numInputs = g18.Max(s.numInputs, v.index)
 		}
-		return err
 	}
 	if !s.isSinglePathPk {
 		s.numPkPaths = len(s.pkPaths)
 	}
-
-	return nil
 }
 
 func (s *StmtDelete) validate() error {
-	if s.id == nil {
+	if s.id == nil && s.whereQuery == "" {
 		return errors.New("id value is missing")
 	}
 	if s.dbName == "" || s.collName == "" {
@@ -482,8 +1329,21 @@ func (s *StmtDelete) Exec(args []driver.Value) (driver.Result, error) {
 func (s *StmtDelete) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
 	// TODO: pass ctx to REST API client
 
+	result, _, err := s.execute(args)
+	return result, err
+}
+
+// execute deletes the document(s) and returns the deleted documents (only if the statement has a RETURNING clause).
+func (s *StmtDelete) execute(args []driver.NamedValue) (driver.Result, []DocInfo, error) {
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
 	if err := s.fetchPkInfo(); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if s.whereQuery != "" {
+		return s.execMultiDocs(args)
 	}
 
 	pkValues := make([]driver.NamedValue, s.numPkPaths)
@@ -496,7 +1356,7 @@ func (s *StmtDelete) ExecContext(_ context.Context, args []driver.NamedValue) (d
 			pkValues[i] = driver.NamedValue{Name: s.pkPaths[i][1:], Value: pkValue}
 		}
 	} else {
-		return nil, fmt.Errorf("expected %d or %d input values, got %d", s.numInputs, s.numInputs+s.numPkPaths, n)
+		return nil, nil, fmt.Errorf("expected %d or %d input values, got %d", s.numInputs, s.numInputs+s.numPkPaths, n)
 	}
 
 	id := s.id
@@ -522,22 +1382,226 @@ func (s *StmtDelete) ExecContext(_ context.Context, args []driver.NamedValue) (d
 		}
 	}
 
-	restResult := s.conn.restClient.DeleteDocument(docReq)
-	result := buildResultNoResultSet(&restResult.RestResponse, false, "", 0)
-	switch restResult.StatusCode {
-	case 404:
-		// consider "document not found" as successful operation
-		// but database/collection not found is not!
-		if strings.Contains(fmt.Sprintf("%s", restResult.Error()), "ResourceType: Document") {
-			result.err = nil
+	expectedEtag := _resolveEtag(s.etag, args)
+	docReq.MatchEtag = expectedEtag
+	for attempt := 1; ; attempt++ {
+		var deletedDoc DocInfo
+		if s.returning != nil {
+			// fetch the pre-delete image, then delete the document only if it has not been modified in the meantime
+			getDocResult := s.conn.restClient.GetDocument(docReq)
+			if err := getDocResult.Error(); err != nil {
+				result := buildResultNoResultSet(&getDocResult.RestResponse, false, "", 0)
+				if getDocResult.StatusCode == 404 && strings.Contains(fmt.Sprintf("%s", err), "ResourceType: Document") {
+					result.err = nil
+				}
+				return result, nil, result.err
+			}
+			deletedDoc = getDocResult.DocInfo
+			if expectedEtag == "" {
+				docReq.MatchEtag = deletedDoc.Etag()
+			}
+		}
+
+		restResult := s.conn.restClient.DeleteDocument(docReq)
+		if restResult.StatusCode == 412 && expectedEtag == "" && attempt < maxReplaceAttempts {
+			// the document was modified after its pre-delete image had been fetched: fetch it again, as a plain DELETE
+			// would not fail either (etag mismatch is reported only if the expected etag is supplied)
+			continue
+		}
+		result := buildResultNoResultSet(&restResult.RestResponse, false, "", 0)
+		switch restResult.StatusCode {
+		case 404:
+			// consider "document not found" as successful operation
+			// but database/collection not found is not!
+			if strings.Contains(fmt.Sprintf("%s", restResult.Error()), "ResourceType: Document") {
+				result.err = nil
+			}
 		}
+		if result.affectedRows == 0 {
+			deletedDoc = nil
+		}
+		return result, []DocInfo{deletedDoc}, result.err
+	}
+}
+
+// execMultiDocs deletes all documents matching the WHERE predicate.
+//
+// @Available since v1.2.0
+func (s *StmtDelete) execMultiDocs(args []driver.NamedValue) (driver.Result, []DocInfo, error) {
+	if len(args) != s.numInputs {
+		return nil, nil, fmt.Errorf("expected %d input values, got %d", s.numInputs, len(args))
+	}
+	params, err := _buildQueryParams(s.wherePlaceholders, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	// only id, _etag and partition key values of matching documents are fetched, unless the pre-delete images are to be returned
+	projection := "*"
+	if s.returning == nil {
+		projection = _idPkProjection(s.pkPaths)
+	}
+	query := QueryReq{
+		DbName:                s.dbName,
+		CollName:              s.collName,
+		Query:                 "SELECT " + projection + " FROM c WHERE " + s.whereQuery,
+		Params:                params,
+		CrossPartitionEnabled: true,
+	}
+	queryResult := s.conn.restClient.QueryDocumentsCrossPartition(query)
+	if err := queryResult.Error(); err != nil {
+		return nil, nil, normalizeError(queryResult.StatusCode, 0, err)
+	}
+
+	docs := queryResult.Documents.AsDocInfoSlice()
+	if s.maxRows > 0 && len(docs) > s.maxRows {
+		return nil, nil, fmt.Errorf("%d documents matched, exceeding MAX_ROWS=%d", len(docs), s.maxRows)
+	}
+	deletedDocs := make([]DocInfo, len(docs))
+	affectedRows, err := _execConcurrently(len(docs), s.concurrency, func(i int) (int64, error) {
+		docReq := DocReq{
+			DbName:             s.dbName,
+			CollName:           s.collName,
+			DocId:              docs[i].Id(),
+			PartitionKeyValues: _projectedPkValues(docs[i], len(s.pkPaths)),
+		}
+		if s.returning != nil {
+			docReq.PartitionKeyValues = _extractPkValues(docs[i], s.pkPaths)
+		}
+		doc := docs[i]
+		for attempt := 1; ; attempt++ {
+			// the deletion is conditioned on the _etag returned by the query, so that a document modified to no longer
+			// match the predicate is not deleted (and the returned pre-delete image is the deleted one)
+			docReq.MatchEtag = doc.Etag()
+			deleteDocResult := s.conn.restClient.DeleteDocument(docReq)
+			switch deleteDocResult.StatusCode {
+			case 404:
+				// document has been deleted since being queried, skip it
+				return 0, nil
+			case 412:
+				// document has been modified since being queried: re-check the predicate against its current version
+				if attempt >= maxReplaceAttempts {
+					return 0, ErrPreconditionFailure
+				}
+				var err error
+				if doc, err = s.requeryDoc(projection, s.whereQuery, params, docs[i].Id(), docReq.PartitionKeyValues); err != nil || doc == nil {
+					return 0, err
+				}
+				continue
+			}
+			if err := deleteDocResult.Error(); err != nil {
+				return 0, normalizeError(deleteDocResult.StatusCode, 0, err)
+			}
+			deletedDocs[i] = doc
+			return 1, nil
+		}
+	})
+	return &ResultNoResultSet{err: err, affectedRows: affectedRows}, deletedDocs, err
+}
+
+// Query implements driver.Stmt/Query.
+// This function is only available if the statement has a RETURNING clause, use Exec otherwise.
+func (s *StmtDelete) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+// This function is only available if the statement has a RETURNING clause, use ExecContext otherwise.
+//
+// @Available since v1.2.0
+func (s *StmtDelete) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.queryReturning(s.execute, args)
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtDeleteAll implements "DELETE ALL" operation, which removes all documents of a logical partition.
+//
+// Syntax:
+//
+//	DELETE ALL FROM <db-name>.<collection-name>
+//	WHERE pk1-path=<pk1-value> [AND pk2-path=<pk2-value> ...]
+//
+//	- The deletion is carried out by the server in the background, no document is streamed to the client.
+//	- All partition key paths of the collection must be specified, in the same order as in the collection.
+//	- <pk-value> must be a placeholder (e.g. :1, @2 or $3), or JSON value.
+//	- The number of deleted documents is not known upon execution, RowsAffected returns ErrOperationNotSupported.
+//
+// @Available since v1.2.0
+type StmtDeleteAll struct {
+	*StmtCRUD
+	whereStr string
+	pkValues []interface{}
+}
+
+// String implements interface fmt.Stringer/String.
+func (s *StmtDeleteAll) String() string {
+	return fmt.Sprintf(`StmtDeleteAll{StmtCRUD: %s, where_clause: %q, pk_values: %v}`, s.StmtCRUD, s.whereStr, s.pkValues)
+}
+
+func (s *StmtDeleteAll) parse(where *astWhere) error {
+	s.pkPaths = make([]string, 0)
+	s.pkValues = make([]interface{}, 0)
+	for _, term := range where.terms {
+		if strings.ToLower(term.field) == "id" {
+			return errors.New("id is not allowed in WHERE clause of DELETE ALL, only partition key values are")
+		}
+		s.pkPaths = append(s.pkPaths, "/"+strings.TrimLeft(term.field, "/"))
+		s.pkValues = append(s.pkValues, term.value)
+		switch v := term.value.(type) {
+		case placeholder:
+			s.numInputs = g18.Max(s.numInputs, v.index)
+		}
+	}
+	s.numPkPaths = len(s.pkPaths)
+	return nil
+}
+
+func (s *StmtDeleteAll) validate() error {
+	if s.numPkPaths == 0 {
+		return errors.New("partition key value is missing")
+	}
+	if s.dbName == "" || s.collName == "" {
+		return errors.New("database/collection is missing")
 	}
+	return nil
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtDeleteAll) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtDeleteAll) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	// TODO: pass ctx to REST API client
+
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != s.numInputs {
+		return nil, fmt.Errorf("expected %d input values, got %d", s.numInputs, len(args))
+	}
+	pkValues := make([]interface{}, len(s.pkValues))
+	for i, pkValue := range s.pkValues {
+		switch v := pkValue.(type) {
+		case placeholder:
+			pkValues[i] = args[v.index-1].Value
+		default:
+			pkValues[i] = v
+		}
+	}
+
+	restResult := s.conn.restClient.DeleteAllItemsByPartitionKey(s.dbName, s.collName, pkValues)
+	result := buildResultNoResultSet(&restResult.RestResponse, false, "", 0)
+	// documents are deleted by the server in the background, the number of deleted documents is not known
+	result.affectedRows, result.unknownAffectedRows = 0, true
 	return result, result.err
 }
 
 // Query implements driver.Stmt/Query.
 // This function is not implemented, use Exec instead.
-func (s *StmtDelete) Query(_ []driver.Value) (driver.Rows, error) {
+func (s *StmtDeleteAll) Query(_ []driver.Value) (driver.Rows, error) {
 	return nil, ErrQueryNotSupported
 }
 
@@ -560,6 +1624,8 @@ func (s *StmtDelete) Query(_ []driver.Value) (driver.Rows, error) {
 //	- (extension) Use "WITH collection=<coll-name>" (or "WITH table=<coll-name>") to specify the collection/table on which the query is to be executed.
 //	  If not specified, collection/table name is extracted from the "FROM <collection/table-name>" clause.
 //	- (extension) Use placeholder syntax @i, $i or :i (where i denotes the i-th parameter, the first parameter is 1)
+//	- (since v1.2.0) A query in form "SELECT * FROM c WHERE c.id=<value> AND c.<pk-path>=<value>", filtering on id and the full partition key,
+//	  is executed as a point read.
 type StmtSelect struct {
 	*Stmt
 	isCrossPartition bool
@@ -567,21 +1633,29 @@ type StmtSelect struct {
 	collName         string
 	selectQuery      string
 	placeholders     map[int]string
+	alias            string                 // (since v1.2.0) alias referring to documents of the collection, e.g. "u" in "FROM users u"
+	pkFilters        map[string]interface{} // (since v1.2.0) "<alias>.<path>=<value>" predicates of the WHERE clause, path -> literal value or placeholder
+	pointRead        bool                   // (since v1.2.0) true if the query selects whole documents filtered only by pkFilters, see tryPointRead
+	pkPaths          []string               // (since v1.2.0) partition key paths of the collection, see RestClient.collectionPkPaths
 }
 
 // String implements interface fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtSelect) String() string {
-	return fmt.Sprintf(`StmtSelect{Stmt: %s, cross_partition: %v, db: %q, collection: %q}`,
-		s.Stmt, s.isCrossPartition, s.dbName, s.collName)
+	return fmt.Sprintf(`StmtSelect{Stmt: %s, cross_partition: %v, db: %q, collection: %q, alias: %q, pk_filters: %v, point_read: %v}`,
+		s.Stmt, s.isCrossPartition, s.dbName, s.collName, s.alias, s.pkFilters, s.pointRead)
 }
 
-func (s *StmtSelect) parse(withOptsStr string) error {
-	if err := s.parseWithOpts(withOptsStr); err != nil {
+func (s *StmtSelect) parse(opts []astWithOpt) error {
+	if err := s.parseWithOpts(opts); err != nil {
 		return err
 	}
+	return s._parse()
+}
 
+// _parse applies the parsed WITH options.
+func (s *StmtSelect) _parse() error {
 	if err := s.onlyOneWithOption("database is specified more than once, only one of DATABASE or DB should be specified", "DATABASE", "DB"); err != nil {
 		return err
 	}
@@ -608,74 +1682,342 @@ func (s *StmtSelect) parse(withOptsStr string) error {
 				}
 				s.isCrossPartition = true
 			}
-		default:
-			return fmt.Errorf("invalid query, parsing error at WITH %s", k)
+		default:
+			return fmt.Errorf("invalid query, parsing error at WITH %s", k)
+		}
+	}
+	return nil
+}
+
+func (s *StmtSelect) validate() error {
+	if s.dbName == "" || s.collName == "" {
+		return errors.New("database/collection is missing")
+	}
+	return nil
+}
+
+// Query implements driver.Stmt/Query.
+func (s *StmtSelect) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+//
+// @Available since v1.1.0
+func (s *StmtSelect) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	// TODO: pass ctx to REST API client
+
+	args, queryMetrics, indexMetrics, err := _extractMetricsArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	args, err = s.bindArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	query, err := s.buildQueryReq(args)
+	if err != nil {
+		return nil, err
+	}
+	var pointReadErr error
+	if s.pointRead && queryMetrics == nil && indexMetrics == nil {
+		var result driver.Rows
+		var ok bool
+		if result, ok, pointReadErr = s.tryPointRead(args); ok {
+			return result, pointReadErr
+		}
+	}
+
+	query.PopulateIndexMetrics = indexMetrics != nil
+	restResult := s.conn.restClient.QueryDocumentsCrossPartition(query)
+	result := &ResultResultSet{err: restResult.Error(), columnList: make([]string, 0)}
+	if result.err == nil {
+		if pointReadErr == errPointReadNotFound && len(restResult.Documents) > 0 {
+			// the query found the document the point read did not: the cached partition key paths are stale
+			s.forgetPkPaths()
+		}
+		result.documents = restResult.Documents
+		result.init()
+		if queryMetrics != nil && restResult.QueryMetrics != nil {
+			*queryMetrics = *restResult.QueryMetrics
+		}
+		if indexMetrics != nil && restResult.IndexMetrics != nil {
+			*indexMetrics = *restResult.IndexMetrics
+		}
+	}
+	result.err = normalizeError(restResult.StatusCode, 0, result.err)
+	return result, result.err
+}
+
+// _extractMetricsArgs separates the sql.Out arguments receiving query metrics (*QueryMetrics) and index metrics
+// (*IndexMetrics) from the arguments to be bound to the query.
+//
+// @Available since v1.2.0
+func _extractMetricsArgs(args []driver.NamedValue) ([]driver.NamedValue, *QueryMetrics, *IndexMetrics, error) {
+	var queryMetrics *QueryMetrics
+	var indexMetrics *IndexMetrics
+	result := make([]driver.NamedValue, 0, len(args))
+	for _, arg := range args {
+		out, ok := arg.Value.(sql.Out)
+		if !ok {
+			result = append(result, arg)
+			continue
+		}
+		switch dest := out.Dest.(type) {
+		case *QueryMetrics:
+			queryMetrics = dest
+		case *IndexMetrics:
+			indexMetrics = dest
+		default:
+			return nil, nil, nil, fmt.Errorf("unsupported output argument #%d of type %T, expecting *QueryMetrics or *IndexMetrics", arg.Ordinal, out.Dest)
+		}
+	}
+	return result, queryMetrics, indexMetrics, nil
+}
+
+// buildQueryReq builds the request to execute the query with the supplied (bound) arguments.
+//
+// @Available since v1.2.0
+func (s *StmtSelect) buildQueryReq(args []driver.NamedValue) (QueryReq, error) {
+	params := make([]interface{}, 0)
+	for i, arg := range args {
+		v, ok := s.placeholders[i+1]
+		if !ok {
+			return QueryReq{}, fmt.Errorf("there is no placeholder #%d", i+1)
+		}
+		params = append(params, map[string]interface{}{"name": v, "value": arg.Value})
+	}
+	return QueryReq{
+		DbName:                s.dbName,
+		CollName:              s.collName,
+		Query:                 s.selectQuery,
+		Params:                params,
+		CrossPartitionEnabled: s.isCrossPartition,
+	}, nil
+}
+
+// tryPointRead executes the query as a single GetDocument call if it filters on id and the full partition key of the collection,
+// e.g. "SELECT * FROM c WHERE c.id=@1 AND c.pk=@2", which costs less than executing the query.
+// It returns false if the query is not eligible, in which case the query should be executed as usual. It also returns
+// false, along with errPointReadNotFound, if the point read found no document: as the partition key paths are cached,
+// "not found" is confirmed by executing the query.
+//
+// @Available since v1.2.0
+func (s *StmtSelect) tryPointRead(args []driver.NamedValue) (driver.Rows, bool, error) {
+	if s.pkPaths == nil {
+		// the partition key paths are cached by the REST client, as database/sql prepares a new statement for each query
+		pkPaths, ok := s.conn.restClient.collectionPkPaths(s.dbName, s.collName)
+		if !ok {
+			// the partition key info is not available, the query reports the error (if any) when executed as usual
+			return nil, false, nil
+		}
+		s.pkPaths = pkPaths
+	}
+	numPaths := len(s.pkPaths)
+	if _, ok := s.pkFilters["/id"]; ok && g18.FindInSlice("/id", s.pkPaths) < 0 {
+		numPaths++
+	}
+	if len(s.pkPaths) == 0 || len(s.pkFilters) != numPaths {
+		return nil, false, nil
+	}
+	valueOf := func(path string) (interface{}, bool) {
+		v, ok := s.pkFilters[path]
+		if p, isPlaceholder := v.(placeholder); isPlaceholder {
+			if p.index > len(args) {
+				return nil, false
+			}
+			v = args[p.index-1].Value
 		}
+		return v, ok
 	}
-
-	matches := reValPlaceholder.FindAllStringSubmatch(s.selectQuery, -1)
-	s.numInputs = len(matches)
-	s.placeholders = make(map[int]string)
-	for _, match := range matches {
-		v, _ := strconv.Atoi(match[1])
-		key := "@_" + match[1]
-		s.placeholders[v] = key
-		if strings.HasSuffix(match[0], " ") {
-			key += " "
+	id, ok := valueOf("/id")
+	if _, isString := id.(string); !ok || !isString {
+		// documents' id is always a string, a query comparing it with a non-string value returns nothing
+		return nil, false, nil
+	}
+	pkValues := make([]interface{}, len(s.pkPaths))
+	for i, pkPath := range s.pkPaths {
+		if pkValues[i], ok = valueOf(pkPath); !ok {
+			return nil, false, nil
 		}
-		s.selectQuery = strings.ReplaceAll(s.selectQuery, match[0], key)
 	}
 
-	return nil
+	getResult := s.conn.restClient.GetDocument(DocReq{DbName: s.dbName, CollName: s.collName, DocId: id.(string), PartitionKeyValues: pkValues})
+	switch {
+	case getResult.StatusCode == 400:
+		// the cached partition key paths are stale (e.g. the collection has been re-created with a different partition
+		// key by another client): they are re-fetched next time, the query is executed as usual
+		s.forgetPkPaths()
+		return nil, false, nil
+	case getResult.StatusCode == 404 && strings.Contains(fmt.Sprintf("%s", getResult.Error()), "ResourceType: Document"):
+		// "document not found" is not authoritative, as the cached partition key paths may be stale:
+		// the query is executed as usual, see QueryContext
+		return nil, false, errPointReadNotFound
+	}
+	result := &ResultResultSet{err: normalizeError(getResult.StatusCode, 0, getResult.Error()), columnList: make([]string, 0), documents: QueriedDocs{}}
+	if result.err != nil {
+		// database/collection not found is an error
+		return nil, true, result.err
+	}
+	result.documents = QueriedDocs{map[string]interface{}(getResult.DocInfo)}
+	result.init()
+	return result, true, nil
 }
 
-func (s *StmtSelect) validate() error {
-	if s.dbName == "" || s.collName == "" {
-		return errors.New("database/collection is missing")
-	}
-	return nil
+// errPointReadNotFound is returned by StmtSelect.tryPointRead if the point read found no document.
+var errPointReadNotFound = errors.New("point read: document not found")
+
+// forgetPkPaths discards the partition key paths cached for point reads, so that they are re-fetched next time.
+//
+// @Available since v1.2.0
+func (s *StmtSelect) forgetPkPaths() {
+	s.conn.restClient.forgetPkPaths(s.dbName, s.collName)
+	s.pkPaths = nil
+}
+
+// Exec implements driver.Stmt/Exec.
+// This function is not implemented, use Query instead.
+func (s *StmtSelect) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, ErrExecNotSupported
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtExplain implements "EXPLAIN SELECT" statement, which returns the plan to execute the query instead of the query's result.
+//
+// Syntax:
+//
+//	EXPLAIN SELECT [CROSS PARTITION] ... FROM <collection/table-name> ...
+//	WITH database|db=<db-name>
+//	[WITH collection|table=<collection/table-name>]
+//	[WITH cross_partition|CrossPartition[=true]]
+//
+// The result set has exactly one row, with the following columns:
+//   - query: the query sent to the server.
+//   - rewritten_query: the query rewritten by the server to be executed on each partition key range, empty if not rewritten.
+//   - distinct_type: None, Ordered or Unordered.
+//   - top, offset, limit: values of TOP and OFFSET...LIMIT clauses, 0 if not specified.
+//   - order_by: ORDER BY expressions, each followed by ASC or DESC.
+//   - group_by: GROUP BY expressions.
+//   - aggregates: aggregate functions in the projection, e.g. Count or Sum.
+//   - has_select_value: true if the query is "SELECT VALUE ...".
+//   - num_pkranges: number of partition key ranges to be queried.
+//   - client_merge: true if results from the partition key ranges must be merged by the client (ordering, grouping, aggregation, DISTINCT or TOP/OFFSET...LIMIT).
+//
+// @Available since v1.2.0
+type StmtExplain struct {
+	*Stmt
+	source *StmtSelect
+}
+
+// String implements interface fmt.Stringer/String.
+func (s *StmtExplain) String() string {
+	return fmt.Sprintf(`StmtExplain{Stmt: %s, source: %s}`, s.Stmt, s.source)
+}
+
+func (s *StmtExplain) validate() error {
+	return s.source.validate()
 }
 
 // Query implements driver.Stmt/Query.
-func (s *StmtSelect) Query(args []driver.Value) (driver.Rows, error) {
+func (s *StmtExplain) Query(args []driver.Value) (driver.Rows, error) {
 	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
 }
 
+// explainColumns lists the columns of the result set of EXPLAIN statement.
+var explainColumns = []string{"query", "rewritten_query", "distinct_type", "top", "offset", "limit", "order_by", "group_by", "aggregates",
+	"has_select_value", "num_pkranges", "client_merge"}
+
 // QueryContext implements driver.StmtQueryContext/QueryContext.
-//
-// @Available since v1.1.0
-func (s *StmtSelect) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+func (s *StmtExplain) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	// TODO: pass ctx to REST API client
 
-	params := make([]interface{}, 0)
-	for i, arg := range args {
-		v, ok := s.placeholders[i+1]
-		if !ok {
-			return nil, fmt.Errorf("there is no placeholder #%d", i+1)
-		}
-		params = append(params, map[string]interface{}{"name": v, "value": arg.Value})
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, err
 	}
-	query := QueryReq{
-		DbName:                s.dbName,
-		CollName:              s.collName,
-		Query:                 s.selectQuery,
-		Params:                params,
-		CrossPartitionEnabled: s.isCrossPartition,
+	query, err := s.source.buildQueryReq(args)
+	if err != nil {
+		return nil, err
+	}
+	query.CrossPartitionEnabled = true
+	queryPlan := s.conn.restClient.QueryPlan(query)
+	if err := queryPlan.Error(); err != nil {
+		return nil, normalizeError(queryPlan.StatusCode, 0, err)
 	}
+	pkranges := s.conn.restClient.GetPkranges(query.DbName, query.CollName)
+	if err := pkranges.Error(); err != nil {
+		return nil, normalizeError(pkranges.StatusCode, 0, err)
+	}
+	row := _explainQueryPlan(queryPlan, _countQueriedPkranges(queryPlan.QueryRanges, pkranges.Pkranges))
+	row["query"] = query.Query
+	return &ResultResultSet{rows: []DocInfo{row}, count: 1, columnList: explainColumns}, nil
+}
 
-	restResult := s.conn.restClient.QueryDocumentsCrossPartition(query)
-	result := &ResultResultSet{err: restResult.Error(), columnList: make([]string, 0)}
-	if result.err == nil {
-		result.documents = restResult.Documents
-		result.init()
+// _countQueriedPkranges returns the number of partition key ranges overlapping the query ranges of a query plan,
+// i.e. the number of partition key ranges to be queried. A query plan without query ranges targets all of them.
+//
+// @Available since v1.2.0
+func _countQueriedPkranges(queryRanges []QueryRange, pkranges []PkrangeInfo) int {
+	if len(queryRanges) == 0 {
+		return len(pkranges)
+	}
+	count := 0
+	for _, pkrange := range pkranges {
+		for _, queryRange := range queryRanges {
+			if queryRange.overlaps(pkrange) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// _explainQueryPlan describes the query plan as a row of EXPLAIN statement's result set (column "query" is not populated).
+//
+// @Available since v1.2.0
+func _explainQueryPlan(queryPlan *RespQueryPlan, numPkranges int) DocInfo {
+	info := queryPlan.QueryInfo
+	orderBy := make([]string, len(info.OrderByExpressions))
+	for i, expr := range info.OrderByExpressions {
+		orderBy[i] = expr + " ASC"
+		if i < len(info.OrderBy) && strings.EqualFold(info.OrderBy[i], "Descending") {
+			orderBy[i] = expr + " DESC"
+		}
+	}
+	groupBy := append(make([]string, 0, len(info.GroupByExpressions)), info.GroupByExpressions...)
+	aggregates := append(make([]string, 0, len(info.Aggregates)), info.Aggregates...)
+	for _, aggregate := range info.GroupByAliasToAggregateType {
+		if aggregate != "" && g18.FindInSlice(aggregate, aggregates) < 0 {
+			aggregates = append(aggregates, aggregate)
+		}
+	}
+	sort.Strings(aggregates[len(info.Aggregates):])
+	distinctType := info.DistinctType
+	if distinctType == "" {
+		distinctType = "None"
+	}
+	needMerge := !strings.EqualFold(distinctType, "None") || queryPlan.IsGroupByQuery() || queryPlan.IsOrderByQuery() || len(aggregates) > 0 ||
+		info.Top > 0 || info.Offset > 0 || info.Limit > 0 || info.RewrittenQuery != ""
+	return DocInfo{
+		"rewritten_query":  info.RewrittenQuery,
+		"distinct_type":    distinctType,
+		"top":              info.Top,
+		"offset":           info.Offset,
+		"limit":            info.Limit,
+		"order_by":         orderBy,
+		"group_by":         groupBy,
+		"aggregates":       aggregates,
+		"has_select_value": info.HasSelectValue,
+		"num_pkranges":     numPkranges,
+		"client_merge":     numPkranges > 1 && needMerge,
 	}
-	result.err = normalizeError(restResult.StatusCode, 0, result.err)
-	return result, result.err
 }
 
 // Exec implements driver.Stmt/Exec.
 // This function is not implemented, use Query instead.
-func (s *StmtSelect) Exec(_ []driver.Value) (driver.Result, error) {
+func (s *StmtExplain) Exec(_ []driver.Value) (driver.Result, error) {
 	return nil, ErrExecNotSupported
 }
 
@@ -690,108 +2032,218 @@ func (s *StmtSelect) Exec(_ []driver.Value) (driver.Result, error) {
 //	WHERE id=<id-value>
 //	[AND pk1-path=<pk1-value> [AND pk2-path=<pk2-value> ...]]
 //
-//	- UPDATE modifies only one document specified by 'id'.
+//	or (since v1.2.0)
+//
+//	UPDATE <db-name>.<collection-name>
+//	SET <field-name1>=<value1>[,<field-nameN>=<valueN>]*
+//	WHERE <cosmos-sql-predicate>
+//	[WITH PK=/pk-path]
+//	[WITH CONCURRENCY=<n>]
+//
+//	- The first form modifies only one document specified by 'id'.
 //	- The clause WHERE id=<id-value> is mandatory, and 'id' is a keyword, _not_ a field name.
 //	- <id-value> and <pk-value> must be a placeholder (e.g. :1, @2 or $3), or JSON value.
 //	- Supplying pk-paths and pk-values is highly recommended to save one round-trip to server to fetch the collection's partition key info.
 //	- If collection's PK has more than one path (i.e. sub-partition is used), the partition paths must be specified in the same order as in the collection (.e.g. AND field1=value1 AND field2=value2...).
 //
+//	- (since v1.2.0) The second form modifies all documents matching the predicate. The predicate follows Cosmos DB SQL grammar
+//	  and refers to documents via alias 'c', e.g. WHERE c.createdAt < @1 AND c.status != "archived".
+//	  Matching documents are fetched via a cross-partition query and then replaced one by one, each replacement is
+//	  conditioned on the document's _etag returned by the query. If a document is modified after being queried, the predicate
+//	  is re-checked against its current version: documents that no longer match (or have been deleted) are skipped, others
+//	  are updated again (up to 5 attempts, then the statement fails with ErrPreconditionFailure).
+//	  RowsAffected returns the number of documents actually updated.
+//	- WITH CONCURRENCY=<n> specifies how many documents are replaced in parallel (default 4).
+//
+//	- (since v1.2.0) a field can be a nested path, e.g. address.city or tags[0]. If the SET clause has at most
+//	  MaxPatchOperations fields, documents are updated via the Patch API ("set" operations).
+//	  Otherwise, or if the Patch API rejects the operations (e.g. a parent object does not exist, or an array element is
+//	  appended via tags[<length>]), documents are fetched, modified and replaced as a whole, creating missing parents as needed.
+//	  The fetch-modify-replace is retried if the document is modified concurrently (up to 5 attempts, then the statement
+//	  fails with ErrPreconditionFailure).
+//	- (since v1.2.0) besides <field>=<value>, the SET clause accepts <field>=<field>+<number> (or -<number>) and
+//	  <field>=ARRAY_APPEND(<field>, <value>); a REMOVE <field1>[,<fieldN>]* clause removes fields. They are translated to
+//	  Patch API "incr", "add" and "remove" operations, so concurrent increments do not lose updates.
+//	- (since v1.2.0) the first form accepts the expected etag of the document, either as "AND _etag=<etag-value>" or "WITH IF_MATCH=<etag-value>".
+//	  If the document's current etag does not match, the statement fails with ErrPreconditionFailure.
+//
 // See StmtInsert for details on <id-value> and <pk-value>.
 type StmtUpdate struct {
 	*StmtCRUD
-	updateStr string
-	whereStr  string
-	id        interface{}
-	fields    []string
-	values    []interface{}
-	pkValues  []interface{}
+	updateStr         string
+	whereStr          string
+	id                interface{}
+	fields            []string
+	values            []interface{}
+	pkValues          []interface{}
+	etag              interface{}    // (since v1.2.0) expected etag of the document, sent as If-Match header
+	whereQuery        string         // (multi-document mode) Cosmos DB SQL predicate, placeholders rewritten to named parameters
+	wherePlaceholders map[int]string // (multi-document mode) placeholder index -> parameter name
+	concurrency       int            // (multi-document mode) number of documents to be replaced in parallel
 }
 
 // String implements interface fmt.Stringer/String.
 //
 // @Available since v1.1.0
 func (s *StmtUpdate) String() string {
-	return fmt.Sprintf(`StmtUpdate{StmtCRUD: %s, fields: %v, values: %v, where_clause: %q, id: %v, pk_values: %v}`,
-		s.StmtCRUD, s.fields, s.values, s.whereStr, s.id, s.pkValues)
+	return fmt.Sprintf(`StmtUpdate{StmtCRUD: %s, fields: %v, values: %v, where_clause: %q, id: %v, pk_values: %v, etag: %v, where_query: %q, concurrency: %d}`,
+		s.StmtCRUD, s.fields, s.values, s.whereStr, s.id, s.pkValues, s.etag, s.whereQuery, s.concurrency)
 }
 
-var (
-	reFieldPart = regexp.MustCompile(`\s*([\w\-]+)\s*=`)
-)
+// incrValue represents "<field> = <field> + <value>" (or "- <value>") in the SET clause.
+//
+// @Available since v1.2.0
+type incrValue struct {
+	value    interface{} // a number or a placeholder
+	negative bool
+}
 
-func (s *StmtUpdate) _parseUpdateClause() error {
-	s.fields = make([]string, 0)
-	s.values = make([]interface{}, 0)
-	for temp := strings.TrimSpace(s.updateStr); temp != ""; temp = strings.TrimSpace(temp) {
-		// firstly, extract the field name
-		if loc := reFieldPart.FindStringIndex(temp); loc != nil && loc[0] == 0 {
-			field := strings.TrimSpace(temp[loc[0] : loc[1]-1])
-			s.fields = append(s.fields, field)
-			temp = strings.TrimSpace(temp[loc[1]:])
-		} else {
-			return errors.New("(field) cannot parse query, invalid token at: " + temp)
+// delta returns the signed value to be added to the field.
+func (v incrValue) delta(args []driver.NamedValue) (interface{}, error) {
+	value := v.value
+	if p, ok := value.(placeholder); ok {
+		value = args[p.index-1].Value
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.negative {
+			return -rv.Int(), nil
 		}
-
-		// secondly, parse the value part
-		value, leftOver, err := _parseValue(temp, ',')
-		if err == nil {
-			s.values = append(s.values, value)
-			temp = leftOver
-			switch v := value.(type) {
-			case placeholder:
-				s.numInputs = g18.Max(s.numInputs, v.index)
-			}
-			continue
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.negative {
+			return -int64(rv.Uint()), nil
 		}
-		return err
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if v.negative {
+			return -rv.Float(), nil
+		}
+		return rv.Float(), nil
 	}
-	return nil
+	return nil, fmt.Errorf("increment value must be a number, got %#v", value)
+}
+
+// appendValue represents "<field> = ARRAY_APPEND(<field>, <value>)" in the SET clause.
+//
+// @Available since v1.2.0
+type appendValue struct {
+	value interface{}
 }
 
-func (s *StmtUpdate) parse(withOptsStr string) error {
-	if err := s.parseWithOpts(withOptsStr); err != nil {
+// removeValue represents "REMOVE <field>".
+//
+// @Available since v1.2.0
+type removeValue struct{}
+
+func (s *StmtUpdate) parse(node *astUpdate) error {
+	if err := s.parseWithOpts(node.withOpts()); err != nil {
 		return err
 	}
 
-	for k := range s.withOpts {
-		if k != "SINGLE_PK" && k != "SINGLEPK" {
+	var ifMatch interface{}
+	for k, v := range s.withOpts {
+		switch k {
+		case "SINGLE_PK", "SINGLEPK", "PK":
+		case "IF_MATCH":
+			var err error
+			if ifMatch, err = _parseIfMatchOpt(v, s.paramNames); err != nil {
+				return err
+			}
+		case "CONCURRENCY":
+			concurrency, err := _parsePositiveIntOpt(k, v)
+			if err != nil {
+				return err
+			}
+			s.concurrency = concurrency
+		default:
 			return fmt.Errorf("invalid query, parsing error at WITH %s", k)
 		}
 	}
 
-	if err := s._parseUpdateClause(); err != nil {
-		return err
+	s.fields, s.values = node.fields, node.values
+	for _, value := range s.values {
+		switch v := value.(type) {
+		case placeholder:
+			s.numInputs = g18.Max(s.numInputs, v.index)
+		case incrValue:
+			if p, ok := v.value.(placeholder); ok {
+				s.numInputs = g18.Max(s.numInputs, p.index)
+			}
+		case appendValue:
+			if p, ok := v.value.(placeholder); ok {
+				s.numInputs = g18.Max(s.numInputs, p.index)
+			}
+		}
+	}
+
+	if node.where.terms == nil {
+		// the WHERE clause is a Cosmos DB SQL predicate: multi-document update
+		if s.isSinglePathPk {
+			return errors.New("WITH SINGLE_PK is not supported when WHERE clause is a predicate, use WITH PK instead")
+		}
+		if ifMatch != nil {
+			return errors.New("WITH IF_MATCH is not supported when WHERE clause is a predicate")
+		}
+		s.whereQuery, s.wherePlaceholders = node.where.predicate, node.where.placeholders
+		s.numInputs = g18.Max(s.numInputs, node.where.maxIndex)
+		s.pkPaths, s.numPkPaths = nil, 0
+		if s.withPk != "" {
+			s.pkPaths = strings.Split(s.withPk, ",")
+			s.numPkPaths = len(s.pkPaths)
+		}
+		return nil
+	}
+	if s.withPk != "" || s.concurrency > 0 {
+		return errors.New("WITH PK/CONCURRENCY is only supported when WHERE clause is a predicate")
+	}
+	s._parseWhereIdPk(node.where.terms)
+	return s.setIfMatch(ifMatch)
+}
+
+// setIfMatch sets the expected etag supplied via WITH IF_MATCH.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) setIfMatch(ifMatch interface{}) error {
+	if ifMatch == nil {
+		return nil
+	}
+	if s.etag != nil {
+		return errors.New("expected etag is specified more than once, use either _etag=<value> or WITH IF_MATCH")
+	}
+	s.etag = ifMatch
+	if p, ok := ifMatch.(placeholder); ok {
+		s.numInputs = g18.Max(s.numInputs, p.index)
 	}
+	return nil
+}
 
+// _parseWhereIdPk extracts id, partition key and etag values from the "WHERE id=<id-value> [AND pk-path=<pk-value>...]" clause.
+func (s *StmtUpdate) _parseWhereIdPk(terms []astWhereTerm) {
 	s.pkPaths = make([]string, 0)
 	s.pkValues = make([]interface{}, 0)
-	for temp := strings.TrimSpace(s.whereStr); temp != ""; temp = strings.TrimSpace(temp) {
-		pkPath, pkValue, leftOver, err := _parseFieldValueAnd(temp)
-		if err == nil {
-			if strings.ToLower(pkPath) == "id" {
-				s.id = pkValue
-			} else {
-				s.pkPaths = append(s.pkPaths, "/"+strings.TrimLeft(pkPath, "/"))
-				s.pkValues = append(s.pkValues, pkValue)
-			}
-			temp = leftOver
-			switch v := pkValue.(type) {
-			case placeholder:
-				s.numInputs = g18.Max(s.numInputs, v.index)
-			}
-			continue
+	for _, term := range terms {
+		if strings.ToLower(term.field) == "id" {
+			s.id = term.value
+		} else if strings.ToLower(term.field) == "_etag" {
+			s.etag = term.value
+		} else {
+			s.pkPaths = append(s.pkPaths, "/"+strings.TrimLeft(term.field, "/"))
+			s.pkValues = append(s.pkValues, term.value)
+		}
+		switch v := term.value.(type) {
+		case placeholder:
+			s.numInputs = g18.Max(s.numInputs, v.index)
 		}
-		return err
 	}
 	if !s.isSinglePathPk {
 		s.numPkPaths = len(s.pkPaths)
 	}
-
-	return nil
 }
 
 func (s *StmtUpdate) validate() error {
-	if s.id == nil {
+	if s.id == nil && s.whereQuery == "" {
 		return errors.New("id value is missing")
 	}
 	if s.dbName == "" || s.collName == "" {
@@ -817,8 +2269,21 @@ func (s *StmtUpdate) Exec(args []driver.Value) (driver.Result, error) {
 func (s *StmtUpdate) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
 	// TODO: pass ctx to REST API client
 
+	result, _, err := s.execute(args)
+	return result, err
+}
+
+// execute updates the document(s) and returns the updated documents.
+func (s *StmtUpdate) execute(args []driver.NamedValue) (driver.Result, []DocInfo, error) {
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
 	if err := s.fetchPkInfo(); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if s.whereQuery != "" {
+		return s.execMultiDocs(args)
 	}
 
 	pkValues := make([]driver.NamedValue, s.numPkPaths)
@@ -831,7 +2296,7 @@ func (s *StmtUpdate) ExecContext(_ context.Context, args []driver.NamedValue) (d
 			pkValues[i] = driver.NamedValue{Name: s.pkPaths[i][1:], Value: pkValue}
 		}
 	} else {
-		return nil, fmt.Errorf("expected %d or %d input values, got %d", s.numInputs, s.numInputs+s.numPkPaths, n)
+		return nil, nil, fmt.Errorf("expected %d or %d input values, got %d", s.numInputs, s.numInputs+s.numPkPaths, n)
 	}
 	pkValuesForApiCall := make([]any, len(pkValues))
 	for i, pkValue := range pkValues {
@@ -843,18 +2308,69 @@ func (s *StmtUpdate) ExecContext(_ context.Context, args []driver.NamedValue) (d
 		}
 	}
 
-	// firstly, fetch the document
 	id := s.id
 	switch v := s.id.(type) {
 	case placeholder:
 		id = args[v.index-1].Value
 	}
 	id, _ = reddo.ToString(id)
+	if s.canPatch() {
+		ops, err := s.patchOperations(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		patchDocResult := s.conn.restClient.PatchDocument(PatchDocReq{
+			DbName:             s.dbName,
+			CollName:           s.collName,
+			DocId:              id.(string),
+			PartitionKeyValues: pkValuesForApiCall,
+			Operations:         ops,
+			MatchEtag:          _resolveEtag(s.etag, args),
+		})
+		if !_isPatchPathRejected(patchDocResult.RestResponse) {
+			result := buildResultNoResultSet(&patchDocResult.RestResponse, false, "", 0)
+			switch patchDocResult.StatusCode {
+			case 404:
+				// consider "document not found" as successful operation
+				// but database/collection not found is not!
+				if strings.Contains(fmt.Sprintf("%s", patchDocResult.Error()), "ResourceType: Document") {
+					result.err = nil
+				}
+			}
+			return result, []DocInfo{patchDocResult.DocInfo}, result.err
+		}
+		// the Patch API rejected the operations, e.g. a parent object does not exist: fall back to fetching and replacing
+		// the whole document, which creates missing parents as needed
+	}
+
+	// the document is fetched, modified and replaced conditioned on the fetched etag; if another writer modifies the
+	// document in between, the read-modify-write is retried so that its update is not lost
+	expectedEtag := _resolveEtag(s.etag, args)
+	for attempt := 1; ; attempt++ {
+		result, docs, retry, err := s.fetchAndReplace(id.(string), pkValuesForApiCall, expectedEtag, args)
+		if !retry || attempt >= maxReplaceAttempts {
+			return result, docs, err
+		}
+	}
+}
+
+// maxReplaceAttempts is the maximum number of times StmtUpdate fetches and replaces a document if it is modified
+// concurrently, before giving up with ErrPreconditionFailure.
+//
+// @Available since v1.2.0
+const maxReplaceAttempts = 5
+
+// fetchAndReplace fetches the document, applies the SET clause and replaces the document. retry is true if the document
+// was modified after being fetched and no expected etag was supplied.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) fetchAndReplace(id string, pkValues []interface{}, expectedEtag string, args []driver.NamedValue) (driver.Result, []DocInfo, bool, error) {
+	// firstly, fetch the document
 	docReq := DocReq{
 		DbName:             s.dbName,
 		CollName:           s.collName,
-		DocId:              id.(string),
-		PartitionKeyValues: pkValuesForApiCall,
+		DocId:              id,
+		PartitionKeyValues: pkValues,
 	}
 	getDocResult := s.conn.restClient.GetDocument(docReq)
 	if err := getDocResult.Error(); err != nil {
@@ -867,27 +2383,26 @@ func (s *StmtUpdate) ExecContext(_ context.Context, args []driver.NamedValue) (d
 				result.err = nil
 			}
 		}
-		return result, result.err
+		return result, nil, false, result.err
 	}
 
 	// secondly, update the fetched document
 	etag := getDocResult.DocInfo.Etag()
+	if expectedEtag != "" {
+		// (since v1.2.0) etag mismatch is reported to the caller if the expected etag is supplied
+		etag = expectedEtag
+	}
 	spec := DocumentSpec{
 		DbName:             s.dbName,
 		CollName:           s.collName,
-		PartitionKeyValues: pkValuesForApiCall,
+		PartitionKeyValues: pkValues,
 		DocumentData:       getDocResult.DocInfo.RemoveSystemAttrs(),
 	}
-	for i, field := range s.fields {
-		switch v := s.values[i].(type) {
-		case placeholder:
-			spec.DocumentData[field] = args[v.index-1].Value
-		default:
-			spec.DocumentData[field] = s.values[i]
-		}
+	if err := s.applySetClause(spec.DocumentData, args); err != nil {
+		return nil, nil, false, err
 	}
 	replaceDocResult := s.conn.restClient.ReplaceDocument(etag, spec)
-	result := buildResultNoResultSet(&replaceDocResult.RestResponse, false, "", 412)
+	result, retry := buildResultNoResultSet(&replaceDocResult.RestResponse, false, "", 0), false
 	switch replaceDocResult.StatusCode {
 	case 404: // rare case, but possible!
 		// consider "document not found" as successful operation
@@ -895,12 +2410,263 @@ func (s *StmtUpdate) ExecContext(_ context.Context, args []driver.NamedValue) (d
 		if strings.Contains(fmt.Sprintf("%s", replaceDocResult.Error()), "ResourceType: Document") {
 			result.err = nil
 		}
+	case 412:
+		retry = expectedEtag == ""
 	}
-	return result, result.err
+	return result, []DocInfo{replaceDocResult.DocInfo}, retry, result.err
 }
 
 // Query implements driver.Stmt/Query.
-// This function is not implemented, use Exec instead.
-func (s *StmtUpdate) Query(_ []driver.Value) (driver.Rows, error) {
-	return nil, ErrQueryNotSupported
+// This function is only available if the statement has a RETURNING clause, use Exec otherwise.
+func (s *StmtUpdate) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+// This function is only available if the statement has a RETURNING clause, use ExecContext otherwise.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.queryReturning(s.execute, args)
+}
+
+// setValue returns the value of the i-th field of the SET clause.
+func (s *StmtUpdate) setValue(i int, args []driver.NamedValue) interface{} {
+	switch v := s.values[i].(type) {
+	case placeholder:
+		return args[v.index-1].Value
+	}
+	return s.values[i]
+}
+
+// applySetClause applies the SET (and REMOVE) clause to the document.
+func (s *StmtUpdate) applySetClause(doc DocInfo, args []driver.NamedValue) error {
+	for i, field := range s.fields {
+		var err error
+		switch v := s.values[i].(type) {
+		case incrValue:
+			var delta interface{}
+			if delta, err = v.delta(args); err == nil {
+				err = _modifyFieldPath(doc, field, func(current interface{}) (interface{}, error) {
+					return _addNumbers(current, delta)
+				})
+			}
+		case appendValue:
+			value := v.value
+			if p, ok := value.(placeholder); ok {
+				value = args[p.index-1].Value
+			}
+			err = _modifyFieldPath(doc, field, func(current interface{}) (interface{}, error) {
+				arr, ok := current.([]interface{})
+				if !ok {
+					return nil, errors.New("not an array")
+				}
+				return append(arr, value), nil
+			})
+		case removeValue:
+			err = _removeFieldPath(doc, field)
+		default:
+			err = _setFieldPath(doc, field, s.setValue(i, args))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// _addNumbers adds delta to the current value of a field, a missing field is considered as zero.
+//
+// @Available since v1.2.0
+func _addNumbers(current, delta interface{}) (interface{}, error) {
+	if current == nil {
+		return delta, nil
+	}
+	c, ok := current.(float64)
+	if !ok {
+		return nil, fmt.Errorf("not a number: %#v", current)
+	}
+	switch d := delta.(type) {
+	case int64:
+		return c + float64(d), nil
+	case float64:
+		return c + d, nil
+	}
+	return nil, fmt.Errorf("not a number: %#v", delta)
+}
+
+// patchRejectedStatus is the status code returned by the Patch API if the operations can not be applied to the document,
+// e.g. the parent of a target path does not exist or an array index is out of range.
+const patchRejectedStatus = 400
+
+// patchPathRejectedMessages are (lower-cased) fragments of the error messages returned by the Patch API when a target
+// path can not be applied to the document, e.g. "Add Operation can only create a child object of an existing node" or
+// "Index 3 to operation set at path /tags/3 is out of bounds".
+var patchPathRejectedMessages = []string{"existing node", "existing path", "out of bound", "out of range"}
+
+// _isPatchPathRejected returns true if the Patch API rejected the operations because a target path can not be applied
+// to the document, in which case the update falls back to fetching and replacing the whole document. Other
+// "400 Bad Request" errors (e.g. an invalid partition key or value) are returned to the caller.
+//
+// @Available since v1.2.0
+func _isPatchPathRejected(resp RestResponse) bool {
+	if resp.StatusCode != patchRejectedStatus {
+		return false
+	}
+	msg := strings.ToLower(string(resp.RespBody))
+	for _, fragment := range patchPathRejectedMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// canPatch returns true if the SET clause can be applied via the Patch API.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) canPatch() bool {
+	return len(s.fields) <= MaxPatchOperations
+}
+
+// patchOperations translates the SET clause to Patch API operations.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) patchOperations(args []driver.NamedValue) ([]PatchOperation, error) {
+	ops := make([]PatchOperation, len(s.fields))
+	for i, field := range s.fields {
+		path := _fieldPathToJsonPointer(field)
+		switch v := s.values[i].(type) {
+		case incrValue:
+			delta, err := v.delta(args)
+			if err != nil {
+				return nil, err
+			}
+			ops[i] = PatchOperation{Op: "incr", Path: path, Value: delta}
+		case appendValue:
+			value := v.value
+			if p, ok := value.(placeholder); ok {
+				value = args[p.index-1].Value
+			}
+			ops[i] = PatchOperation{Op: "add", Path: path + "/-", Value: value}
+		case removeValue:
+			ops[i] = PatchOperation{Op: "remove", Path: path}
+		default:
+			// "set" creates or overwrites an object field and overwrites an existing array element ("add" would insert a
+			// new element at an array index); an array index out of range is rejected and handled by the fallback
+			ops[i] = PatchOperation{Op: "set", Path: path, Value: s.setValue(i, args)}
+		}
+	}
+	return ops, nil
+}
+
+// updateQueriedDoc updates a document returned by the query of execMultiDocs, conditioned on the document's _etag.
+// If usePatch is true, doc holds only id, _etag and partition key values and the update is done via the Patch API;
+// otherwise doc is the whole document, which is modified and replaced.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) updateQueriedDoc(doc DocInfo, pkValues []interface{}, usePatch bool, args []driver.NamedValue) (RestResponse, DocInfo, error) {
+	if usePatch {
+		ops, err := s.patchOperations(args)
+		if err != nil {
+			return RestResponse{}, nil, err
+		}
+		patchDocResult := s.conn.restClient.PatchDocument(PatchDocReq{
+			DbName:             s.dbName,
+			CollName:           s.collName,
+			DocId:              doc.Id(),
+			PartitionKeyValues: pkValues,
+			Operations:         ops,
+			MatchEtag:          doc.Etag(),
+		})
+		return patchDocResult.RestResponse, patchDocResult.DocInfo, nil
+	}
+	etag := doc.Etag()
+	spec := DocumentSpec{
+		DbName:             s.dbName,
+		CollName:           s.collName,
+		PartitionKeyValues: pkValues,
+		DocumentData:       doc.RemoveSystemAttrs(),
+	}
+	if err := s.applySetClause(spec.DocumentData, args); err != nil {
+		return RestResponse{}, nil, err
+	}
+	replaceDocResult := s.conn.restClient.ReplaceDocument(etag, spec)
+	return replaceDocResult.RestResponse, replaceDocResult.DocInfo, nil
+}
+
+// execMultiDocs updates all documents matching the WHERE predicate.
+//
+// @Available since v1.2.0
+func (s *StmtUpdate) execMultiDocs(args []driver.NamedValue) (driver.Result, []DocInfo, error) {
+	if len(args) != s.numInputs {
+		return nil, nil, fmt.Errorf("expected %d input values, got %d", s.numInputs, len(args))
+	}
+	params, err := _buildQueryParams(s.wherePlaceholders, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the Patch API needs only id, _etag and partition key values of matching documents, the whole documents are fetched otherwise
+	projection, canPatch := "*", s.canPatch()
+	if canPatch {
+		projection = _idPkProjection(s.pkPaths)
+	}
+	query := QueryReq{
+		DbName:                s.dbName,
+		CollName:              s.collName,
+		Query:                 "SELECT " + projection + " FROM c WHERE " + s.whereQuery,
+		Params:                params,
+		CrossPartitionEnabled: true,
+	}
+	queryResult := s.conn.restClient.QueryDocumentsCrossPartition(query)
+	if err := queryResult.Error(); err != nil {
+		return nil, nil, normalizeError(queryResult.StatusCode, 0, err)
+	}
+
+	docs := queryResult.Documents.AsDocInfoSlice()
+	updatedDocs := make([]DocInfo, len(docs))
+	affectedRows, err := _execConcurrently(len(docs), s.concurrency, func(i int) (int64, error) {
+		// pkValues are extracted only once, as the partition key of a document never changes
+		doc, usePatch, pkValues := docs[i], canPatch, _extractPkValues(docs[i], s.pkPaths)
+		if canPatch {
+			pkValues = _projectedPkValues(docs[i], len(s.pkPaths))
+		}
+		for attempt := 1; ; {
+			// the update is conditioned on the _etag returned by the query
+			restResp, updatedDoc, err := s.updateQueriedDoc(doc, pkValues, usePatch, args)
+			if err != nil {
+				return 0, err
+			}
+			switch {
+			case usePatch && _isPatchPathRejected(restResp):
+				// fall back to fetching and replacing the whole document, see execute
+				usePatch = false
+			case restResp.StatusCode == 412:
+				// the document has been modified since being queried: its update is retried, so that concurrent
+				// updates (e.g. increments) are not lost
+				if attempt >= maxReplaceAttempts {
+					return 0, ErrPreconditionFailure
+				}
+				attempt++
+			case restResp.StatusCode == 404:
+				// document has been deleted since being queried, skip it
+				return 0, nil
+			default:
+				if err := restResp.Error(); err != nil {
+					return 0, normalizeError(restResp.StatusCode, 0, err)
+				}
+				updatedDocs[i] = updatedDoc
+				return 1, nil
+			}
+			// re-fetch the document and re-check the predicate against its current version
+			projection := "*"
+			if usePatch {
+				projection = _idPkProjection(s.pkPaths)
+			}
+			if doc, err = s.requeryDoc(projection, s.whereQuery, params, docs[i].Id(), pkValues); err != nil || doc == nil {
+				return 0, err
+			}
+		}
+	})
+	return &ResultNoResultSet{err: err, affectedRows: affectedRows}, updatedDocs, err
 }
@@ -41,6 +41,12 @@ a,b,c) VALUES (
 $1, :3, @2)`,
 			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 3}, dbName: "db-2", collName: "table_2"}, fields: []string{"a", "b", "c"}, values: []interface{}{placeholder{1}, placeholder{3}, placeholder{2}}},
 		},
+		{
+			name:     "named_params",
+			sql:      `INSERT INTO db.table (id,name,alias) VALUES (@id, @name, @id)`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table"}, fields: []string{"id", "name", "alias"}, values: []interface{}{placeholder{1}, placeholder{2}, placeholder{1}}},
+		},
+		{name: "error_mixed_params", sql: `INSERT INTO db.table (id,name) VALUES (@id, $2)`, mustError: true},
 		{
 			name:     "singlepk",
 			sql:      `INSERT INTO db.table (a,b,c) VALUES (1,2,3) WITH singlePK`,
@@ -101,6 +107,41 @@ $1, :3, @2)`,
 			sql:       `INSERT INTO db.table (a,b,c) VALUES (:1,$2,3) WITH Pk=/mypk WITH SINGLE_PK`,
 			mustError: true,
 		},
+		{
+			name: "multi_rows",
+			sql: `INSERT INTO db.table (a,b,c) VALUES (:1,$2,3),
+				(@3, "\"x\"", null) , (4,5,$4) WITH pk=/a, CONCURRENCY=3`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 4}, dbName: "db", collName: "table", numPkPaths: 1, withPk: "/a", pkPaths: []string{"/a"}}, fields: []string{"a", "b", "c"},
+				values: []interface{}{placeholder{1}, placeholder{2}, 3.0, placeholder{3}, "x", nil, 4.0, 5.0, placeholder{4}}, concurrency: 3},
+		},
+		{name: "error_multi_rows_mismatch", sql: `INSERT INTO db.table (a,b,c) VALUES (1,2,3),(4,5)`, mustError: true},
+		{name: "error_multi_rows_singlepk", sql: `INSERT INTO db.table (a,b,c) VALUES (1,2,3),(4,5,6) WITH SINGLE_PK`, mustError: true},
+		{name: "error_multi_rows_invalid_concurrency", sql: `INSERT INTO db.table (a,b,c) VALUES (1,2,3),(4,5,6) WITH CONCURRENCY=0`, mustError: true},
+		{name: "error_single_row_concurrency", sql: `INSERT INTO db.table (a,b,c) VALUES (1,2,3) WITH CONCURRENCY=2`, mustError: true},
+		{name: "error_invalid_ttl", sql: `INSERT INTO db.table (a,b,c) VALUES (1,2,3) WITH TTL=OFF`, mustError: true},
+		{
+			name:     "ttl",
+			sql:      `UPSERT INTO db.table (a,b,c) VALUES (1,2,3) WITH PK=/a WITH TTL=60`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 0}, dbName: "db", collName: "table", numPkPaths: 1, withPk: "/a", pkPaths: []string{"/a"}}, isUpsert: true, fields: []string{"a", "b", "c"}, values: []interface{}{1.0, 2.0, 3.0}, ttl: _ttl(60)},
+		},
+		{
+			name:     "nested_fields",
+			sql:      `INSERT INTO db.table (id, address.city, tags[0], tags[1]) VALUES (1, @1, "\"a\"", "\"b\"") WITH PK=/address/city`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table", numPkPaths: 1, withPk: "/address/city", pkPaths: []string{"/address/city"}}, fields: []string{"id", "address.city", "tags[0]", "tags[1]"}, values: []interface{}{1.0, placeholder{1}, "a", "b"}},
+		},
+		{name: "error_invalid_field_path", sql: `INSERT INTO db.table (a.) VALUES (1)`, mustError: true},
+		{name: "error_invalid_field_path2", sql: `INSERT INTO db.table (a[-1]) VALUES (1)`, mustError: true},
+
+		{
+			name:     "parentheses_in_string",
+			sql:      `INSERT INTO db.table (id,note) VALUES ("\"1\"", "\"a (note) with ')' inside\"")`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 0}, dbName: "db", collName: "table"}, fields: []string{"id", "note"}, values: []interface{}{"1", "a (note) with ')' inside"}},
+		},
+		{
+			name:     "returning",
+			sql:      `INSERT INTO db.table (id,a) VALUES (@1,@2) WITH PK=/id RETURNING id, _etag`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", numPkPaths: 1, withPk: "/id", pkPaths: []string{"/id"}, returning: []string{"id", "_etag"}}, fields: []string{"id", "a"}, values: []interface{}{placeholder{1}, placeholder{2}}},
+		},
 	}
 	for _, testCase := range testData {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -301,6 +342,11 @@ a,b,c) VALUES ($1,
 			sql:       `UPSERT INTO db.table (a,b,c) VALUES (:1, :3, :2) WITH singlePK=false`,
 			mustError: true,
 		},
+		{
+			name:     "multi_rows",
+			sql:      `UPSERT INTO db.table (a,b) VALUES (:1,$2), (:3,$4)`,
+			expected: &StmtInsert{StmtCRUD: &StmtCRUD{dbName: "db", collName: "table"}, isUpsert: true, fields: []string{"a", "b"}, values: []interface{}{placeholder{1}, placeholder{2}, placeholder{3}, placeholder{4}}},
+		},
 	}
 	for _, testCase := range testData {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -516,6 +562,62 @@ db1.table1 WHERE
 			sql:      `DELETE FROM db.table WHERE id=:3 AND app=$2 and Username=1`,
 			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 3}, dbName: "db", collName: "table", numPkPaths: 2, pkPaths: []string{"/app", "/Username"}}, id: placeholder{3}, pkValues: []interface{}{placeholder{2}, 1.0}},
 		},
+		{
+			name: "where_predicate",
+			sql:  `DELETE FROM db.table WHERE c.createdAt < @1 AND c["status"] = "archived"`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table"},
+				whereQuery: `c.createdAt < @_1 AND c["status"] = "archived"`, wherePlaceholders: map[int]string{1: "@_1"}},
+		},
+		{
+			name: "where_predicate_named_params",
+			sql:  `DELETE FROM db.table WHERE c.createdAt < @before AND c["status"] = @status`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table"},
+				whereQuery: `c.createdAt < @before AND c["status"] = @status`, wherePlaceholders: map[int]string{1: "@before", 2: "@status"}},
+		},
+		{
+			name: "where_predicate_with_opts",
+			sql:  `DELETE FROM db.table WHERE c.a > $2 OR c.b = :1 WITH PK=/app, CONCURRENCY=8, MAX_ROWS=100`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", withPk: "/app", pkPaths: []string{"/app"}, numPkPaths: 1},
+				whereQuery: `c.a > @_2 OR c.b = @_1`, wherePlaceholders: map[int]string{1: "@_1", 2: "@_2"}, concurrency: 8, maxRows: 100},
+		},
+		{name: "error_predicate_singlepk", sql: `DELETE FROM db.table WHERE c.a > 1 WITH SINGLE_PK`, mustError: true},
+		{name: "error_predicate_invalid_concurrency", sql: `DELETE FROM db.table WHERE c.a > 1 WITH CONCURRENCY=-1`, mustError: true},
+		{name: "error_predicate_invalid_max_rows", sql: `DELETE FROM db.table WHERE c.a > 1 WITH MAX_ROWS=abc`, mustError: true},
+		{name: "error_max_rows_single_doc", sql: `DELETE FROM db.table WHERE id=1 WITH MAX_ROWS=1`, mustError: true},
+
+		{
+			name:     "returning",
+			sql:      `DELETE FROM db.table WHERE id=@1 RETURNING *`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table", pkPaths: []string{}, returning: []string{"*"}}, id: placeholder{1}, pkValues: []interface{}{}},
+		},
+		{
+			name: "where_predicate_returning",
+			sql:  `DELETE FROM db.table WHERE c.a > 1 WITH CONCURRENCY=2 returning id, a`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{}, dbName: "db", collName: "table", returning: []string{"id", "a"}},
+				whereQuery: `c.a > 1`, wherePlaceholders: map[int]string{}, concurrency: 2},
+		},
+		{name: "error_returning_no_fields", sql: `DELETE FROM db.table WHERE id=1 RETURNING`, mustError: true},
+
+		{
+			name:     "where_etag",
+			sql:      `DELETE FROM db.table WHERE id=@1 AND _etag=@3 AND app=@2`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 3}, dbName: "db", collName: "table", numPkPaths: 1, pkPaths: []string{"/app"}}, id: placeholder{1}, pkValues: []interface{}{placeholder{2}}, etag: placeholder{3}},
+		},
+		{
+			name:     "with_if_match",
+			sql:      `DELETE FROM db.table WHERE id=@1 WITH IF_MATCH=@2`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", pkPaths: []string{}}, id: placeholder{1}, pkValues: []interface{}{}, etag: placeholder{2}},
+		},
+		{
+			name:     "named_params",
+			sql:      `DELETE FROM db.table WHERE id=@id WITH IF_MATCH=@etag`,
+			expected: &StmtDelete{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", pkPaths: []string{}}, id: placeholder{1}, pkValues: []interface{}{}, etag: placeholder{2}},
+		},
+		{name: "error_mixed_params", sql: `DELETE FROM db.table WHERE id=@id WITH IF_MATCH=@2`, mustError: true},
+		{name: "error_etag_twice", sql: `DELETE FROM db.table WHERE id=@1 AND _etag=@2 WITH IF_MATCH=@3`, mustError: true},
+		{name: "error_if_match_predicate", sql: `DELETE FROM db.table WHERE c.a > 1 WITH IF_MATCH=@1`, mustError: true},
+		{name: "error_if_match_invalid", sql: `DELETE FROM db.table WHERE id=1 WITH IF_MATCH=123`, mustError: true},
+		{name: "error_returning_invalid_fields", sql: `DELETE FROM db.table WHERE id=1 RETURNING a.b`, mustError: true},
 	}
 	for _, testCase := range testData {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -669,22 +771,35 @@ func TestStmtSelect_parse(t *testing.T) {
 		{
 			name:     "basic",
 			sql:      `SELECT * FROM c WITH database=db WITH collection=tbl`,
-			expected: &StmtSelect{dbName: "db", collName: "tbl", selectQuery: `SELECT * FROM c`, placeholders: map[int]string{}},
+			expected: &StmtSelect{dbName: "db", collName: "tbl", selectQuery: `SELECT * FROM c`, placeholders: map[int]string{}, alias: "c"},
 		},
 		{
 			name:     "cross_partition",
 			sql:      `SELECT CROSS PARTITION * FROM c WHERE id="1" WITH db=db-1 WITH table=tbl_1`,
-			expected: &StmtSelect{dbName: "db-1", collName: "tbl_1", isCrossPartition: true, selectQuery: `SELECT * FROM c WHERE id="1"`, placeholders: map[int]string{}},
+			expected: &StmtSelect{dbName: "db-1", collName: "tbl_1", isCrossPartition: true, selectQuery: `SELECT * FROM c WHERE id="1"`, placeholders: map[int]string{}, alias: "c"},
 		},
 		{
 			name:     "placeholders",
 			sql:      `SELECT id,username,email FROM c WHERE username!=@1 AND (id>:2 OR email=$3) WITH CROSS_PARTITION=true WITH database=db_3-0 WITH table=table-3_0`,
-			expected: &StmtSelect{dbName: "db_3-0", collName: "table-3_0", isCrossPartition: true, selectQuery: `SELECT id,username,email FROM c WHERE username!=@_1 AND (id>@_2 OR email=@_3)`, placeholders: map[int]string{1: "@_1", 2: "@_2", 3: "@_3"}},
+			expected: &StmtSelect{dbName: "db_3-0", collName: "table-3_0", isCrossPartition: true, selectQuery: `SELECT id,username,email FROM c WHERE username!=@_1 AND (id>@_2 OR email=@_3)`, placeholders: map[int]string{1: "@_1", 2: "@_2", 3: "@_3"}, alias: "c"},
 		},
 		{
 			name:     "collection_in_query",
 			sql:      `SELECT a,b,c FROM user u WHERE u.id="1" WITH db=dbtemp WITH CrossPartition`,
-			expected: &StmtSelect{dbName: "dbtemp", collName: "user", isCrossPartition: true, selectQuery: `SELECT a,b,c FROM user u WHERE u.id="1"`, placeholders: map[int]string{}},
+			expected: &StmtSelect{dbName: "dbtemp", collName: "user", isCrossPartition: true, selectQuery: `SELECT a,b,c FROM user u WHERE u.id="1"`, placeholders: map[int]string{}, alias: "u", pkFilters: map[string]interface{}{"/id": "1"}},
+		},
+		{
+			name: "named_params",
+			sql:  `SELECT * FROM c WHERE c.id=@id AND c.grade>=@min AND c.tag!="@id" OFFSET @skip LIMIT @min WITH db=db WITH table=tbl`,
+			expected: &StmtSelect{dbName: "db", collName: "tbl", selectQuery: `SELECT * FROM c WHERE c.id=@id AND c.grade>=@min AND c.tag!="@id" OFFSET @skip LIMIT @min`,
+				placeholders: map[int]string{1: "@id", 2: "@min", 3: "@skip"}, alias: "c", pkFilters: map[string]interface{}{"/id": placeholder{1}}},
+		},
+		{name: "error_mixed_params", sql: `SELECT * FROM c WHERE c.id=@id AND c.grade>=$1 WITH db=db WITH table=tbl`, mustError: true},
+		{
+			name: "point_read",
+			sql:  `SELECT * FROM c WHERE c.id=$1 AND c.app.pk="p" WITH db=db WITH table=tbl`,
+			expected: &StmtSelect{dbName: "db", collName: "tbl", selectQuery: `SELECT * FROM c WHERE c.id=@_1 AND c.app.pk="p"`, placeholders: map[int]string{1: "@_1"}, alias: "c",
+				pkFilters: map[string]interface{}{"/id": placeholder{1}, "/app/pk": "p"}, pointRead: true},
 		},
 	}
 	for _, testCase := range testData {
@@ -724,25 +839,25 @@ func TestStmtSelect_parse_defaultDb(t *testing.T) {
 			name:     "basic",
 			db:       "mydb",
 			sql:      `SELECT * FROM c WITH collection=tbl`,
-			expected: &StmtSelect{dbName: "mydb", collName: "tbl", selectQuery: `SELECT * FROM c`, placeholders: map[int]string{}},
+			expected: &StmtSelect{dbName: "mydb", collName: "tbl", selectQuery: `SELECT * FROM c`, placeholders: map[int]string{}, alias: "c"},
 		},
 		{
 			name:     "db_table_in_query",
 			db:       "mydb",
 			sql:      `SELECT CROSS PARTITION * FROM c WHERE id="1" WITH db=db-1 WITH table=tbl_1`,
-			expected: &StmtSelect{dbName: "db-1", collName: "tbl_1", isCrossPartition: true, selectQuery: `SELECT * FROM c WHERE id="1"`, placeholders: map[int]string{}},
+			expected: &StmtSelect{dbName: "db-1", collName: "tbl_1", isCrossPartition: true, selectQuery: `SELECT * FROM c WHERE id="1"`, placeholders: map[int]string{}, alias: "c"},
 		},
 		{
 			name:     "placeholders",
 			db:       "mydb",
 			sql:      `SELECT id,username,email FROM c WHERE username!=@1 AND (id>:2 OR email=$3) WITH CROSS_PARTITION=true WITH table=tbl_2-0`,
-			expected: &StmtSelect{dbName: "mydb", collName: "tbl_2-0", isCrossPartition: true, selectQuery: `SELECT id,username,email FROM c WHERE username!=@_1 AND (id>@_2 OR email=@_3)`, placeholders: map[int]string{1: "@_1", 2: "@_2", 3: "@_3"}},
+			expected: &StmtSelect{dbName: "mydb", collName: "tbl_2-0", isCrossPartition: true, selectQuery: `SELECT id,username,email FROM c WHERE username!=@_1 AND (id>@_2 OR email=@_3)`, placeholders: map[int]string{1: "@_1", 2: "@_2", 3: "@_3"}, alias: "c"},
 		},
 		{
 			name:     "collection_in_query",
 			db:       "mydb",
 			sql:      `SELECT a,b,c FROM user u WHERE u.id="1" with CrossPartition`,
-			expected: &StmtSelect{dbName: "mydb", collName: "user", isCrossPartition: true, selectQuery: `SELECT a,b,c FROM user u WHERE u.id="1"`, placeholders: map[int]string{}},
+			expected: &StmtSelect{dbName: "mydb", collName: "user", isCrossPartition: true, selectQuery: `SELECT a,b,c FROM user u WHERE u.id="1"`, placeholders: map[int]string{}, alias: "u", pkFilters: map[string]interface{}{"/id": "1"}},
 		},
 	}
 	for _, testCase := range testData {
@@ -769,6 +884,63 @@ func TestStmtSelect_parse_defaultDb(t *testing.T) {
 	}
 }
 
+func TestStmtExplain_parse(t *testing.T) {
+	testName := "TestStmtExplain_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtSelect
+		mustError bool
+	}{
+		{name: "error_not_select", sql: `EXPLAIN DELETE FROM db.tbl WHERE id=1`, mustError: true},
+		{name: "error_no_db", sql: `EXPLAIN SELECT * FROM c WITH collection=tbl`, mustError: true},
+		{name: "error_returning", sql: `EXPLAIN SELECT * FROM c WITH db=db WITH collection=tbl RETURNING *`, mustError: true},
+		{name: "error_mixed_params", sql: `EXPLAIN SELECT * FROM c WHERE c.id=@id AND c.grade>=$1 WITH db=db WITH table=tbl`, mustError: true},
+		{
+			name:     "basic",
+			sql:      `EXPLAIN SELECT * FROM c ORDER BY c.name WITH database=db WITH collection=tbl`,
+			expected: &StmtSelect{dbName: "db", collName: "tbl", selectQuery: `SELECT * FROM c ORDER BY c.name`, placeholders: map[int]string{}, alias: "c"},
+		},
+		{
+			name:     "default_db",
+			db:       "mydb",
+			sql:      `explain SELECT CROSS PARTITION c.a, COUNT(1) AS n FROM c WHERE c.b>$1 GROUP BY c.a WITH table=tbl`,
+			expected: &StmtSelect{dbName: "mydb", collName: "tbl", isCrossPartition: true, selectQuery: `SELECT c.a, COUNT(1) AS n FROM c WHERE c.b>@_1 GROUP BY c.a`, placeholders: map[int]string{1: "@_1"}, alias: "c"},
+		},
+		{
+			name:     "named_params",
+			sql:      `EXPLAIN SELECT TOP @n * FROM c WHERE c.grade>=@min WITH db=db WITH table=tbl`,
+			expected: &StmtSelect{dbName: "db", collName: "tbl", selectQuery: `SELECT TOP @n * FROM c WHERE c.grade>=@min`, placeholders: map[int]string{1: "@n", 2: "@min"}, alias: "c"},
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			stmt, ok := s.(*StmtExplain)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtExplain but received %T", testName+"/"+testCase.name, s)
+			}
+			if stmt.numInputs != len(testCase.expected.placeholders) {
+				t.Fatalf("%s failed: expected %d input(s) but received %d", testName+"/"+testCase.name, len(testCase.expected.placeholders), stmt.numInputs)
+			}
+			stmt.source.Stmt = nil
+			if !reflect.DeepEqual(stmt.source, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %s\nreceived %s", testName+"/"+testCase.name, testCase.expected, stmt.source)
+			}
+		})
+	}
+}
+
 func TestStmtUpdate_parse(t *testing.T) {
 	testName := "TestStmtUpdate_parse"
 	testData := []struct {
@@ -875,6 +1047,82 @@ func TestStmtUpdate_parse(t *testing.T) {
 				fields: []string{"a", "b", "c", "d"},
 				values: []interface{}{1.0, placeholder{2}, "3", placeholder{9}}},
 		},
+
+		{
+			name: "where_predicate",
+			sql:  `UPDATE db.table SET status="\"archived\"" WHERE c.createdAt < @1 AND c["status"] != "archived"`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table"},
+				fields: []string{"status"}, values: []interface{}{"archived"},
+				whereQuery: `c.createdAt < @_1 AND c["status"] != "archived"`, wherePlaceholders: map[int]string{1: "@_1"}},
+		},
+		{
+			name: "where_predicate_with_pk_concurrency",
+			sql:  `UPDATE db.table SET a=$1, b=$3 WHERE c.a > $2 OR c.id IN ("1", "2") WITH PK=/app/user, CONCURRENCY=4`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 3}, dbName: "db", collName: "table", withPk: "/app/user", pkPaths: []string{"/app/user"}, numPkPaths: 1},
+				fields: []string{"a", "b"}, values: []interface{}{placeholder{1}, placeholder{3}},
+				whereQuery: `c.a > @_2 OR c.id IN ("1", "2")`, wherePlaceholders: map[int]string{2: "@_2"}, concurrency: 4},
+		},
+		{name: "error_predicate_singlepk", sql: `UPDATE db.table SET a=1 WHERE c.a > 1 WITH SINGLE_PK`, mustError: true},
+		{name: "error_predicate_invalid_concurrency", sql: `UPDATE db.table SET a=1 WHERE c.a > 1 WITH CONCURRENCY=0`, mustError: true},
+		{name: "error_predicate_invalid_concurrency2", sql: `UPDATE db.table SET a=1 WHERE c.a > 1 WITH CONCURRENCY=abc`, mustError: true},
+		{name: "error_concurrency_single_doc", sql: `UPDATE db.table SET a=1 WHERE id=1 WITH CONCURRENCY=2`, mustError: true},
+		{name: "error_pk_single_doc", sql: `UPDATE db.table SET a=1 WHERE id=1 WITH PK=/a`, mustError: true},
+
+		{
+			name: "nested_fields",
+			sql:  `UPDATE db.table SET address.city=@1, tags[0]="\"x\"", a-b.c_d[1][2].e=null WHERE id=@2`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", pkPaths: []string{}},
+				id: placeholder{2}, pkValues: []interface{}{}, fields: []string{"address.city", "tags[0]", "a-b.c_d[1][2].e"}, values: []interface{}{placeholder{1}, "x", nil}},
+		},
+		{name: "error_invalid_field_path", sql: `UPDATE db.table SET address..city=1 WHERE id=1`, mustError: true},
+		{name: "error_invalid_field_path2", sql: `UPDATE db.table SET tags[a]=1 WHERE id=1`, mustError: true},
+
+		{
+			name: "incr_append_remove",
+			sql:  `UPDATE db.table SET views = views + 1, stats.score=stats.score-@1, tags = array_append(tags, "\"new\""), a = NULL REMOVE obsolete, b[0] WHERE id=@2`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", pkPaths: []string{}},
+				id: placeholder{2}, pkValues: []interface{}{},
+				fields: []string{"views", "stats.score", "tags", "a", "obsolete", "b[0]"},
+				values: []interface{}{incrValue{value: 1.0}, incrValue{value: placeholder{1}, negative: true}, appendValue{value: "new"}, nil, removeValue{}, removeValue{}}},
+		},
+		{
+			name: "remove_only",
+			sql:  `UPDATE db.table REMOVE a WHERE id=1`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{}, dbName: "db", collName: "table", pkPaths: []string{}},
+				id: 1.0, pkValues: []interface{}{}, fields: []string{"a"}, values: []interface{}{removeValue{}}},
+		},
+		{
+			name: "remove_then_set",
+			sql:  `UPDATE db.table REMOVE a SET tags=ARRAY_APPEND(tags,$1),remove=1 WHERE id=1`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table", pkPaths: []string{}},
+				id: 1.0, pkValues: []interface{}{}, fields: []string{"a", "tags", "remove"}, values: []interface{}{removeValue{}, appendValue{value: placeholder{1}}, 1.0}},
+		},
+		{name: "error_incr_not_number", sql: `UPDATE db.table SET a=a+"\"x\"" WHERE id=1`, mustError: true},
+		{name: "error_append_other_field", sql: `UPDATE db.table SET a=ARRAY_APPEND(b, 1) WHERE id=1`, mustError: true},
+		{name: "error_append_missing_parenthesis", sql: `UPDATE db.table SET a=ARRAY_APPEND(a, 1 WHERE id=1`, mustError: true},
+		{name: "error_remove_invalid_field", sql: `UPDATE db.table REMOVE a=1 WHERE id=1`, mustError: true},
+
+		{
+			name: "where_etag",
+			sql:  `UPDATE db.table SET a=@1 WHERE id=@2 AND _ETAG="\"\\\"etag1\\\"\""`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", pkPaths: []string{}},
+				id: placeholder{2}, pkValues: []interface{}{}, etag: `"etag1"`, fields: []string{"a"}, values: []interface{}{placeholder{1}}},
+		},
+		{
+			name: "with_if_match",
+			sql:  `UPDATE db.table SET a=1 WHERE id=@1 WITH IF_MATCH=:3`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 3}, dbName: "db", collName: "table", pkPaths: []string{}},
+				id: placeholder{1}, pkValues: []interface{}{}, etag: placeholder{3}, fields: []string{"a"}, values: []interface{}{1.0}},
+		},
+		{name: "error_etag_twice", sql: `UPDATE db.table SET a=1 WHERE id=@1 AND _etag=@2 WITH IF_MATCH=@3`, mustError: true},
+		{name: "error_if_match_predicate", sql: `UPDATE db.table SET a=1 WHERE c.a > 1 WITH IF_MATCH=@1`, mustError: true},
+
+		{
+			name: "returning",
+			sql:  `UPDATE db.table SET a=1 WHERE id=@1 RETURNING id,a`,
+			expected: &StmtUpdate{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table", pkPaths: []string{}, returning: []string{"id", "a"}},
+				id: placeholder{1}, pkValues: []interface{}{}, fields: []string{"a"}, values: []interface{}{1.0}},
+		},
 	}
 	for _, testCase := range testData {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -1040,3 +1288,182 @@ SET a=null, b=
 		})
 	}
 }
+
+func TestStmtDeleteAll_parse(t *testing.T) {
+	testName := "TestStmtDeleteAll_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtDeleteAll
+		mustError bool
+	}{
+		{name: "error_no_collection", sql: `DELETE ALL FROM db WHERE pk=1`, mustError: true},
+		{name: "error_no_db", sql: `DELETE ALL FROM table WHERE pk=1`, mustError: true},
+		{name: "error_no_where", sql: `DELETE ALL FROM db.table`, mustError: true},
+		{name: "error_id", sql: `DELETE ALL FROM db.table WHERE id=1`, mustError: true},
+		{name: "error_id2", sql: `DELETE ALL FROM db.table WHERE pk=1 AND id=1`, mustError: true},
+		{name: "error_returning", sql: `DELETE ALL FROM db.table WHERE pk=1 RETURNING *`, mustError: true},
+		{name: "error_invalid_where", sql: `DELETE ALL FROM db.table WHERE pk=@1 a`, mustError: true},
+
+		{
+			name:     "basic",
+			sql:      `DELETE ALL FROM db.table WHERE tenantId="\"tenant1\""`,
+			expected: &StmtDeleteAll{StmtCRUD: &StmtCRUD{Stmt: &Stmt{}, dbName: "db", collName: "table", pkPaths: []string{"/tenantId"}, numPkPaths: 1}, pkValues: []interface{}{"tenant1"}},
+		},
+		{
+			name:     "default_db",
+			db:       "mydb",
+			sql:      `delete all from table WHERE tenantId=@1`,
+			expected: &StmtDeleteAll{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "mydb", collName: "table", pkPaths: []string{"/tenantId"}, numPkPaths: 1}, pkValues: []interface{}{placeholder{1}}},
+		},
+		{
+			name:     "subpartitions",
+			sql:      `DELETE ALL FROM db.table WHERE app=:2 AND username=$1`,
+			expected: &StmtDeleteAll{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 2}, dbName: "db", collName: "table", pkPaths: []string{"/app", "/username"}, numPkPaths: 2}, pkValues: []interface{}{placeholder{2}, placeholder{1}}},
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			stmt, ok := s.(*StmtDeleteAll)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtDeleteAll but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = &Stmt{numInputs: stmt.numInputs}
+			stmt.whereStr = "" // ignore
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %s\nreceived %s", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestStmtInsertSelect_parse(t *testing.T) {
+	testName := "TestStmtInsertSelect_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtInsertSelect
+		mustError bool
+	}{
+		{name: "error_no_db", sql: `INSERT INTO table2 SELECT * FROM table1`, mustError: true},
+		{name: "error_no_from", sql: `INSERT INTO db.table2 SELECT 1`, mustError: true},
+		{name: "error_singlepk", sql: `INSERT INTO db.table2 SELECT * FROM table1 WITH SINGLE_PK`, mustError: true},
+		{name: "error_invalid_concurrency", sql: `INSERT INTO db.table2 SELECT * FROM table1 WITH CONCURRENCY=x`, mustError: true},
+		{name: "error_invalid_ttl", sql: `INSERT INTO db.table2 SELECT * FROM table1 WITH TTL=x`, mustError: true},
+		{name: "error_invalid_source_opt", sql: `INSERT INTO db.table2 SELECT * FROM table1 WITH abc=1`, mustError: true},
+		{name: "error_returning", sql: `INSERT INTO db.table2 SELECT * FROM table1 RETURNING *`, mustError: true},
+
+		{
+			name: "basic",
+			sql:  `INSERT INTO db.table2 SELECT * FROM table1 c WHERE c.grade > @1`,
+			expected: &StmtInsertSelect{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table2"},
+				source: &StmtSelect{dbName: "db", collName: "table1", isCrossPartition: true, selectQuery: `SELECT * FROM table1 c WHERE c.grade > @_1`, placeholders: map[int]string{1: "@_1"}, alias: "c"}},
+		},
+		{
+			name: "upsert_with_opts",
+			db:   "mydb",
+			sql:  `UPSERT INTO table2 SELECT CROSS PARTITION c.id, c.tenant AS pk FROM c WITH db=srcdb, collection=table1, PK=/pk, CONCURRENCY=4, TTL=300`,
+			expected: &StmtInsertSelect{StmtCRUD: &StmtCRUD{Stmt: &Stmt{}, dbName: "mydb", collName: "table2", withPk: "/pk", pkPaths: []string{"/pk"}, numPkPaths: 1}, isUpsert: true, concurrency: 4, ttl: _ttl(300),
+				source: &StmtSelect{dbName: "srcdb", collName: "table1", isCrossPartition: true, selectQuery: `SELECT c.id, c.tenant AS pk FROM c`, placeholders: map[int]string{}, alias: "c"}},
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			stmt, ok := s.(*StmtInsertSelect)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtInsertSelect but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = &Stmt{numInputs: stmt.numInputs}
+			stmt.source.Stmt = nil // ignore
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %s\nreceived %s", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestStmtInsertValue_parse(t *testing.T) {
+	testName := "TestStmtInsertValue_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtInsertValue
+		mustError bool
+	}{
+		{name: "error_no_db", sql: `INSERT INTO table VALUE @1`, mustError: true},
+		{name: "error_not_json", sql: `INSERT INTO db.table VALUE '{id:1}'`, mustError: true},
+		{name: "error_not_object", sql: `INSERT INTO db.table VALUE '[1,2,3]'`, mustError: true},
+		{name: "error_invalid_value", sql: `INSERT INTO db.table VALUE 123`, mustError: true},
+		{name: "error_singlepk", sql: `INSERT INTO db.table VALUE @1 WITH SINGLE_PK`, mustError: true},
+		{name: "error_invalid_ttl", sql: `INSERT INTO db.table VALUE @1 WITH TTL=0`, mustError: true},
+		{
+			name:     "ttl",
+			sql:      `INSERT INTO db.table VALUE @1 WITH TTL=-1`,
+			expected: &StmtInsertValue{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table"}, value: placeholder{1}, ttl: _ttl(-1)},
+		},
+
+		{
+			name:     "returning",
+			sql:      `INSERT INTO db.table VALUE @1 RETURNING *`,
+			expected: &StmtInsertValue{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table", returning: []string{"*"}}, value: placeholder{1}},
+		},
+
+		{
+			name:     "placeholder",
+			sql:      `INSERT INTO db.table VALUE @1`,
+			expected: &StmtInsertValue{StmtCRUD: &StmtCRUD{Stmt: &Stmt{numInputs: 1}, dbName: "db", collName: "table"}, value: placeholder{1}},
+		},
+		{
+			name: "upsert_literal",
+			db:   "mydb",
+			sql:  `UPSERT INTO table VALUE '{"id":"1","name":"O''Brien","address":{"city":"Hanoi"}}' WITH PK=/address/city`,
+			expected: &StmtInsertValue{StmtCRUD: &StmtCRUD{Stmt: &Stmt{}, dbName: "mydb", collName: "table", withPk: "/address/city", pkPaths: []string{"/address/city"}, numPkPaths: 1}, isUpsert: true,
+				value: map[string]interface{}{"id": "1", "name": "O'Brien", "address": map[string]interface{}{"city": "Hanoi"}}},
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			stmt, ok := s.(*StmtInsertValue)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtInsertValue but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = &Stmt{numInputs: stmt.numInputs}
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %s\nreceived %s", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
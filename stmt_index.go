@@ -0,0 +1,386 @@
+package gocosmos
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	indexKindIncluded  = "included"
+	indexKindExcluded  = "excluded"
+	indexKindComposite = "composite"
+	indexKindSpatial   = "spatial"
+)
+
+// spatialTypes maps the spatial types accepted by WITH TYPES option (in upper case) to their names in indexing policy.
+var spatialTypes = map[string]string{"POINT": "Point", "POLYGON": "Polygon", "LINESTRING": "LineString", "MULTIPOLYGON": "MultiPolygon"}
+
+// reSpatialTypesSep splits the value of WITH TYPES option into spatial types.
+var reSpatialTypesSep = regexp.MustCompile(`[,;:\s]+`)
+
+// _policyKeyForIndexKind returns the entry of the indexing policy that holds indexes of the specified kind.
+func _policyKeyForIndexKind(kind string) string {
+	switch kind {
+	case indexKindExcluded:
+		return "excludedPaths"
+	case indexKindComposite:
+		return "compositeIndexes"
+	case indexKindSpatial:
+		return "spatialIndexes"
+	}
+	return "includedPaths"
+}
+
+// _findPathEntry returns the position of the entry {"path":<path>,...} in the list, -1 if not found.
+func _findPathEntry(list []interface{}, path string) int {
+	for i, entry := range list {
+		if m, ok := entry.(map[string]interface{}); ok && m["path"] == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// _findCompositeEntry returns the position of the composite index [{"path":<path>,"order":<order>},...] in the list, -1 if not found.
+func _findCompositeEntry(list []interface{}, paths, orders []string) int {
+	for i, entry := range list {
+		items, ok := entry.([]interface{})
+		if !ok || len(items) != len(paths) {
+			continue
+		}
+		match := true
+		for j, item := range items {
+			m, _ := item.(map[string]interface{})
+			order, _ := m["order"].(string)
+			if order == "" {
+				order = "ascending"
+			}
+			if m["path"] != paths[j] || !strings.EqualFold(order, orders[j]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// _mergeSpatialTypes adds the types missing from the spatial index entry {"path":<path>,"types":[...]}.
+//
+// This function returns false if the entry already has all the types.
+func _mergeSpatialTypes(entry map[string]interface{}, types []string) bool {
+	var merged []string
+	switch existing := entry["types"].(type) {
+	case []string:
+		merged = append(merged, existing...)
+	case []interface{}:
+		for _, typ := range existing {
+			merged = append(merged, fmt.Sprintf("%v", typ))
+		}
+	}
+	added := false
+	for _, typ := range types {
+		found := false
+		for _, existing := range merged {
+			found = found || strings.EqualFold(existing, typ)
+		}
+		if !found {
+			merged, added = append(merged, typ), true
+		}
+	}
+	if added {
+		entry["types"] = merged
+	}
+	return added
+}
+
+// _addIndex adds an index to the indexing policy. Paths of an included (excluded) index are removed from the excluded (included) paths.
+// Types of a spatial index are merged into the existing entry of the same path.
+//
+// This function returns false if the index already exists in the indexing policy.
+func _addIndex(policy map[string]interface{}, kind string, paths, orders, types []string) bool {
+	key := _policyKeyForIndexKind(kind)
+	list, _ := policy[key].([]interface{})
+	added := false
+	if kind == indexKindComposite {
+		if _findCompositeEntry(list, paths, orders) < 0 {
+			items := make([]interface{}, len(paths))
+			for i, path := range paths {
+				items[i] = map[string]interface{}{"path": path, "order": orders[i]}
+			}
+			list, added = append(list, items), true
+		}
+		policy[key] = list
+		return added
+	}
+	for _, path := range paths {
+		if i := _findPathEntry(list, path); i >= 0 {
+			if kind == indexKindSpatial && _mergeSpatialTypes(list[i].(map[string]interface{}), types) {
+				added = true
+			}
+			continue
+		}
+		entry := map[string]interface{}{"path": path}
+		if kind == indexKindSpatial {
+			entry["types"] = types
+		}
+		list, added = append(list, entry), true
+		if kind == indexKindIncluded || kind == indexKindExcluded {
+			// a path must not be both included and excluded
+			otherKey := _policyKeyForIndexKind(indexKindIncluded)
+			if kind == indexKindIncluded {
+				otherKey = _policyKeyForIndexKind(indexKindExcluded)
+			}
+			if other, ok := policy[otherKey].([]interface{}); ok {
+				if i := _findPathEntry(other, path); i >= 0 {
+					policy[otherKey] = append(other[:i:i], other[i+1:]...)
+				}
+			}
+		}
+	}
+	policy[key] = list
+	return added
+}
+
+// _removeIndex removes an index from the indexing policy.
+//
+// This function returns allFound=false if the index (or any of its paths) does not exist in the indexing policy,
+// and anyRemoved=false if nothing has been removed.
+func _removeIndex(policy map[string]interface{}, kind string, paths, orders []string) (allFound, anyRemoved bool) {
+	key := _policyKeyForIndexKind(kind)
+	list, _ := policy[key].([]interface{})
+	if kind == indexKindComposite {
+		i := _findCompositeEntry(list, paths, orders)
+		if i < 0 {
+			return false, false
+		}
+		policy[key] = append(list[:i:i], list[i+1:]...)
+		return true, true
+	}
+	allFound = true
+	for _, path := range paths {
+		if i := _findPathEntry(list, path); i >= 0 {
+			list, anyRemoved = append(list[:i:i], list[i+1:]...), true
+		} else {
+			allFound = false
+		}
+	}
+	policy[key] = list
+	return allFound, anyRemoved
+}
+
+// _validateIndexDef validates the definition of an index supplied to CREATE/DROP INDEX statements.
+func _validateIndexDef(dbName, collName, kind string, paths []string) error {
+	if dbName == "" || collName == "" {
+		return errors.New("database/collection is missing")
+	}
+	if kind == indexKindComposite && len(paths) < 2 {
+		return errors.New("composite index must have at least 2 paths")
+	}
+	for _, path := range paths {
+		if kind == indexKindComposite && (strings.HasSuffix(path, "/?") || strings.HasSuffix(path, "/*")) {
+			return fmt.Errorf("invalid path of composite index: %s", path)
+		}
+	}
+	return nil
+}
+
+// _fetchIndexingPolicy fetches a collection's info, including the indexing policy.
+func _fetchIndexingPolicy(client *RestClient, dbName, collName string) (*CollInfo, error) {
+	getColl := client.GetCollection(dbName, collName)
+	if err := getColl.Error(); err != nil {
+		return nil, normalizeError(getColl.StatusCode, 0, err)
+	}
+	if getColl.IndexingPolicy == nil {
+		getColl.IndexingPolicy = make(map[string]interface{})
+	}
+	return &getColl.CollInfo, nil
+}
+
+// _replaceIndexingPolicy replaces the indexing policy of a collection; the collection's other settings are retained.
+func _replaceIndexingPolicy(client *RestClient, dbName string, collInfo *CollInfo) *ResultNoResultSet {
+	spec := CollectionSpec{DbName: dbName, CollName: collInfo.Id, PartitionKeyInfo: collInfo.PartitionKey, IndexingPolicy: collInfo.IndexingPolicy,
+		ConflictResolutionPolicy: collInfo.ConflictResolutionPolicy, DefaultTtl: collInfo.DefaultTtl}
+	restResult := client.ReplaceCollection(spec)
+	return buildResultNoResultSet(&restResult.RestResponse, true, restResult.Rid, 0)
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtCreateIndex implements "CREATE INDEX" statement, which adds an index to a collection's indexing policy.
+//
+// Syntax:
+//
+//	CREATE [INCLUDED|EXCLUDED|COMPOSITE|SPATIAL] INDEX [IF NOT EXISTS] ON [<db-name>.]<collection-name> (<path>[,<path>...])
+//	[WITH TYPES=<type>[,<type>...]]
+//
+// - INCLUDED (default): the paths are added to the included paths, e.g. CREATE INDEX ON mydb.mytable (/name/?, /address/*).
+//
+// - EXCLUDED: the paths are added to the excluded paths, e.g. CREATE EXCLUDED INDEX ON mydb.mytable (/content/*).
+//
+// - COMPOSITE: a composite index of the paths is added, each path can be followed by ASC (default) or DESC,
+// e.g. CREATE COMPOSITE INDEX ON mydb.mytable (/name ASC, /age DESC).
+//
+// - SPATIAL: the paths are added to the spatial indexes, e.g. CREATE SPATIAL INDEX ON mydb.mytable (/location/*) WITH TYPES=Point,Polygon.
+// Supported types are Point, Polygon, LineString and MultiPolygon (default: all). If a path already has a spatial index,
+// the types are added to the existing ones.
+//
+// - If "IF NOT EXISTS" is specified, Exec will silently swallow the error "409 Conflict" (the index already exists).
+//
+// The statement replaces the collection's indexing policy. Cosmos DB then re-indexes the collection in background,
+// see RestClient.WaitForIndexTransformation to track the progress.
+//
+// @Available since v1.2.0
+type StmtCreateIndex struct {
+	*Stmt
+	indexKind    string // one of indexKind... constants
+	dbName       string
+	collName     string
+	paths        []string
+	orders       []string // (composite index) order of each path, "ascending" or "descending"
+	spatialTypes []string // (spatial index) spatial types
+	ifNotExists  bool
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtCreateIndex) String() string {
+	return fmt.Sprintf(`StmtCreateIndex{Stmt: %s, kind: %q, db: %q, collection: %q, paths: %q, orders: %q, types: %q, if_not_exists: %t}`,
+		s.Stmt, s.indexKind, s.dbName, s.collName, s.paths, s.orders, s.spatialTypes, s.ifNotExists)
+}
+
+func (s *StmtCreateIndex) parse(opts []astWithOpt) error {
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
+		return err
+	}
+
+	for k, v := range s.withOpts {
+		switch k {
+		case "TYPES":
+			if s.indexKind != indexKindSpatial {
+				return errors.New("WITH TYPES is only supported for spatial index")
+			}
+			for _, token := range reSpatialTypesSep.Split(v, -1) {
+				typ, ok := spatialTypes[strings.ToUpper(token)]
+				if !ok {
+					return fmt.Errorf("invalid TYPES value: %s", v)
+				}
+				s.spatialTypes = append(s.spatialTypes, typ)
+			}
+		default:
+			return fmt.Errorf("invalid query, parsing error at WITH %s=%s", k, v)
+		}
+	}
+	if s.indexKind == indexKindSpatial && len(s.spatialTypes) == 0 {
+		s.spatialTypes = []string{"Point", "Polygon", "LineString", "MultiPolygon"}
+	}
+
+	return nil
+}
+
+func (s *StmtCreateIndex) validate() error {
+	return _validateIndexDef(s.dbName, s.collName, s.indexKind, s.paths)
+}
+
+// Query implements driver.Stmt/Query.
+// This function is not implemented, use Exec instead.
+func (s *StmtCreateIndex) Query(_ []driver.Value) (driver.Rows, error) {
+	return nil, ErrQueryNotSupported
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtCreateIndex) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtCreateIndex) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected 0 input value, got %d", len(args))
+	}
+
+	// TODO: pass ctx to REST API client
+	collInfo, err := _fetchIndexingPolicy(s.conn.restClient, s.dbName, s.collName)
+	if err != nil {
+		return nil, err
+	}
+	if !_addIndex(collInfo.IndexingPolicy, s.indexKind, s.paths, s.orders, s.spatialTypes) {
+		if s.ifNotExists {
+			return &ResultNoResultSet{supportLastInsertId: true, lastInsertId: collInfo.Rid}, nil
+		}
+		return nil, ErrConflict
+	}
+	result := _replaceIndexingPolicy(s.conn.restClient, s.dbName, collInfo)
+	return result, result.err
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtDropIndex implements "DROP INDEX" statement, which removes an index from a collection's indexing policy.
+//
+// Syntax:
+//
+//	DROP [INCLUDED|EXCLUDED|COMPOSITE|SPATIAL] INDEX [IF EXISTS] ON [<db-name>.]<collection-name> (<path>[,<path>...])
+//
+// - The index is identified by its kind and paths, see StmtCreateIndex. A composite index must match the paths and their orders.
+//
+// - If "IF EXISTS" is specified, Exec will silently swallow the error "404 Not Found" (the index, or any of its paths, does not exist).
+//
+// @Available since v1.2.0
+type StmtDropIndex struct {
+	*Stmt
+	indexKind string // one of indexKind... constants
+	dbName    string
+	collName  string
+	paths     []string
+	orders    []string // (composite index) order of each path, "ascending" or "descending"
+	ifExists  bool
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtDropIndex) String() string {
+	return fmt.Sprintf(`StmtDropIndex{Stmt: %s, kind: %q, db: %q, collection: %q, paths: %q, orders: %q, if_exists: %t}`,
+		s.Stmt, s.indexKind, s.dbName, s.collName, s.paths, s.orders, s.ifExists)
+}
+
+func (s *StmtDropIndex) validate() error {
+	return _validateIndexDef(s.dbName, s.collName, s.indexKind, s.paths)
+}
+
+// Query implements driver.Stmt/Query.
+// This function is not implemented, use Exec instead.
+func (s *StmtDropIndex) Query(_ []driver.Value) (driver.Rows, error) {
+	return nil, ErrQueryNotSupported
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtDropIndex) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtDropIndex) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected 0 input value, got %d", len(args))
+	}
+
+	// TODO: pass ctx to REST API client
+	collInfo, err := _fetchIndexingPolicy(s.conn.restClient, s.dbName, s.collName)
+	if err != nil {
+		return nil, err
+	}
+	allFound, anyRemoved := _removeIndex(collInfo.IndexingPolicy, s.indexKind, s.paths, s.orders)
+	if !allFound && !s.ifExists {
+		return nil, ErrNotFound
+	}
+	if !anyRemoved {
+		return &ResultNoResultSet{supportLastInsertId: true, lastInsertId: collInfo.Rid}, nil
+	}
+	result := _replaceIndexingPolicy(s.conn.restClient, s.dbName, collInfo)
+	return result, result.err
+}
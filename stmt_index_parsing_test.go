@@ -0,0 +1,175 @@
+package gocosmos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStmtCreateIndex_parse(t *testing.T) {
+	testName := "TestStmtCreateIndex_parse"
+	testData := []struct {
+		name      string
+		sql       string
+		expected  *StmtCreateIndex
+		mustError bool
+	}{
+		{name: "error_no_on", sql: "CREATE INDEX db.coll (/name/?)", mustError: true},
+		{name: "error_no_paths", sql: "CREATE INDEX ON db.coll ()", mustError: true},
+		{name: "error_no_collection", sql: "CREATE INDEX ON coll (/name/?)", mustError: true},
+		{name: "error_invalid_path", sql: "CREATE INDEX ON db.coll (name)", mustError: true},
+		{name: "error_missing_close_paren", sql: "CREATE INDEX ON db.coll (/name/?", mustError: true},
+		{name: "error_composite_single_path", sql: "CREATE COMPOSITE INDEX ON db.coll (/name)", mustError: true},
+		{name: "error_composite_wildcard_path", sql: "CREATE COMPOSITE INDEX ON db.coll (/name/?, /age/?)", mustError: true},
+		{name: "error_types_not_spatial", sql: "CREATE INDEX ON db.coll (/name/?) WITH TYPES=Point", mustError: true},
+		{name: "error_invalid_types", sql: "CREATE SPATIAL INDEX ON db.coll (/loc/*) WITH TYPES=Circle", mustError: true},
+		{name: "error_invalid_with", sql: "CREATE INDEX ON db.coll (/name/?) WITH a=1", mustError: true},
+		{name: "error_missing_index_keyword", sql: "CREATE COMPOSITE ON db.coll (/name, /age)", mustError: true},
+
+		{name: "included", sql: "CREATE INDEX ON db1.coll1 (/name/?)", expected: &StmtCreateIndex{indexKind: indexKindIncluded, dbName: "db1", collName: "coll1", paths: []string{"/name/?"}}},
+		{name: "included_multiple_paths", sql: "create included index if not exists on db1.coll1 (/name/?,\n/address/*, /tags/[]/?)", expected: &StmtCreateIndex{indexKind: indexKindIncluded, dbName: "db1", collName: "coll1", paths: []string{"/name/?", "/address/*", "/tags/[]/?"}, ifNotExists: true}},
+		{name: "excluded", sql: "CREATE EXCLUDED INDEX ON db1.coll1 (/content/*)", expected: &StmtCreateIndex{indexKind: indexKindExcluded, dbName: "db1", collName: "coll1", paths: []string{"/content/*"}}},
+		{name: "composite", sql: "CREATE COMPOSITE INDEX ON db1.coll1 (/name, /age DESC, /city ASC)", expected: &StmtCreateIndex{indexKind: indexKindComposite, dbName: "db1", collName: "coll1", paths: []string{"/name", "/age", "/city"}, orders: []string{"ascending", "descending", "ascending"}}},
+		{name: "spatial", sql: "CREATE SPATIAL INDEX ON db1.coll1 (/location/*)", expected: &StmtCreateIndex{indexKind: indexKindSpatial, dbName: "db1", collName: "coll1", paths: []string{"/location/*"}, spatialTypes: []string{"Point", "Polygon", "LineString", "MultiPolygon"}}},
+		{name: "spatial_with_types", sql: "CREATE SPATIAL INDEX ON db1.coll1 (/location/*) WITH TYPES=point,Polygon", expected: &StmtCreateIndex{indexKind: indexKindSpatial, dbName: "db1", collName: "coll1", paths: []string{"/location/*"}, spatialTypes: []string{"Point", "Polygon"}}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, "", testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s\n%s", testName+"/"+testCase.name, err, testCase.sql)
+			}
+			stmt, ok := s.(*StmtCreateIndex)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtCreateIndex but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = nil
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestStmtDropIndex_parse(t *testing.T) {
+	testName := "TestStmtDropIndex_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtDropIndex
+		mustError bool
+	}{
+		{name: "error_no_paths", sql: "DROP INDEX ON db.coll", mustError: true},
+		{name: "error_no_collection", sql: "DROP INDEX ON coll (/name/?)", mustError: true},
+		{name: "error_if_exist", sql: "DROP INDEX IF EXIST ON db.coll (/name/?)", mustError: true},
+		{name: "error_composite_single_path", sql: "DROP COMPOSITE INDEX ON db.coll (/name DESC)", mustError: true},
+
+		{name: "included", sql: "DROP INDEX ON db1.coll1 (/name/?)", expected: &StmtDropIndex{indexKind: indexKindIncluded, dbName: "db1", collName: "coll1", paths: []string{"/name/?"}}},
+		{name: "excluded_if_exists", sql: "drop excluded index if exists on db1.coll1 (/content/*)", expected: &StmtDropIndex{indexKind: indexKindExcluded, dbName: "db1", collName: "coll1", paths: []string{"/content/*"}, ifExists: true}},
+		{name: "composite", sql: "DROP COMPOSITE INDEX ON db1.coll1 (/name ASC, /age DESC)", expected: &StmtDropIndex{indexKind: indexKindComposite, dbName: "db1", collName: "coll1", paths: []string{"/name", "/age"}, orders: []string{"ascending", "descending"}}},
+		{name: "spatial_default_db", db: "mydb", sql: "DROP SPATIAL INDEX ON coll1 (/location/*)", expected: &StmtDropIndex{indexKind: indexKindSpatial, dbName: "mydb", collName: "coll1", paths: []string{"/location/*"}}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s\n%s", testName+"/"+testCase.name, err, testCase.sql)
+			}
+			stmt, ok := s.(*StmtDropIndex)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtDropIndex but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = nil
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func Test_addRemoveIndex(t *testing.T) {
+	testName := "Test_addRemoveIndex"
+	policy := map[string]interface{}{
+		"indexingMode":  "consistent",
+		"includedPaths": []interface{}{map[string]interface{}{"path": "/*"}},
+		"excludedPaths": []interface{}{map[string]interface{}{"path": "/name/?"}, map[string]interface{}{"path": "/\"_etag\"/?"}},
+	}
+
+	if !_addIndex(policy, indexKindIncluded, []string{"/name/?"}, nil, nil) {
+		t.Fatalf("%s failed: included index must be added", testName)
+	}
+	if _addIndex(policy, indexKindIncluded, []string{"/name/?", "/*"}, nil, nil) {
+		t.Fatalf("%s failed: existing included index must not be added", testName)
+	}
+	expected := map[string]interface{}{
+		"indexingMode":  "consistent",
+		"includedPaths": []interface{}{map[string]interface{}{"path": "/*"}, map[string]interface{}{"path": "/name/?"}},
+		"excludedPaths": []interface{}{map[string]interface{}{"path": "/\"_etag\"/?"}},
+	}
+	if !reflect.DeepEqual(policy, expected) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expected, policy)
+	}
+
+	paths, orders := []string{"/name", "/age"}, []string{"ascending", "descending"}
+	if !_addIndex(policy, indexKindComposite, paths, orders, nil) || _addIndex(policy, indexKindComposite, paths, orders, nil) {
+		t.Fatalf("%s failed: composite index must be added exactly once", testName)
+	}
+	if !_addIndex(policy, indexKindComposite, paths, []string{"ascending", "ascending"}, nil) {
+		t.Fatalf("%s failed: composite index with different orders must be added", testName)
+	}
+	if !_addIndex(policy, indexKindSpatial, []string{"/location/*"}, nil, []string{"Point"}) {
+		t.Fatalf("%s failed: spatial index must be added", testName)
+	}
+	expectedSpatial := []interface{}{map[string]interface{}{"path": "/location/*", "types": []string{"Point"}}}
+	if !reflect.DeepEqual(policy["spatialIndexes"], expectedSpatial) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expectedSpatial, policy["spatialIndexes"])
+	}
+	if _addIndex(policy, indexKindSpatial, []string{"/location/*"}, nil, []string{"Point"}) {
+		t.Fatalf("%s failed: existing spatial index must not be added", testName)
+	}
+	if !_addIndex(policy, indexKindSpatial, []string{"/location/*"}, nil, []string{"Point", "Polygon"}) {
+		t.Fatalf("%s failed: spatial types must be merged", testName)
+	}
+	expectedSpatial = []interface{}{map[string]interface{}{"path": "/location/*", "types": []string{"Point", "Polygon"}}}
+	if !reflect.DeepEqual(policy["spatialIndexes"], expectedSpatial) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expectedSpatial, policy["spatialIndexes"])
+	}
+	// indexing policy fetched from server holds types as []interface{}
+	serverPolicy := map[string]interface{}{"spatialIndexes": []interface{}{map[string]interface{}{"path": "/geo/*", "types": []interface{}{"Point"}}}}
+	if _addIndex(serverPolicy, indexKindSpatial, []string{"/geo/*"}, nil, []string{"point"}) || !_addIndex(serverPolicy, indexKindSpatial, []string{"/geo/*"}, nil, []string{"LineString"}) {
+		t.Fatalf("%s failed: spatial types must be merged with the ones fetched from server", testName)
+	}
+	expectedSpatial = []interface{}{map[string]interface{}{"path": "/geo/*", "types": []string{"Point", "LineString"}}}
+	if !reflect.DeepEqual(serverPolicy["spatialIndexes"], expectedSpatial) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expectedSpatial, serverPolicy["spatialIndexes"])
+	}
+
+	if allFound, anyRemoved := _removeIndex(policy, indexKindComposite, paths, []string{"descending", "ascending"}); allFound || anyRemoved {
+		t.Fatalf("%s failed: non-existing composite index must not be removed", testName)
+	}
+	if allFound, anyRemoved := _removeIndex(policy, indexKindComposite, paths, orders); !allFound || !anyRemoved {
+		t.Fatalf("%s failed: composite index must be removed", testName)
+	}
+	expectedComposite := []interface{}{[]interface{}{map[string]interface{}{"path": "/name", "order": "ascending"}, map[string]interface{}{"path": "/age", "order": "ascending"}}}
+	if !reflect.DeepEqual(policy["compositeIndexes"], expectedComposite) {
+		t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName, expectedComposite, policy["compositeIndexes"])
+	}
+	if allFound, anyRemoved := _removeIndex(policy, indexKindExcluded, []string{"/\"_etag\"/?", "/content/*"}, nil); allFound || !anyRemoved {
+		t.Fatalf("%s failed: existing paths must be removed, non-existing paths must be reported", testName)
+	}
+	if allFound, _ := _removeIndex(policy, indexKindSpatial, []string{"/location/*"}, nil); !allFound || len(policy["spatialIndexes"].([]interface{})) != 0 {
+		t.Fatalf("%s failed: spatial index must be removed", testName)
+	}
+}
@@ -0,0 +1,329 @@
+package gocosmos
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	scriptTypeProcedure = "PROCEDURE"
+	scriptTypeFunction  = "FUNCTION"
+	scriptTypeTrigger   = "TRIGGER"
+)
+
+var (
+	triggerTypes      = map[string]string{"PRE": "Pre", "POST": "Post"}
+	triggerOperations = map[string]string{"ALL": "All", "CREATE": "Create", "REPLACE": "Replace", "DELETE": "Delete"}
+)
+
+// _normalizeScriptType converts the script type keyword found in the query (e.g. "PROCEDURES") to one of the
+// scriptType... constants.
+func _normalizeScriptType(input string) string {
+	return strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(input)), "S")
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtCreateScript implements "CREATE PROCEDURE|FUNCTION|TRIGGER" statement.
+//
+// Syntax:
+//
+//	CREATE [OR REPLACE] PROCEDURE|FUNCTION|TRIGGER [<db-name>.]<collection-name>.<script-name> AS '<body>'|@1
+//		[WITH TYPE=PRE|POST[, OPERATION=ALL|CREATE|REPLACE|DELETE]]
+//
+// - body: JavaScript code of the stored procedure, user-defined function or trigger. Single quotes inside the body
+// must be escaped by doubling them. Alternatively, the body can be supplied via a placeholder.
+//
+// - TYPE (required for triggers only): PRE or POST trigger.
+//
+// - OPERATION (triggers only): the operation that fires the trigger, default value is ALL.
+//
+// - If "OR REPLACE" is specified, Exec will replace the existing script if it already exists.
+//
+// @Available since v1.2.0
+type StmtCreateScript struct {
+	*Stmt
+	scriptType       string // one of PROCEDURE, FUNCTION or TRIGGER
+	orReplace        bool
+	dbName           string
+	collName         string
+	scriptName       string
+	body             interface{} // script body, either a string or a placeholder
+	triggerType      string
+	triggerOperation string
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtCreateScript) String() string {
+	return fmt.Sprintf(`StmtCreateScript{Stmt: %s, type: %q, or_replace: %t, db: %q, collection: %q, name: %q, trigger_type: %q, trigger_operation: %q}`,
+		s.Stmt, s.scriptType, s.orReplace, s.dbName, s.collName, s.scriptName, s.triggerType, s.triggerOperation)
+}
+
+func (s *StmtCreateScript) parse(body interface{}, opts []astWithOpt) error {
+	s.body = body
+	if _, ok := s.body.(placeholder); ok {
+		s.numInputs = 1
+	}
+
+	if err := s.Stmt.parseWithOpts(opts); err != nil {
+		return err
+	}
+	for k, v := range s.withOpts {
+		switch k {
+		case "TYPE":
+			triggerType, ok := triggerTypes[strings.ToUpper(v)]
+			if !ok || s.scriptType != scriptTypeTrigger {
+				return fmt.Errorf("invalid query, parsing error at WITH %s=%s", k, v)
+			}
+			s.triggerType = triggerType
+		case "OPERATION":
+			triggerOperation, ok := triggerOperations[strings.ToUpper(v)]
+			if !ok || s.scriptType != scriptTypeTrigger {
+				return fmt.Errorf("invalid query, parsing error at WITH %s=%s", k, v)
+			}
+			s.triggerOperation = triggerOperation
+		default:
+			return fmt.Errorf("invalid query, parsing error at WITH %s=%s", k, v)
+		}
+	}
+	if s.scriptType == scriptTypeTrigger && s.triggerOperation == "" {
+		s.triggerOperation = triggerOperations["ALL"]
+	}
+
+	return nil
+}
+
+func (s *StmtCreateScript) validate() error {
+	if s.dbName == "" || s.collName == "" || s.scriptName == "" {
+		return errors.New("database/collection/script name is missing")
+	}
+	if s.scriptType == scriptTypeTrigger && s.triggerType == "" {
+		return errors.New("trigger type (WITH TYPE=PRE|POST) must be specified")
+	}
+	return nil
+}
+
+// Query implements driver.Stmt/Query.
+// This function is not implemented, use Exec instead.
+func (s *StmtCreateScript) Query(_ []driver.Value) (driver.Rows, error) {
+	return nil, ErrQueryNotSupported
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtCreateScript) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtCreateScript) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	args, err := s.bindArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != s.numInputs {
+		return nil, fmt.Errorf("expected %d input values, got %d", s.numInputs, len(args))
+	}
+	body, ok := s.body.(string)
+	if p, isPlaceholder := s.body.(placeholder); isPlaceholder {
+		if p.index <= 0 || p.index > len(args) {
+			return nil, fmt.Errorf("invalid value index %d", p.index)
+		}
+		body, ok = args[p.index-1].Value.(string)
+	}
+	if !ok {
+		return nil, errors.New("script body must be a string")
+	}
+
+	// TODO: pass ctx to REST API client
+	var restResponse *RestResponse
+	var rid string
+	switch s.scriptType {
+	case scriptTypeProcedure:
+		spec := SprocSpec{DbName: s.dbName, CollName: s.collName, Id: s.scriptName, Body: body}
+		restResult := s.conn.restClient.CreateSproc(spec)
+		if restResult.StatusCode == 409 && s.orReplace {
+			replaceResult := s.conn.restClient.ReplaceSproc(spec)
+			restResponse, rid = &replaceResult.RestResponse, replaceResult.Rid
+		} else {
+			restResponse, rid = &restResult.RestResponse, restResult.Rid
+		}
+	case scriptTypeFunction:
+		spec := UdfSpec{DbName: s.dbName, CollName: s.collName, Id: s.scriptName, Body: body}
+		restResult := s.conn.restClient.CreateUdf(spec)
+		if restResult.StatusCode == 409 && s.orReplace {
+			replaceResult := s.conn.restClient.ReplaceUdf(spec)
+			restResponse, rid = &replaceResult.RestResponse, replaceResult.Rid
+		} else {
+			restResponse, rid = &restResult.RestResponse, restResult.Rid
+		}
+	default:
+		spec := TriggerSpec{DbName: s.dbName, CollName: s.collName, Id: s.scriptName, Body: body,
+			TriggerType: s.triggerType, TriggerOperation: s.triggerOperation}
+		restResult := s.conn.restClient.CreateTrigger(spec)
+		if restResult.StatusCode == 409 && s.orReplace {
+			replaceResult := s.conn.restClient.ReplaceTrigger(spec)
+			restResponse, rid = &replaceResult.RestResponse, replaceResult.Rid
+		} else {
+			restResponse, rid = &restResult.RestResponse, restResult.Rid
+		}
+	}
+	result := buildResultNoResultSet(restResponse, true, rid, 0)
+	return result, result.err
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtDropScript implements "DROP PROCEDURE|FUNCTION|TRIGGER" statement.
+//
+// Syntax:
+//
+//	DROP PROCEDURE|FUNCTION|TRIGGER [IF EXISTS] [<db-name>.]<collection-name>.<script-name>
+//
+// - If "IF EXISTS" is specified, Exec will silently swallow the error "404 Not Found".
+//
+// @Available since v1.2.0
+type StmtDropScript struct {
+	*Stmt
+	scriptType string // one of PROCEDURE, FUNCTION or TRIGGER
+	dbName     string
+	collName   string
+	scriptName string
+	ifExists   bool
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtDropScript) String() string {
+	return fmt.Sprintf(`StmtDropScript{Stmt: %s, type: %q, db: %q, collection: %q, name: %q, if_exists: %t}`,
+		s.Stmt, s.scriptType, s.dbName, s.collName, s.scriptName, s.ifExists)
+}
+
+func (s *StmtDropScript) validate() error {
+	if s.dbName == "" || s.collName == "" || s.scriptName == "" {
+		return errors.New("database/collection/script name is missing")
+	}
+	return nil
+}
+
+// Query implements driver.Stmt/Query.
+// This function is not implemented, use Exec instead.
+func (s *StmtDropScript) Query(_ []driver.Value) (driver.Rows, error) {
+	return nil, ErrQueryNotSupported
+}
+
+// Exec implements driver.Stmt/Exec.
+func (s *StmtDropScript) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext/ExecContext.
+func (s *StmtDropScript) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected 0 input value, got %d", len(args))
+	}
+
+	// TODO: pass ctx to REST API client
+	var restResponse *RestResponse
+	switch s.scriptType {
+	case scriptTypeProcedure:
+		restResponse = &s.conn.restClient.DeleteSproc(s.dbName, s.collName, s.scriptName).RestResponse
+	case scriptTypeFunction:
+		restResponse = &s.conn.restClient.DeleteUdf(s.dbName, s.collName, s.scriptName).RestResponse
+	default:
+		restResponse = &s.conn.restClient.DeleteTrigger(s.dbName, s.collName, s.scriptName).RestResponse
+	}
+	ignoreErrorCode := 0
+	if s.ifExists {
+		ignoreErrorCode = 404
+	}
+	result := buildResultNoResultSet(restResponse, false, "", ignoreErrorCode)
+	return result, result.err
+}
+
+/*----------------------------------------------------------------------*/
+
+// StmtListScripts implements "LIST PROCEDURES|FUNCTIONS|TRIGGERS" statement.
+//
+// Syntax:
+//
+//	LIST PROCEDURES|PROCEDURE|FUNCTIONS|FUNCTION|TRIGGERS|TRIGGER FROM [<db-name>.]<collection-name>
+//
+// @Available since v1.2.0
+type StmtListScripts struct {
+	*Stmt
+	scriptType string // one of PROCEDURE, FUNCTION or TRIGGER
+	dbName     string
+	collName   string
+}
+
+// String implements fmt.Stringer/String.
+func (s *StmtListScripts) String() string {
+	return fmt.Sprintf(`StmtListScripts{Stmt: %s, type: %q, db: %q, collection: %q}`,
+		s.Stmt, s.scriptType, s.dbName, s.collName)
+}
+
+func (s *StmtListScripts) validate() error {
+	if s.dbName == "" || s.collName == "" {
+		return errors.New("database/collection is missing")
+	}
+	return nil
+}
+
+// Exec implements driver.Stmt/Exec.
+// This function is not implemented, use Query instead.
+func (s *StmtListScripts) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, ErrExecNotSupported
+}
+
+// Query implements driver.Stmt/Query.
+func (s *StmtListScripts) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), _valuesToNamedValues(args))
+}
+
+// QueryContext implements driver.StmtQueryContext/QueryContext.
+func (s *StmtListScripts) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected 0 input value, got %d", len(args))
+	}
+
+	// TODO: pass ctx to REST API client
+	result := &ResultResultSet{columnList: []string{"id", "body", "_rid", "_ts", "_self", "_etag"}}
+	var statusCode int
+	switch s.scriptType {
+	case scriptTypeProcedure:
+		restResult := s.conn.restClient.ListSprocs(s.dbName, s.collName)
+		if result.err, statusCode = restResult.Error(), restResult.StatusCode; result.err == nil {
+			result.rows = make([]DocInfo, len(restResult.Sprocs))
+			for i, sproc := range restResult.Sprocs {
+				result.rows[i] = sproc.toMap()
+			}
+		}
+	case scriptTypeFunction:
+		restResult := s.conn.restClient.ListUdfs(s.dbName, s.collName)
+		if result.err, statusCode = restResult.Error(), restResult.StatusCode; result.err == nil {
+			result.rows = make([]DocInfo, len(restResult.Udfs))
+			for i, udf := range restResult.Udfs {
+				result.rows[i] = udf.toMap()
+			}
+		}
+	default:
+		result.columnList = []string{"id", "body", "triggerType", "triggerOperation", "_rid", "_ts", "_self", "_etag"}
+		restResult := s.conn.restClient.ListTriggers(s.dbName, s.collName)
+		if result.err, statusCode = restResult.Error(), restResult.StatusCode; result.err == nil {
+			result.rows = make([]DocInfo, len(restResult.Triggers))
+			for i, trigger := range restResult.Triggers {
+				result.rows[i] = trigger.toMap()
+			}
+		}
+	}
+	result.count = len(result.rows)
+	switch statusCode {
+	case 403:
+		result.err = ErrForbidden
+	case 404:
+		result.err = ErrNotFound
+	}
+	return result, result.err
+}
@@ -0,0 +1,142 @@
+package gocosmos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStmtCreateScript_parse(t *testing.T) {
+	testName := "TestStmtCreateScript_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtCreateScript
+		mustError bool
+	}{
+		{name: "error_no_collection", sql: "CREATE PROCEDURE sp1 AS 'function(){}'", mustError: true},
+		{name: "error_no_db", sql: "CREATE PROCEDURE coll.sp1 AS 'function(){}'", mustError: true},
+		{name: "error_no_body", sql: "CREATE FUNCTION db.coll.udf1", mustError: true},
+		{name: "error_unquoted_body", sql: "CREATE FUNCTION db.coll.udf1 AS function(){}", mustError: true},
+		{name: "error_with_on_procedure", sql: "CREATE PROCEDURE db.coll.sp1 AS 'function(){}' WITH TYPE=PRE", mustError: true},
+		{name: "error_trigger_no_type", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}'", mustError: true},
+		{name: "error_trigger_invalid_type", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}' WITH TYPE=MIDDLE", mustError: true},
+		{name: "error_trigger_invalid_operation", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}' WITH TYPE=PRE, OPERATION=READ", mustError: true},
+		{name: "error_trigger_invalid_with", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}' WITH TYPE=PRE WITH a=1", mustError: true},
+
+		{name: "procedure", sql: "CREATE PROCEDURE db1.coll1.sp1 AS 'function() { var x = ''a''; }'", expected: &StmtCreateScript{scriptType: "PROCEDURE", dbName: "db1", collName: "coll1", scriptName: "sp1", body: "function() { var x = 'a'; }"}},
+		{name: "or_replace_function", sql: "create\nor\treplace function db-2.coll_2.udf-2 as\r\n'function(x) { return x; }'", expected: &StmtCreateScript{scriptType: "FUNCTION", orReplace: true, dbName: "db-2", collName: "coll_2", scriptName: "udf-2", body: "function(x) { return x; }"}},
+		{name: "procedure_placeholder", sql: "CREATE OR REPLACE PROCEDURE db.coll.sp AS @1", expected: &StmtCreateScript{Stmt: &Stmt{numInputs: 1}, scriptType: "PROCEDURE", orReplace: true, dbName: "db", collName: "coll", scriptName: "sp", body: placeholder{1}}},
+		{name: "trigger_default_operation", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}' WITH type=post", expected: &StmtCreateScript{scriptType: "TRIGGER", dbName: "db", collName: "coll", scriptName: "t1", body: "function(){}", triggerType: "Post", triggerOperation: "All"}},
+		{name: "trigger_with_operation", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}' WITH TYPE=PRE, OPERATION=create", expected: &StmtCreateScript{scriptType: "TRIGGER", dbName: "db", collName: "coll", scriptName: "t1", body: "function(){}", triggerType: "Pre", triggerOperation: "Create"}},
+		{name: "trigger_with_operation_repeat_with", sql: "CREATE TRIGGER db.coll.t1 AS 'function(){}' WITH OPERATION=Delete WITH TYPE=Pre", expected: &StmtCreateScript{scriptType: "TRIGGER", dbName: "db", collName: "coll", scriptName: "t1", body: "function(){}", triggerType: "Pre", triggerOperation: "Delete"}},
+		{name: "default_db", db: "mydb", sql: "CREATE FUNCTION coll.udf1 AS 'function(){}'", expected: &StmtCreateScript{scriptType: "FUNCTION", dbName: "mydb", collName: "coll", scriptName: "udf1", body: "function(){}"}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s\n%s", testName+"/"+testCase.name, err, testCase.sql)
+			}
+			stmt, ok := s.(*StmtCreateScript)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtCreateScript but received %T", testName+"/"+testCase.name, s)
+			}
+			if testCase.expected.Stmt != nil {
+				stmt.Stmt = &Stmt{numInputs: stmt.numInputs}
+			} else {
+				stmt.Stmt = nil
+			}
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestStmtDropScript_parse(t *testing.T) {
+	testName := "TestStmtDropScript_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtDropScript
+		mustError bool
+	}{
+		{name: "error_no_collection", sql: "DROP PROCEDURE sp1", mustError: true},
+		{name: "error_no_db", sql: "DROP TRIGGER coll.t1", mustError: true},
+		{name: "error_if_exist", sql: "DROP FUNCTION IF EXIST db.coll.udf1", mustError: true},
+
+		{name: "procedure", sql: "DROP PROCEDURE db1.coll1.sp1", expected: &StmtDropScript{scriptType: "PROCEDURE", dbName: "db1", collName: "coll1", scriptName: "sp1"}},
+		{name: "function_if_exists", sql: "drop\nfunction\tif\r\nexists db-2.coll_2.udf-2", expected: &StmtDropScript{scriptType: "FUNCTION", dbName: "db-2", collName: "coll_2", scriptName: "udf-2", ifExists: true}},
+		{name: "trigger_default_db", db: "mydb", sql: "DROP TRIGGER IF EXISTS coll.t1", expected: &StmtDropScript{scriptType: "TRIGGER", dbName: "mydb", collName: "coll", scriptName: "t1", ifExists: true}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s\n%s", testName+"/"+testCase.name, err, testCase.sql)
+			}
+			stmt, ok := s.(*StmtDropScript)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtDropScript but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = nil
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestStmtListScripts_parse(t *testing.T) {
+	testName := "TestStmtListScripts_parse"
+	testData := []struct {
+		name      string
+		db        string
+		sql       string
+		expected  *StmtListScripts
+		mustError bool
+	}{
+		{name: "error_no_collection", sql: "LIST PROCEDURES", mustError: true},
+		{name: "error_no_db", sql: "LIST TRIGGERS FROM coll", mustError: true},
+
+		{name: "procedures", sql: "LIST PROCEDURES FROM db1.coll1", expected: &StmtListScripts{scriptType: "PROCEDURE", dbName: "db1", collName: "coll1"}},
+		{name: "function", sql: "list\nfunction\tfrom\r\ndb-2.coll_2", expected: &StmtListScripts{scriptType: "FUNCTION", dbName: "db-2", collName: "coll_2"}},
+		{name: "triggers_default_db", db: "mydb", sql: "LIST TRIGGERS FROM coll", expected: &StmtListScripts{scriptType: "TRIGGER", dbName: "mydb", collName: "coll"}},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := parseQueryWithDefaultDb(nil, testCase.db, testCase.sql)
+			if testCase.mustError && err == nil {
+				t.Fatalf("%s failed: parsing must fail", testName+"/"+testCase.name)
+			}
+			if testCase.mustError {
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s\n%s", testName+"/"+testCase.name, err, testCase.sql)
+			}
+			stmt, ok := s.(*StmtListScripts)
+			if !ok {
+				t.Fatalf("%s failed: expected StmtListScripts but received %T", testName+"/"+testCase.name, s)
+			}
+			stmt.Stmt = nil
+			if !reflect.DeepEqual(stmt, testCase.expected) {
+				t.Fatalf("%s failed:\nexpected %#v\nreceived %#v", testName+"/"+testCase.name, testCase.expected, stmt)
+			}
+		})
+	}
+}
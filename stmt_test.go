@@ -0,0 +1,152 @@
+package gocosmos
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStmt_bindArgs(t *testing.T) {
+	testName := "TestStmt_bindArgs"
+	testData := []struct {
+		name       string
+		paramNames []string
+		args       []driver.NamedValue
+		expected   []interface{}
+		mustError  bool
+	}{
+		{name: "positional", args: []driver.NamedValue{{Ordinal: 1, Value: "a"}, {Ordinal: 2, Value: 2}}, expected: []interface{}{"a", 2}},
+		{name: "named", paramNames: []string{"id", "name"},
+			args:     []driver.NamedValue{{Name: "name", Ordinal: 1, Value: "Tom"}, {Name: "id", Ordinal: 2, Value: "1"}},
+			expected: []interface{}{"1", "Tom"}},
+		{name: "named_extra_values", paramNames: []string{"id"},
+			args:     []driver.NamedValue{{Name: "id", Ordinal: 1, Value: "1"}, {Ordinal: 2, Value: "p"}},
+			expected: []interface{}{"1", "p"}},
+		{name: "error_missing_name", paramNames: []string{"id", "name"}, args: []driver.NamedValue{{Name: "id", Ordinal: 1, Value: "1"}}, mustError: true},
+		{name: "error_unknown_name", paramNames: []string{"id"},
+			args: []driver.NamedValue{{Name: "pkk", Ordinal: 1, Value: "p"}, {Name: "id", Ordinal: 2, Value: "1"}}, mustError: true},
+		{name: "error_named_after_positional", paramNames: []string{"id"},
+			args: []driver.NamedValue{{Ordinal: 1, Value: "p"}, {Name: "id", Ordinal: 2, Value: "1"}}, mustError: true},
+		{name: "error_positional_for_named", paramNames: []string{"id"}, args: []driver.NamedValue{{Ordinal: 1, Value: "1"}}, mustError: true},
+		{name: "error_named_for_positional", args: []driver.NamedValue{{Name: "id", Ordinal: 1, Value: "1"}}, mustError: true},
+	}
+	for _, testCase := range testData {
+		t.Run(testCase.name, func(t *testing.T) {
+			s := &Stmt{paramNames: testCase.paramNames}
+			args, err := s.bindArgs(testCase.args)
+			if testCase.mustError {
+				if err == nil {
+					t.Fatalf("%s failed: binding must fail", testName+"/"+testCase.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			values := make([]interface{}, len(args))
+			for i, arg := range args {
+				values[i] = arg.Value
+			}
+			if !reflect.DeepEqual(values, testCase.expected) {
+				t.Fatalf("%s failed: expected %v but received %v", testName+"/"+testCase.name, testCase.expected, values)
+			}
+		})
+	}
+}
+
+func Test_explainQueryPlan(t *testing.T) {
+	testName := "Test_explainQueryPlan"
+	queryPlan := &RespQueryPlan{}
+	queryPlan.QueryInfo.DistinctType = "None"
+	queryPlan.QueryInfo.OrderBy = []string{"Ascending", "Descending"}
+	queryPlan.QueryInfo.OrderByExpressions = []string{"c.a", "c.b"}
+	queryPlan.QueryInfo.RewrittenQuery = "SELECT ..."
+	queryPlan.QueryInfo.Top = 10
+
+	row := _explainQueryPlan(queryPlan, 2)
+	if expected := []string{"c.a ASC", "c.b DESC"}; !reflect.DeepEqual(row["order_by"], expected) {
+		t.Fatalf("%s failed: expected order_by %#v but received %#v", testName, expected, row["order_by"])
+	}
+	if row["distinct_type"] != "None" || row["top"] != 10 || row["num_pkranges"] != 2 || row["client_merge"] != true {
+		t.Fatalf("%s failed: received %#v", testName, row)
+	}
+
+	row = _explainQueryPlan(queryPlan, 1)
+	if row["client_merge"] != false {
+		t.Fatalf("%s failed: expected no client merge for a single partition key range", testName)
+	}
+
+	queryPlan = &RespQueryPlan{}
+	queryPlan.QueryInfo.Aggregates = []string{"Count"}
+	queryPlan.QueryInfo.GroupByExpressions = []string{"c.a"}
+	queryPlan.QueryInfo.GroupByAliasToAggregateType = map[string]string{"a": "", "n": "Sum"}
+	row = _explainQueryPlan(queryPlan, 3)
+	if expected := []string{"Count", "Sum"}; !reflect.DeepEqual(row["aggregates"], expected) {
+		t.Fatalf("%s failed: expected aggregates %#v but received %#v", testName, expected, row["aggregates"])
+	}
+	if row["distinct_type"] != "None" || row["client_merge"] != true {
+		t.Fatalf("%s failed: received %#v", testName, row)
+	}
+}
+
+func Test_countQueriedPkranges(t *testing.T) {
+	testName := "Test_countQueriedPkranges"
+	pkranges := []PkrangeInfo{{Id: "0", MinInclusive: "", MaxExclusive: "55"}, {Id: "1", MinInclusive: "55", MaxExclusive: "AA"},
+		{Id: "2", MinInclusive: "AA", MaxExclusive: "FF"}}
+	testData := []struct {
+		name        string
+		queryRanges []QueryRange
+		expected    int
+	}{
+		{name: "no_ranges", expected: 3},
+		{name: "full_range", queryRanges: []QueryRange{{Min: "", Max: "FF", IsMinInclusive: true}}, expected: 3},
+		{name: "single_pk", queryRanges: []QueryRange{{Min: "6A1F", Max: "6A1F", IsMinInclusive: true, IsMaxInclusive: true}}, expected: 1},
+		{name: "range_boundary", queryRanges: []QueryRange{{Min: "55", Max: "55", IsMinInclusive: true, IsMaxInclusive: true}}, expected: 1},
+		{name: "max_exclusive", queryRanges: []QueryRange{{Min: "10", Max: "55", IsMinInclusive: true}}, expected: 1},
+		{name: "lower_case", queryRanges: []QueryRange{{Min: "0b", Max: "0b", IsMinInclusive: true, IsMaxInclusive: true},
+			{Min: "c0", Max: "c0", IsMinInclusive: true, IsMaxInclusive: true}}, expected: 2},
+	}
+	for _, testCase := range testData {
+		if count := _countQueriedPkranges(testCase.queryRanges, pkranges); count != testCase.expected {
+			t.Fatalf("%s failed: expected %d but received %d", testName+"/"+testCase.name, testCase.expected, count)
+		}
+	}
+}
+
+func Test_execConcurrently(t *testing.T) {
+	testName := "Test_execConcurrently"
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	affectedRows, err := _execConcurrently(20, 0, func(i int) (int64, error) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return 1, nil
+	})
+	if err != nil || affectedRows != 20 {
+		t.Fatalf("%s failed: expected 20 affected rows but received %d / %s", testName, affectedRows, err)
+	}
+	if maxRunning < 2 || maxRunning > defaultConcurrency {
+		t.Fatalf("%s failed: expected at most %d items processed in parallel but received %d", testName, defaultConcurrency, maxRunning)
+	}
+
+	affectedRows, err = _execConcurrently(20, 1, func(i int) (int64, error) {
+		if i == 5 {
+			return 0, errors.New("error")
+		}
+		return 1, nil
+	})
+	if err == nil || affectedRows != 5 {
+		t.Fatalf("%s failed: expected 5 affected rows and error but received %d / %s", testName, affectedRows, err)
+	}
+}
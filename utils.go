@@ -29,19 +29,30 @@ const (
 	restApiHeaderMigrateToAutopilotThroughput   = "x-ms-cosmos-migrate-offer-to-autopilot"
 	restApiHeaderSupportedQueryFeatures         = "x-ms-cosmos-supported-query-features"
 	restApiHeaderPopulateMetrics                = "x-ms-documentdb-populatequerymetrics"
+	restApiHeaderPopulateIndexMetrics           = "x-ms-cosmos-populateindexmetrics"
+	restApiHeaderPopulateQuotaInfo              = "x-ms-documentdb-populatequotainfo"
 	restApiHeaderIncremental                    = "A-IM"
+	restApiHeaderPreTriggerInclude              = "x-ms-documentdb-pre-trigger-include"
+	restApiHeaderPostTriggerInclude             = "x-ms-documentdb-post-trigger-include"
 
-	restApiParamIndexingPolicy  = "indexingPolicy"
-	restApiParamUniqueKeyPolicy = "uniqueKeyPolicy"
-	restApiParamPartitionKey    = "partitionKey"
-	restApiParamQuery           = "query"
-	restApiParamParameters      = "parameters"
-	restApiParamContent         = "content"
+	restApiParamIndexingPolicy           = "indexingPolicy"
+	restApiParamUniqueKeyPolicy          = "uniqueKeyPolicy"
+	restApiParamConflictResolutionPolicy = "conflictResolutionPolicy"
+	restApiParamDefaultTtl               = "defaultTtl"
+	restApiParamPartitionKey             = "partitionKey"
+	restApiParamQuery                    = "query"
+	restApiParamParameters               = "parameters"
+	restApiParamContent                  = "content"
 
 	respHeaderRequestCharge = "X-MS-REQUEST-CHARGE"
 	respHeaderSessionToken  = "X-MS-SESSION-TOKEN"
 	respHeaderContinuation  = "X-MS-CONTINUATION"
 	respHeaderEtag          = "ETAG"
+	respHeaderSubStatus     = "X-MS-SUBSTATUS"
+	respHeaderQueryMetrics  = "X-MS-DOCUMENTDB-QUERY-METRICS"
+	respHeaderIndexMetrics  = "X-MS-COSMOS-INDEX-UTILIZATION"
+
+	respHeaderIndexTransformationProgress = "X-MS-DOCUMENTDB-COLLECTION-INDEX-TRANSFORMATION-PROGRESS"
 
 	docFieldId = "id"
 )